@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/atakanatali/contextify/internal/api"
+	"github.com/atakanatali/contextify/internal/auth"
 	"github.com/atakanatali/contextify/internal/config"
 	"github.com/atakanatali/contextify/internal/db"
 	"github.com/atakanatali/contextify/internal/embedding"
@@ -18,6 +19,7 @@ import (
 	"github.com/atakanatali/contextify/internal/memory"
 	"github.com/atakanatali/contextify/internal/scheduler"
 	"github.com/atakanatali/contextify/internal/steward"
+	"github.com/atakanatali/contextify/internal/steward/llm"
 )
 
 var version = "dev" // set via ldflags at build time
@@ -51,8 +53,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize embedding client
-	embedClient := embedding.NewClient(cfg.Embedding)
+	// Initialize embedding provider
+	embedClient, err := embedding.New(cfg.Embedding)
+	if err != nil {
+		slog.Error("failed to initialize embedding provider", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("embedding provider", "provider", cfg.Embedding.Provider, "model", embedClient.Model(), "dimensions", embedClient.Dimensions())
 
 	// Ensure embedding model is available
 	if err := embedClient.EnsureModel(ctx); err != nil {
@@ -63,9 +70,26 @@ func main() {
 	// Initialize memory service
 	repo := memory.NewRepository(pool)
 	svc := memory.NewService(repo, embedClient, cfg.Memory, cfg.Search)
+	rerankURL := cfg.Search.Rerank.OllamaURL
+	if rerankURL == "" {
+		rerankURL = cfg.Embedding.OllamaURL
+	}
+	svc.SetReranker(memory.NewLLMReranker(llm.NewClient(rerankURL, cfg.Search.Rerank.Model)))
+	svc.SetChunking(cfg.Embedding.Chunking)
+
+	// Reconcile the configured embedding model with stored vectors
+	if err := svc.InitEmbeddings(ctx, cfg.Embedding.Provider, cfg.Embedding.Reembed, func(provider, model string, dimensions int) (embedding.Embedder, error) {
+		return embedding.NewWithModel(cfg.Embedding, provider, model, dimensions)
+	}); err != nil {
+		slog.Error("failed to initialize embedding state", "error", err)
+		os.Exit(1)
+	}
+
+	// Apply search cache invalidations published by other instances
+	go svc.ListenForCacheInvalidations(ctx)
 
 	// Steward bootstrap wiring (runtime implementation is added incrementally in STW04+)
-	stewardMgr := steward.NewManager(pool, svc, cfg.Steward, cfg.Embedding.OllamaURL)
+	stewardMgr := steward.NewManager(pool, svc, embedClient, cfg.Steward, cfg.Embedding.OllamaURL)
 	slog.Info("steward config",
 		"enabled", cfg.Steward.Enabled,
 		"dry_run", cfg.Steward.DryRun,
@@ -80,30 +104,48 @@ func main() {
 	stewardMgr.Start()
 	defer stewardMgr.Stop()
 
-	// Start TTL cleanup scheduler
-	cleanup := scheduler.NewCleanup(svc, cfg.Memory.CleanupInterval)
-	cleanup.Start()
-	defer cleanup.Stop()
-
-	// Start dedup scanner (if consolidation enabled)
+	// Start the job scheduler: TTL cleanup, re-embedding (resumes
+	// migrations, builds chunk embeddings), the dedup scanner (if
+	// consolidation enabled) and the project_id normalizer run on the
+	// instance holding the scheduler lock
+	jobs := []scheduler.Job{
+		scheduler.CleanupJob(svc, cfg.Memory.CleanupInterval),
+		scheduler.ReembedJob(svc, cfg.Embedding.Reembed.Interval),
+	}
 	if cfg.Memory.Consolidation.Enabled {
-		dedupScanner := scheduler.NewDedupScanner(svc, cfg.Memory.Consolidation.ScanInterval)
-		dedupScanner.Start()
-		defer dedupScanner.Stop()
+		jobs = append(jobs, scheduler.DedupScanJob(svc, cfg.Memory.Consolidation.ScanInterval))
 	}
-
-	// Start project_id normalizer background job
 	if cfg.Memory.NormalizeProjectID {
-		normalizerJob := scheduler.NewProjectNormalizerJob(svc, 1*time.Hour)
-		normalizerJob.Start()
-		defer normalizerJob.Stop()
+		jobs = append(jobs, scheduler.ProjectNormalizerJob(svc, 1*time.Hour))
+	}
+	sched := scheduler.New(pool, cfg.Scheduler)
+	for _, job := range jobs {
+		if err := sched.Register(job); err != nil {
+			slog.Error("failed to register scheduled job", "error", err)
+			os.Exit(1)
+		}
+	}
+	sched.Start()
+	defer sched.Stop()
+
+	// Follow the leader's embedding model cutover on every instance
+	embedderSync := scheduler.NewEmbedderSync(svc, cfg.Embedding.Reembed.Interval)
+	embedderSync.Start()
+	defer embedderSync.Stop()
+
+	// API token authentication
+	authSvc := auth.NewService(auth.NewRepository(pool), cfg.Auth)
+	if cfg.Auth.Enabled {
+		slog.Info("authentication enabled", "bootstrap_token", cfg.Auth.BootstrapToken != "")
+	} else {
+		slog.Warn("authentication disabled; /api/v1 and /mcp accept any caller")
 	}
 
 	// Create MCP server
-	mcpServer := mcp.NewServer(svc)
+	mcpServer := mcp.NewServer(svc, authSvc)
 
 	// Create REST API router
-	apiRouter := api.NewRouter(svc, stewardMgr)
+	apiRouter := api.NewRouter(svc, stewardMgr, authSvc, sched)
 
 	// Combined HTTP server
 	mux := http.NewServeMux()
@@ -3,25 +3,32 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
+	"github.com/atakanatali/contextify/internal/auth"
 	"github.com/atakanatali/contextify/internal/memory"
+	"github.com/atakanatali/contextify/internal/scheduler"
 	"github.com/atakanatali/contextify/internal/steward"
 )
 
 type Handlers struct {
 	svc        *memory.Service
 	stewardMgr *steward.Manager
+	authSvc    *auth.Service
+	sched      *scheduler.Scheduler
 }
 
-func NewHandlers(svc *memory.Service, stewardMgr *steward.Manager) *Handlers {
-	return &Handlers{svc: svc, stewardMgr: stewardMgr}
+func NewHandlers(svc *memory.Service, stewardMgr *steward.Manager, authSvc *auth.Service, sched *scheduler.Scheduler) *Handlers {
+	return &Handlers{svc: svc, stewardMgr: stewardMgr, authSvc: authSvc, sched: sched}
 }
 
 // POST /api/v1/memories
@@ -39,6 +46,10 @@ func (h *Handlers) StoreMemory(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.svc.Store(r.Context(), req)
 	if err != nil {
+		if errors.Is(err, memory.ErrInvalidLanguage) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -87,6 +98,10 @@ func (h *Handlers) UpdateMemory(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, "memory not found")
 			return
 		}
+		if errors.Is(err, memory.ErrInvalidLanguage) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -114,6 +129,81 @@ func (h *Handlers) DeleteMemory(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted", "id": id.String()})
 }
 
+// GET /api/v1/trash
+func (h *Handlers) ListTrash(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := memory.TrashFilter{Reason: q.Get("reason")}
+	if v := q.Get("project_id"); v != "" {
+		f.ProjectID = &v
+	}
+	limit := 20
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	page, err := h.svc.ListTrash(r.Context(), f, limit, q.Get("cursor"))
+	if err != nil {
+		if errors.Is(err, memory.ErrInvalidCursor) || errors.Is(err, memory.ErrInvalidTrashReason) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// POST /api/v1/trash/{id}/restore
+func (h *Handlers) RestoreFromTrash(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+
+	mem, err := h.svc.RestoreFromTrash(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, memory.ErrNotInTrash) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, mem)
+}
+
+// DELETE /api/v1/trash/{id}
+func (h *Handlers) PurgeTrash(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+
+	if err := h.svc.PurgeTrash(r.Context(), id); err != nil {
+		if errors.Is(err, memory.ErrNotInTrash) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "purged", "id": id.String()})
+}
+
+// DELETE /api/v1/trash
+func (h *Handlers) EmptyTrash(w http.ResponseWriter, r *http.Request) {
+	count, err := h.svc.EmptyTrash(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "purged", "count": count})
+}
+
 // POST /api/v1/memories/search
 func (h *Handlers) SearchMemories(w http.ResponseWriter, r *http.Request) {
 	var req memory.SearchRequest
@@ -123,19 +213,41 @@ func (h *Handlers) SearchMemories(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Query == "" {
-		writeError(w, http.StatusBadRequest, "query is required")
+		if strings.TrimSpace(req.Filter) == "" {
+			writeError(w, http.StatusBadRequest, "query or filter is required")
+			return
+		}
+		// A filter on its own lists every matching memory.
+		req.Query = "*"
+	}
+
+	if req.Paginate || req.Cursor != "" {
+		page, err := h.svc.SearchPage(r.Context(), req)
+		if err != nil {
+			writeSearchError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, page)
 		return
 	}
 
 	results, err := h.svc.Search(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeSearchError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, results)
 }
 
+func writeSearchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, memory.ErrInvalidSearch) || errors.Is(err, memory.ErrInvalidCursor) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
 // POST /api/v1/memories/recall
 func (h *Handlers) RecallMemories(w http.ResponseWriter, r *http.Request) {
 	var req memory.SearchRequest
@@ -151,7 +263,7 @@ func (h *Handlers) RecallMemories(w http.ResponseWriter, r *http.Request) {
 
 	results, err := h.svc.Search(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeSearchError(w, err)
 		return
 	}
 
@@ -168,13 +280,122 @@ func (h *Handlers) CreateRelationship(w http.ResponseWriter, r *http.Request) {
 
 	rel, err := h.svc.CreateRelationship(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeRelationshipError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, rel)
 }
 
+// GET /api/v1/relationships
+func (h *Handlers) ListRelationships(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := memory.RelationshipFilter{Types: splitList(q.Get("types"))}
+	if v := q.Get("project_id"); v != "" {
+		f.ProjectID = &v
+	}
+	if v := q.Get("memory_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid memory_id")
+			return
+		}
+		f.MemoryID = &id
+	}
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			f.Limit = n
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			f.Offset = n
+		}
+	}
+
+	list, err := h.svc.ListRelationships(r.Context(), f)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// GET /api/v1/relationships/types
+func (h *Handlers) ListRelationshipTypes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, memory.ListRelationshipTypes())
+}
+
+// GET /api/v1/relationships/{id}
+func (h *Handlers) GetRelationship(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid relationship id")
+		return
+	}
+
+	rel, err := h.svc.GetRelationship(r.Context(), id)
+	if err != nil {
+		writeRelationshipError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rel)
+}
+
+// PUT /api/v1/relationships/{id}
+func (h *Handlers) UpdateRelationship(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid relationship id")
+		return
+	}
+
+	var req memory.RelationshipUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	rel, err := h.svc.UpdateRelationship(r.Context(), id, req)
+	if err != nil {
+		writeRelationshipError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rel)
+}
+
+// DELETE /api/v1/relationships/{id}
+func (h *Handlers) DeleteRelationship(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid relationship id")
+		return
+	}
+
+	if err := h.svc.DeleteRelationship(r.Context(), id); err != nil {
+		writeRelationshipError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted", "id": id.String()})
+}
+
+func writeRelationshipError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, memory.ErrMemoryNotFound), errors.Is(err, memory.ErrRelationshipNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, memory.ErrUnknownRelationshipType), errors.Is(err, memory.ErrInvalidRelationship):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, memory.ErrRelationshipExists):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 // GET /api/v1/memories/{id}/related
 func (h *Handlers) GetRelatedMemories(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -195,6 +416,178 @@ func (h *Handlers) GetRelatedMemories(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GET /api/v1/memories/{id}/graph
+func (h *Handlers) GetMemoryGraph(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+
+	q := r.URL.Query()
+	req := memory.GraphRequest{
+		RelationshipTypes: splitList(q.Get("types")),
+		Direction:         memory.GraphDirection(q.Get("direction")),
+	}
+	if req.Direction != "" && !memory.ValidDirections[req.Direction] {
+		writeError(w, http.StatusBadRequest, "direction must be 'outgoing', 'incoming' or 'both'")
+		return
+	}
+	if v := q.Get("depth"); v != "" {
+		if req.Depth, err = strconv.Atoi(v); err != nil || req.Depth <= 0 {
+			writeError(w, http.StatusBadRequest, "depth must be a positive integer")
+			return
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if req.Limit, err = strconv.Atoi(v); err != nil || req.Limit <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+	}
+	if v := q.Get("min_strength"); v != "" {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil || f < 0 || f > 1 {
+			writeError(w, http.StatusBadRequest, "min_strength must be between 0 and 1")
+			return
+		}
+		req.MinStrength = float32(f)
+	}
+
+	if v := q.Get("to"); v != "" {
+		toID, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid to memory id")
+			return
+		}
+		path, err := h.svc.ShortestPath(r.Context(), id, toID, req)
+		if err != nil {
+			if errors.Is(err, memory.ErrMemoryNotFound) || errors.Is(err, memory.ErrNoGraphPath) {
+				writeError(w, http.StatusNotFound, err.Error())
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, path)
+		return
+	}
+
+	graph, err := h.svc.TraverseGraph(r.Context(), id, req)
+	if err != nil {
+		if errors.Is(err, memory.ErrMemoryNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if q.Get("format") == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(graph.DOT()))
+		return
+	}
+	writeJSON(w, http.StatusOK, graph)
+}
+
+// GET /api/v1/memories/{id}/versions
+func (h *Handlers) GetMemoryVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+	limit := 20
+	offset := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	versions, total, err := h.svc.ListVersions(r.Context(), id, limit, offset)
+	if err != nil {
+		if errors.Is(err, memory.ErrMemoryNotFound) {
+			writeError(w, http.StatusNotFound, "memory not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"versions": versions,
+		"total":    total,
+	})
+}
+
+// GET /api/v1/memories/{id}/versions/diff?from=N&to=M
+func (h *Handlers) DiffMemoryVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+	var from, to int
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = strconv.Atoi(v); err != nil || from <= 0 {
+			writeError(w, http.StatusBadRequest, "from must be a positive version number")
+			return
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = strconv.Atoi(v); err != nil || to <= 0 {
+			writeError(w, http.StatusBadRequest, "to must be a positive version number")
+			return
+		}
+	}
+
+	diff, err := h.svc.DiffVersions(r.Context(), id, from, to)
+	if err != nil {
+		if errors.Is(err, memory.ErrMemoryNotFound) || errors.Is(err, memory.ErrVersionNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diff)
+}
+
+// POST /api/v1/memories/{id}/restore/{version}
+func (h *Handlers) RestoreMemoryVersion(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	mem, err := h.svc.RestoreVersion(r.Context(), id, version)
+	if err != nil {
+		if errors.Is(err, memory.ErrMemoryNotFound) || errors.Is(err, memory.ErrVersionNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mem)
+}
+
 // GET /api/v1/stats
 func (h *Handlers) GetStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.svc.GetStats(r.Context())
@@ -243,6 +636,73 @@ func (h *Handlers) PromoteMemory(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "promoted", "id": id.String()})
 }
 
+// GET /api/v1/memories/expiring
+func (h *Handlers) ListExpiring(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var f memory.ExpiringFilter
+	if v := q.Get("project_id"); v != "" {
+		f.ProjectID = &v
+	}
+	if v := q.Get("within"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid within: must be a positive duration such as 24h")
+			return
+		}
+		f.Within = d
+	}
+	limit := 20
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	page, err := h.svc.ListExpiring(r.Context(), f, limit, q.Get("cursor"))
+	if err != nil {
+		if errors.Is(err, memory.ErrInvalidCursor) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// POST /api/v1/memories/{id}/review
+func (h *Handlers) ReviewExpiring(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	review, err := h.svc.ReviewExpiring(r.Context(), id, req.Action)
+	if err != nil {
+		switch {
+		case errors.Is(err, memory.ErrInvalidReviewAction):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, memory.ErrMemoryNotFound):
+			writeError(w, http.StatusNotFound, "memory not found")
+		case errors.Is(err, memory.ErrNotExpiring):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, review)
+}
+
 // GET /api/v1/analytics
 func (h *Handlers) GetAnalytics(w http.ResponseWriter, r *http.Request) {
 	data, err := h.svc.GetAnalytics(r.Context())
@@ -425,16 +885,36 @@ func (h *Handlers) GetSuggestions(w http.ResponseWriter, r *http.Request) {
 		projectPtr = &projectID
 	}
 
-	suggestions, total, err := h.svc.GetSuggestions(r.Context(), projectPtr, status, limit, offset)
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" && offset > 0 {
+		writeError(w, http.StatusBadRequest, "offset cannot be combined with cursor")
+		return
+	}
+	// Offset paging is kept for existing callers; otherwise pages are keyed
+	// by cursor.
+	if offset > 0 {
+		suggestions, total, err := h.svc.GetSuggestions(r.Context(), projectPtr, status, limit, offset)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"suggestions": suggestions,
+			"total":       total,
+		})
+		return
+	}
+
+	page, err := h.svc.GetSuggestionsPage(r.Context(), projectPtr, status, limit, cursor)
 	if err != nil {
+		if errors.Is(err, memory.ErrInvalidCursor) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-
-	writeJSON(w, http.StatusOK, map[string]any{
-		"suggestions": suggestions,
-		"total":       total,
-	})
+	writeJSON(w, http.StatusOK, page)
 }
 
 // PUT /api/v1/consolidation/suggestions/{id}
@@ -495,6 +975,128 @@ func (h *Handlers) GetConsolidationLog(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, logs)
 }
 
+// POST /api/v1/consolidation/log/{id}/revert
+func (h *Handlers) RevertConsolidation(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid consolidation log id")
+		return
+	}
+
+	entry, err := h.svc.RevertConsolidation(r.Context(), id, "api")
+	if err != nil {
+		switch {
+		case errors.Is(err, memory.ErrConsolidationNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, memory.ErrConsolidationAlreadyReverted), errors.Is(err, memory.ErrConsolidationNotRevertible):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// GET /api/v1/export
+func (h *Handlers) ExportMemories(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var f memory.ExportFilter
+	if v := q.Get("project_id"); v != "" {
+		f.ProjectID = &v
+	}
+	for _, t := range splitList(q.Get("type")) {
+		if !memory.ValidTypes[memory.MemoryType(t)] {
+			writeError(w, http.StatusBadRequest, "unknown memory type: "+t)
+			return
+		}
+		f.Types = append(f.Types, memory.MemoryType(t))
+	}
+	f.Tags = splitList(q.Get("tags"))
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &f.From}, {"to", &f.To}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := parseDateParam(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, p.name+" must be YYYY-MM-DD or RFC 3339")
+			return
+		}
+		*p.dst = &t
+	}
+	f.IncludeEmbeddings = q.Get("include_embeddings") == "true"
+
+	// Large exports outlive the server's write timeout.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="contextify-export-%s.jsonl"`, time.Now().UTC().Format("20060102-150405")))
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so a failure can only truncate the stream.
+	stats, err := h.svc.Export(r.Context(), w, f)
+	if err != nil {
+		slog.Error("export failed", "error", err)
+		return
+	}
+	slog.Info("exported memories",
+		"memories", stats.Memories,
+		"relationships", stats.Relationships,
+		"consolidations", stats.Consolidations,
+	)
+}
+
+// POST /api/v1/import
+func (h *Handlers) ImportMemories(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := memory.ImportOptions{
+		OnConflict: memory.ConflictMode(q.Get("on_conflict")),
+		Reembed:    q.Get("reembed") == "true",
+		Dedup:      q.Get("dedup") == "true",
+	}
+	if opts.OnConflict != "" && !memory.ValidConflictModes[opts.OnConflict] {
+		writeError(w, http.StatusBadRequest, "on_conflict must be 'skip', 'overwrite' or 'remap'")
+		return
+	}
+
+	http.NewResponseController(w).SetReadDeadline(time.Time{})
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	result, err := h.svc.Import(r.Context(), r.Body, opts)
+	if err != nil {
+		if errors.Is(err, memory.ErrInvalidArchive) {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error(), "result": result})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// splitList parses a comma-separated query parameter, dropping empty items.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// parseDateParam accepts a date (midnight UTC) or an RFC 3339 timestamp.
+func parseDateParam(v string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
 // POST /api/v1/admin/normalize-projects
 func (h *Handlers) NormalizeProjects(w http.ResponseWriter, r *http.Request) {
 	updated, err := h.svc.NormalizeAllProjectIDs(r.Context())
@@ -509,6 +1111,188 @@ func (h *Handlers) NormalizeProjects(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GET /api/v1/admin/retention
+func (h *Handlers) GetRetentionRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.svc.RetentionRules(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// PUT /api/v1/admin/retention
+func (h *Handlers) SetRetentionRules(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Rules []memory.RetentionRule `json:"rules"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	rules, err := h.svc.SetRetentionRules(r.Context(), req.Rules)
+	if err != nil {
+		if errors.Is(err, memory.ErrInvalidRetentionRule) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// GET /api/v1/admin/reembed
+func (h *Handlers) GetReembedStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.svc.ReembedStatus(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// POST /api/v1/admin/reembed
+func (h *Handlers) StartReembed(w http.ResponseWriter, r *http.Request) {
+	migration, err := h.svc.StartReembed(r.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, memory.ErrReembedNotNeeded), errors.Is(err, memory.ErrReembedInProgress):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	// Start the migration now rather than at the job's next sweep.
+	if err := h.sched.Trigger(r.Context(), scheduler.ReembedJobName); err != nil {
+		slog.Warn("failed to trigger re-embed job", "error", err)
+	}
+	writeJSON(w, http.StatusAccepted, migration)
+}
+
+// POST /api/v1/admin/reembed/pause
+func (h *Handlers) PauseReembed(w http.ResponseWriter, r *http.Request) {
+	migration, err := h.svc.PauseReembed(r.Context())
+	if err != nil {
+		if errors.Is(err, memory.ErrReembedNotActive) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, migration)
+}
+
+// GET /api/v1/admin/schedules
+func (h *Handlers) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	status, err := h.sched.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// POST /api/v1/admin/schedules
+func (h *Handlers) ControlSchedule(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name   string `json:"name"`
+		Action string `json:"action"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	var err error
+	code := http.StatusOK
+	switch req.Action {
+	case "pause":
+		err = h.sched.Pause(r.Context(), req.Name, true)
+	case "resume":
+		err = h.sched.Pause(r.Context(), req.Name, false)
+	case "trigger":
+		err = h.sched.Trigger(r.Context(), req.Name)
+		code = http.StatusAccepted
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid action %q: must be pause, resume or trigger", req.Action))
+		return
+	}
+	if err != nil {
+		if errors.Is(err, scheduler.ErrUnknownJob) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	status, err := h.sched.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, job := range status.Jobs {
+		if job.Name == req.Name {
+			writeJSON(w, code, job)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "scheduled job not found")
+}
+
+// GET /api/v1/admin/tokens
+func (h *Handlers) ListTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.authSvc.List(r.Context(), r.URL.Query().Get("include_revoked") == "true")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tokens": tokens})
+}
+
+// POST /api/v1/admin/tokens
+func (h *Handlers) CreateToken(w http.ResponseWriter, r *http.Request) {
+	var req auth.CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.authSvc.Create(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, auth.ErrWorkspaceForbidden) || errors.Is(err, auth.ErrScopeForbidden) {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, result)
+}
+
+// DELETE /api/v1/admin/tokens/{id}
+func (h *Handlers) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	if err := h.authSvc.Revoke(r.Context(), id); err != nil {
+		if errors.Is(err, auth.ErrTokenNotFound) {
+			writeError(w, http.StatusNotFound, "token not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "revoked", "id": id.String()})
+}
+
 type stewardModeRequest struct {
 	Paused bool `json:"paused"`
 	DryRun bool `json:"dry_run"`
@@ -583,12 +1367,37 @@ func (h *Handlers) GetStewardRuns(w http.ResponseWriter, r *http.Request) {
 		}
 		f.Offset = n
 	}
-	runs, err := h.stewardMgr.ListRuns(r.Context(), f)
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" && f.Offset > 0 {
+		writeError(w, http.StatusBadRequest, "offset cannot be combined with cursor")
+		return
+	}
+	if f.Offset > 0 {
+		runs, err := h.stewardMgr.ListRuns(r.Context(), f)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"runs": runs, "limit": f.Limit, "offset": f.Offset})
+		return
+	}
+	page, err := h.stewardMgr.ListRunsPage(r.Context(), f, cursor, r.URL.Query().Get("include_total") == "true")
 	if err != nil {
+		if errors.Is(err, memory.ErrInvalidCursor) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"runs": runs, "limit": f.Limit, "offset": f.Offset})
+	resp := map[string]any{"runs": page.Runs, "limit": f.Limit, "offset": f.Offset}
+	if page.NextCursor != "" {
+		resp["next_cursor"] = page.NextCursor
+	}
+	if page.Total != nil {
+		resp["total"] = *page.Total
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func (h *Handlers) GetStewardJobEvents(w http.ResponseWriter, r *http.Request) {
@@ -2,11 +2,15 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/auth"
+	"github.com/atakanatali/contextify/internal/memory"
 )
 
 func requestIDMiddleware(next http.Handler) http.Handler {
@@ -50,3 +54,48 @@ func (w *statusWriter) WriteHeader(code int) {
 	w.status = code
 	w.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// authMiddleware resolves the bearer token when authentication is enabled
+// and scopes the request to the token's workspace. Scopes are enforced per
+// route by requireScope.
+func authMiddleware(authSvc *auth.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authSvc == nil || !authSvc.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			tok, err := authSvc.Authenticate(r.Context(), auth.BearerToken(r))
+			if err != nil {
+				if errors.Is(err, auth.ErrInvalidToken) {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="contextify"`)
+					writeError(w, http.StatusUnauthorized, "valid bearer token required")
+					return
+				}
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			ctx := memory.WithWorkspace(auth.WithToken(r.Context(), tok), tok.Workspace)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requireScope rejects tokens below the given scope. Without a token in the
+// context (authentication disabled) every request passes.
+func requireScope(scope auth.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tok := auth.FromContext(r.Context()); tok != nil && !tok.Scope.Allows(scope) {
+				writeError(w, http.StatusForbidden, "token scope "+string(tok.Scope)+" does not allow this operation; requires "+string(scope))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -9,19 +9,21 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 
+	"github.com/atakanatali/contextify/internal/auth"
 	"github.com/atakanatali/contextify/internal/memory"
+	"github.com/atakanatali/contextify/internal/scheduler"
 	"github.com/atakanatali/contextify/internal/steward"
 )
 
-func NewRouter(svc *memory.Service, stewardMgr *steward.Manager) *chi.Mux {
-	h := NewHandlers(svc, stewardMgr)
+func NewRouter(svc *memory.Service, stewardMgr *steward.Manager, authSvc *auth.Service, sched *scheduler.Scheduler) *chi.Mux {
+	h := NewHandlers(svc, stewardMgr, authSvc, sched)
 
 	r := chi.NewRouter()
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
 		ExposedHeaders:   []string{"X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           300,
@@ -32,59 +34,104 @@ func NewRouter(svc *memory.Service, stewardMgr *steward.Manager) *chi.Mux {
 	r.Get("/health", h.Health)
 
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(authMiddleware(authSvc))
+		read := requireScope(auth.ScopeRead)
+		write := requireScope(auth.ScopeWrite)
+		admin := requireScope(auth.ScopeAdmin)
+		// instance guards operations that act on every workspace.
+		instance := requireScope(auth.ScopeInstance)
+
 		// Memories CRUD
-		r.Post("/memories", h.StoreMemory)
-		r.Get("/memories/{id}", h.GetMemory)
-		r.Put("/memories/{id}", h.UpdateMemory)
-		r.Delete("/memories/{id}", h.DeleteMemory)
+		r.With(write).Post("/memories", h.StoreMemory)
+		r.With(read).Get("/memories/{id}", h.GetMemory)
+		r.With(write).Put("/memories/{id}", h.UpdateMemory)
+		r.With(admin).Delete("/memories/{id}", h.DeleteMemory)
+
+		// Trash
+		r.With(read).Get("/trash", h.ListTrash)
+		r.With(write).Post("/trash/{id}/restore", h.RestoreFromTrash)
+		r.With(admin).Delete("/trash/{id}", h.PurgeTrash)
+		r.With(admin).Delete("/trash", h.EmptyTrash)
 
 		// Search
-		r.Post("/memories/search", h.SearchMemories)
-		r.Post("/memories/recall", h.RecallMemories)
+		r.With(read).Post("/memories/search", h.SearchMemories)
+		r.With(read).Post("/memories/recall", h.RecallMemories)
 
 		// Promote
-		r.Post("/memories/{id}/promote", h.PromoteMemory)
+		r.With(write).Post("/memories/{id}/promote", h.PromoteMemory)
+
+		// Expiry review
+		r.With(read).Get("/memories/expiring", h.ListExpiring)
+		r.With(write).Post("/memories/{id}/review", h.ReviewExpiring)
 
 		// Merge
-		r.Post("/memories/{id}/merge", h.MergeMemories)
+		r.With(write).Post("/memories/{id}/merge", h.MergeMemories)
 
 		// Related
-		r.Get("/memories/{id}/related", h.GetRelatedMemories)
+		r.With(read).Get("/memories/{id}/related", h.GetRelatedMemories)
+		r.With(read).Get("/memories/{id}/graph", h.GetMemoryGraph)
+
+		// Version history
+		r.With(read).Get("/memories/{id}/versions", h.GetMemoryVersions)
+		r.With(read).Get("/memories/{id}/versions/diff", h.DiffMemoryVersions)
+		r.With(write).Post("/memories/{id}/restore/{version}", h.RestoreMemoryVersion)
 
 		// Duplicates & Consolidation
-		r.Get("/memories/duplicates", h.GetDuplicates)
-		r.Post("/memories/consolidate", h.BatchConsolidate)
+		r.With(read).Get("/memories/duplicates", h.GetDuplicates)
+		r.With(write).Post("/memories/consolidate", h.BatchConsolidate)
 
 		// Relationships
-		r.Post("/relationships", h.CreateRelationship)
+		r.With(read).Get("/relationships", h.ListRelationships)
+		r.With(write).Post("/relationships", h.CreateRelationship)
+		r.With(read).Get("/relationships/types", h.ListRelationshipTypes)
+		r.With(read).Get("/relationships/{id}", h.GetRelationship)
+		r.With(write).Put("/relationships/{id}", h.UpdateRelationship)
+		r.With(admin).Delete("/relationships/{id}", h.DeleteRelationship)
+
+		// Export & Import
+		r.With(read).Get("/export", h.ExportMemories)
+		r.With(write).Post("/import", h.ImportMemories)
 
 		// Stats & Analytics
-		r.Get("/stats", h.GetStats)
-		r.Get("/analytics", h.GetAnalytics)
-		r.Get("/analytics/funnel", h.GetFunnelAnalytics)
+		r.With(read).Get("/stats", h.GetStats)
+		r.With(read).Get("/analytics", h.GetAnalytics)
+		r.With(read).Get("/analytics/funnel", h.GetFunnelAnalytics)
 
 		// Context
-		r.Post("/context/{project}", h.GetContext)
+		r.With(read).Post("/context/{project}", h.GetContext)
 
 		// Consolidation
-		r.Get("/consolidation/suggestions", h.GetSuggestions)
-		r.Put("/consolidation/suggestions/{id}", h.UpdateSuggestion)
-		r.Get("/consolidation/log", h.GetConsolidationLog)
+		r.With(read).Get("/consolidation/suggestions", h.GetSuggestions)
+		r.With(write).Put("/consolidation/suggestions/{id}", h.UpdateSuggestion)
+		r.With(read).Get("/consolidation/log", h.GetConsolidationLog)
+		r.With(write).Post("/consolidation/log/{id}/revert", h.RevertConsolidation)
 
 		// Admin
-		r.Post("/admin/normalize-projects", h.NormalizeProjects)
+		r.With(instance).Post("/admin/normalize-projects", h.NormalizeProjects)
+		r.With(read).Get("/admin/reembed", h.GetReembedStatus)
+		r.With(instance).Post("/admin/reembed", h.StartReembed)
+		r.With(instance).Post("/admin/reembed/pause", h.PauseReembed)
+		r.With(admin).Get("/admin/retention", h.GetRetentionRules)
+		r.With(admin).Put("/admin/retention", h.SetRetentionRules)
+		r.With(instance).Get("/admin/schedules", h.ListSchedules)
+		r.With(instance).Post("/admin/schedules", h.ControlSchedule)
+
+		// API tokens
+		r.With(admin).Get("/admin/tokens", h.ListTokens)
+		r.With(admin).Post("/admin/tokens", h.CreateToken)
+		r.With(admin).Delete("/admin/tokens/{id}", h.RevokeToken)
 
 		// Steward
-		r.Get("/steward/status", h.GetStewardStatus)
-		r.Get("/steward/runs", h.GetStewardRuns)
-		r.Get("/steward/jobs/{id}/events", h.GetStewardJobEvents)
-		r.Get("/steward/metrics", h.GetStewardMetrics)
-		r.Get("/steward/policies/history", h.GetStewardPolicyHistory)
-		r.Post("/steward/run-once", h.StewardRunOnce)
-		r.Put("/steward/mode", h.UpdateStewardMode)
-		r.Post("/steward/jobs/{id}/retry", h.RetryStewardJob)
-		r.Post("/steward/jobs/{id}/cancel", h.CancelStewardJob)
-		r.Post("/steward/policies/rollback", h.RollbackStewardPolicy)
+		r.With(read).Get("/steward/status", h.GetStewardStatus)
+		r.With(read).Get("/steward/runs", h.GetStewardRuns)
+		r.With(read).Get("/steward/jobs/{id}/events", h.GetStewardJobEvents)
+		r.With(read).Get("/steward/metrics", h.GetStewardMetrics)
+		r.With(read).Get("/steward/policies/history", h.GetStewardPolicyHistory)
+		r.With(instance).Post("/steward/run-once", h.StewardRunOnce)
+		r.With(instance).Put("/steward/mode", h.UpdateStewardMode)
+		r.With(instance).Post("/steward/jobs/{id}/retry", h.RetryStewardJob)
+		r.With(instance).Post("/steward/jobs/{id}/cancel", h.CancelStewardJob)
+		r.With(instance).Post("/steward/policies/rollback", h.RollbackStewardPolicy)
 	})
 
 	// Serve embedded Web UI static files (SPA with fallback to index.html)
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+func TestScope_Allows(t *testing.T) {
+	cases := []struct {
+		have, need Scope
+		want       bool
+	}{
+		{ScopeRead, ScopeRead, true},
+		{ScopeRead, ScopeWrite, false},
+		{ScopeWrite, ScopeRead, true},
+		{ScopeWrite, ScopeAdmin, false},
+		{ScopeAdmin, ScopeWrite, true},
+		{ScopeAdmin, ScopeInstance, false},
+		{ScopeInstance, ScopeAdmin, true},
+		{Scope("bogus"), ScopeRead, false},
+	}
+	for _, c := range cases {
+		if got := c.have.Allows(c.need); got != c.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", c.have, c.need, got, c.want)
+		}
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	if s, err := ParseScope("read-only"); err != nil || s != ScopeRead {
+		t.Fatalf("expected read-only to map to read, got %q %v", s, err)
+	}
+	if _, err := ParseScope("root"); err == nil {
+		t.Fatalf("expected error for unknown scope")
+	}
+}
+
+func TestScope_Implied(t *testing.T) {
+	if got := strings.Join(ScopeWrite.Implied(), ","); got != "read,write" {
+		t.Fatalf("unexpected implied scopes: %s", got)
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	a, prefix, err := generateToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _, _ := generateToken()
+	if a == b {
+		t.Fatalf("expected distinct tokens")
+	}
+	if !strings.HasPrefix(a, tokenPrefix) || !strings.HasPrefix(a, prefix) || len(prefix) != len(tokenPrefix)+8 {
+		t.Fatalf("unexpected token %q / prefix %q", a, prefix)
+	}
+	if hashToken(a) == a || hashToken(a) != hashToken(a) {
+		t.Fatalf("expected stable, non-identity hash")
+	}
+}
+
+func TestAuthenticate_BootstrapToken(t *testing.T) {
+	s := NewService(nil, config.AuthConfig{Enabled: true, BootstrapToken: "bootstrap-secret-123"})
+	tok, err := s.Authenticate(context.Background(), "bootstrap-secret-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tok.Scope != ScopeInstance {
+		t.Fatalf("expected instance scope for bootstrap token, got %s", tok.Scope)
+	}
+	if !tok.Bootstrap() || tok.Workspace != "default" {
+		t.Fatalf("expected bootstrap token in default workspace, got %+v", tok)
+	}
+	if _, err := s.Authenticate(context.Background(), ""); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for empty token, got %v", err)
+	}
+}
+
+func TestCreate_RejectsOtherWorkspace(t *testing.T) {
+	s := NewService(nil, config.AuthConfig{Enabled: true})
+	caller := &Token{ID: uuid.New(), Name: "team-a-admin", Scope: ScopeAdmin, Workspace: "team-a"}
+	ctx := WithToken(context.Background(), caller)
+
+	_, err := s.Create(ctx, CreateRequest{Name: "x", Scope: "read", Workspace: "team-b"})
+	if !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Fatalf("expected ErrWorkspaceForbidden, got %v", err)
+	}
+	if _, err := s.Create(ctx, CreateRequest{Name: "x", Scope: "read", Workspace: "Team B"}); err == nil {
+		t.Fatalf("expected invalid workspace error")
+	}
+}
+
+func TestCreate_RejectsHigherScope(t *testing.T) {
+	s := NewService(nil, config.AuthConfig{Enabled: true})
+	caller := &Token{ID: uuid.New(), Name: "team-a-admin", Scope: ScopeAdmin, Workspace: "team-a"}
+	ctx := WithToken(context.Background(), caller)
+
+	_, err := s.Create(ctx, CreateRequest{Name: "x", Scope: "instance"})
+	if !errors.Is(err, ErrScopeForbidden) {
+		t.Fatalf("expected ErrScopeForbidden, got %v", err)
+	}
+}
+
+func TestManagedWorkspace(t *testing.T) {
+	if got := managedWorkspace(nil); got != "" {
+		t.Fatalf("auth disabled should manage all workspaces, got %q", got)
+	}
+	if got := managedWorkspace(&Token{Workspace: "default"}); got != "" {
+		t.Fatalf("bootstrap token should manage all workspaces, got %q", got)
+	}
+	if got := managedWorkspace(&Token{ID: uuid.New(), Workspace: "team-a"}); got != "team-a" {
+		t.Fatalf("expected team-a, got %q", got)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "bearer abc")
+	if got := BearerToken(r); got != "abc" {
+		t.Fatalf("expected abc, got %q", got)
+	}
+	r.Header.Set("Authorization", "Basic abc")
+	if got := BearerToken(r); got != "" {
+		t.Fatalf("expected empty for non-bearer auth, got %q", got)
+	}
+}
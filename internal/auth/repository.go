@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const tokenColumns = `id, name, prefix, scope, workspace_id, created_at, last_used_at, expires_at, revoked_at`
+
+func scanToken(row pgx.Row) (*Token, error) {
+	t := &Token{}
+	if err := row.Scan(&t.ID, &t.Name, &t.Prefix, &t.Scope, &t.Workspace, &t.CreatedAt, &t.LastUsedAt, &t.ExpiresAt, &t.RevokedAt); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *Repository) Create(ctx context.Context, t *Token, hash string) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO api_tokens (id, name, prefix, token_hash, scope, workspace_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`, t.ID, t.Name, t.Prefix, hash, t.Scope, t.Workspace, t.ExpiresAt).Scan(&t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create token: %w", err)
+	}
+	return nil
+}
+
+// GetActiveByHash returns the unrevoked, unexpired token with the given hash,
+// or nil.
+func (r *Repository) GetActiveByHash(ctx context.Context, hash string) (*Token, error) {
+	t, err := scanToken(r.pool.QueryRow(ctx, `
+		SELECT `+tokenColumns+`
+		FROM api_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	`, hash))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get token: %w", err)
+	}
+	return t, nil
+}
+
+// List returns tokens newest first; an empty workspace lists every workspace.
+func (r *Repository) List(ctx context.Context, workspace string, includeRevoked bool) ([]Token, error) {
+	query := `SELECT ` + tokenColumns + ` FROM api_tokens WHERE ($1 = '' OR workspace_id = $1)`
+	if !includeRevoked {
+		query += ` AND revoked_at IS NULL`
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, query, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("list tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []Token
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan token: %w", err)
+		}
+		tokens = append(tokens, *t)
+	}
+	return tokens, rows.Err()
+}
+
+// Revoke revokes a token; an empty workspace matches any workspace.
+func (r *Repository) Revoke(ctx context.Context, id uuid.UUID, workspace string) error {
+	result, err := r.pool.Exec(ctx,
+		"UPDATE api_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL AND ($2 = '' OR workspace_id = $2)",
+		id, workspace,
+	)
+	if err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrTokenNotFound, id)
+	}
+	return nil
+}
+
+func (r *Repository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "UPDATE api_tokens SET last_used_at = NOW() WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("touch token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/memory"
+)
+
+// lastUsedResolution bounds how often a token's last_used_at is written.
+const lastUsedResolution = time.Minute
+
+type Service struct {
+	repo          *Repository
+	enabled       bool
+	bootstrapHash string
+
+	mu      sync.Mutex
+	touched map[uuid.UUID]time.Time
+}
+
+func NewService(repo *Repository, cfg config.AuthConfig) *Service {
+	s := &Service{repo: repo, enabled: cfg.Enabled, touched: make(map[uuid.UUID]time.Time)}
+	if cfg.BootstrapToken != "" {
+		s.bootstrapHash = hashToken(cfg.BootstrapToken)
+	}
+	return s
+}
+
+// Enabled reports whether requests must carry a token.
+func (s *Service) Enabled() bool {
+	return s.enabled
+}
+
+// Authenticate resolves a raw bearer token. The bootstrap token, if
+// configured, authenticates as an instance token named "bootstrap" in the
+// default workspace.
+func (s *Service) Authenticate(ctx context.Context, raw string) (*Token, error) {
+	if raw == "" {
+		return nil, ErrInvalidToken
+	}
+	hash := hashToken(raw)
+	if s.bootstrapHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(s.bootstrapHash)) == 1 {
+		return &Token{Name: "bootstrap", Scope: ScopeInstance, Workspace: memory.DefaultWorkspace}, nil
+	}
+
+	t, err := s.repo.GetActiveByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrInvalidToken
+	}
+	s.touch(ctx, t.ID)
+	return t, nil
+}
+
+func (s *Service) touch(ctx context.Context, id uuid.UUID) {
+	s.mu.Lock()
+	if time.Since(s.touched[id]) < lastUsedResolution {
+		s.mu.Unlock()
+		return
+	}
+	s.touched[id] = time.Now()
+	s.mu.Unlock()
+
+	if err := s.repo.TouchLastUsed(ctx, id); err != nil {
+		slog.Warn("failed to record token use", "id", id, "error", err)
+	}
+}
+
+// Create issues a new token. The raw value is returned once and only its
+// hash is stored. Tokens are created in the caller's workspace unless the
+// caller is the bootstrap token.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (*CreateResult, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	scope, err := ParseScope(req.Scope)
+	if err != nil {
+		return nil, err
+	}
+	caller := FromContext(ctx)
+	if caller != nil && !caller.Scope.Allows(scope) {
+		return nil, fmt.Errorf("%w: %s", ErrScopeForbidden, scope)
+	}
+	workspace := strings.TrimSpace(req.Workspace)
+	if workspace == "" {
+		workspace = memory.DefaultWorkspace
+		if caller != nil {
+			workspace = caller.Workspace
+		}
+	}
+	if err := memory.ValidateWorkspace(workspace); err != nil {
+		return nil, err
+	}
+	if scoped := managedWorkspace(caller); scoped != "" && scoped != workspace {
+		return nil, fmt.Errorf("%w: %s", ErrWorkspaceForbidden, workspace)
+	}
+
+	t := &Token{ID: uuid.New(), Name: name, Scope: scope, Workspace: workspace}
+	if req.ExpiresIn != "" {
+		d, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid expires_in %q: must be a positive duration", req.ExpiresIn)
+		}
+		exp := time.Now().Add(d)
+		t.ExpiresAt = &exp
+	}
+
+	raw, prefix, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	t.Prefix = prefix
+	if err := s.repo.Create(ctx, t, hashToken(raw)); err != nil {
+		return nil, err
+	}
+	return &CreateResult{Token: t, RawToken: raw}, nil
+}
+
+func (s *Service) List(ctx context.Context, includeRevoked bool) ([]Token, error) {
+	return s.repo.List(ctx, managedWorkspace(FromContext(ctx)), includeRevoked)
+}
+
+func (s *Service) Revoke(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Revoke(ctx, id, managedWorkspace(FromContext(ctx)))
+}
+
+// managedWorkspace returns the only workspace caller may manage tokens in,
+// or "" for all of them (bootstrap token, or authentication disabled).
+func managedWorkspace(caller *Token) string {
+	if caller == nil || caller.Bootstrap() {
+		return ""
+	}
+	return caller.Workspace
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer" header.
+func BearerToken(r *http.Request) string {
+	fields := strings.Fields(r.Header.Get("Authorization"))
+	if len(fields) != 2 || !strings.EqualFold(fields[0], "bearer") {
+		return ""
+	}
+	return fields[1]
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope is the access level of a token. Each scope includes the ones below it.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	ScopeAdmin Scope = "admin"
+	// ScopeInstance allows operations that affect every workspace, such as
+	// re-embedding, project normalization and scheduler and steward control.
+	ScopeInstance Scope = "instance"
+)
+
+var scopeRank = map[Scope]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3, ScopeInstance: 4}
+
+// ParseScope validates a scope name. "read-only" is accepted for read.
+func ParseScope(s string) (Scope, error) {
+	if s == "read-only" {
+		return ScopeRead, nil
+	}
+	if _, ok := scopeRank[Scope(s)]; !ok {
+		return "", fmt.Errorf("invalid scope %q: must be one of read, write, admin, instance", s)
+	}
+	return Scope(s), nil
+}
+
+// Allows reports whether a token with scope s may perform an action that
+// requires scope required.
+func (s Scope) Allows(required Scope) bool {
+	return scopeRank[s] >= scopeRank[required]
+}
+
+// Implied returns s and every scope it includes, lowest first.
+func (s Scope) Implied() []string {
+	var out []string
+	for _, sc := range []Scope{ScopeRead, ScopeWrite, ScopeAdmin, ScopeInstance} {
+		if s.Allows(sc) {
+			out = append(out, string(sc))
+		}
+	}
+	return out
+}
+
+var (
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrWorkspaceForbidden is returned when a token manages tokens outside
+	// its own workspace. Only the bootstrap token spans workspaces.
+	ErrWorkspaceForbidden = errors.New("token cannot manage another workspace")
+
+	// ErrScopeForbidden is returned when a token creates a token with a
+	// scope it does not have itself.
+	ErrScopeForbidden = errors.New("token cannot grant a scope it does not have")
+)
+
+// tokenPrefix marks Contextify tokens so they are easy to spot in configs and
+// secret scanners.
+const tokenPrefix = "ctx_"
+
+// Token is a stored API token. The raw value is never persisted.
+type Token struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      Scope      `json:"scope"`
+	Workspace  string     `json:"workspace"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Bootstrap reports whether t is the configured bootstrap token rather than
+// a stored one.
+func (t *Token) Bootstrap() bool {
+	return t.ID == uuid.Nil
+}
+
+// CreateRequest is the API request for creating a token.
+type CreateRequest struct {
+	Name      string `json:"name"`
+	Scope     string `json:"scope"`
+	Workspace string `json:"workspace,omitempty"`  // defaults to the caller's workspace
+	ExpiresIn string `json:"expires_in,omitempty"` // Go duration, e.g. "720h"; empty for no expiry
+}
+
+// CreateResult carries the raw token, which is only available at creation.
+type CreateResult struct {
+	Token    *Token `json:"token"`
+	RawToken string `json:"raw_token"`
+}
+
+func generateToken() (raw, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generate token: %w", err)
+	}
+	raw = tokenPrefix + hex.EncodeToString(b)
+	return raw, raw[:len(tokenPrefix)+8], nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+type tokenKey struct{}
+
+// WithToken attaches an authenticated token to ctx.
+func WithToken(ctx context.Context, t *Token) context.Context {
+	return context.WithValue(ctx, tokenKey{}, t)
+}
+
+// FromContext returns the authenticated token, or nil when auth is disabled.
+func FromContext(ctx context.Context) *Token {
+	t, _ := ctx.Value(tokenKey{}).(*Token)
+	return t
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/atakanatali/contextify/internal/client"
 )
 
 const (
@@ -16,6 +18,7 @@ const (
 
 type CLIConfig struct {
 	ServerURL     string `yaml:"server_url"`
+	Token         string `yaml:"token"`
 	DockerImage   string `yaml:"docker_image"`
 	ContainerName string `yaml:"container_name"`
 	Port          string `yaml:"port"`
@@ -72,6 +75,23 @@ func getServerURL() string {
 	return loadCLIConfig().ServerURL
 }
 
+func getToken() string {
+	if flagToken != "" {
+		return flagToken
+	}
+	if env := os.Getenv("CONTEXTIFY_TOKEN"); env != "" {
+		return env
+	}
+	return loadCLIConfig().Token
+}
+
+// newClient returns an API client for the configured server and token.
+func newClient() *client.Client {
+	c := client.New(getServerURL())
+	c.Token = getToken()
+	return c
+}
+
 func getDockerImage() string {
 	if env := os.Getenv("CONTEXTIFY_IMAGE"); env != "" {
 		return env
@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 
-	"github.com/atakanatali/contextify/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -30,7 +29,7 @@ func runContext(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not detect project. Provide a project ID or run from a git repository")
 	}
 
-	c := client.New(getServerURL())
+	c := newClient()
 	memories, err := c.GetContext(cmd.Context(), projectID)
 	if err != nil {
 		return fmt.Errorf("get context: %w", err)
@@ -6,14 +6,13 @@ import (
 	"os"
 	"strings"
 
-	"github.com/atakanatali/contextify/internal/client"
 	"github.com/spf13/cobra"
 )
 
 func newDeleteCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "delete ID",
-		Short: "Delete a memory",
+		Short: "Move a memory to the trash",
 		Args:  cobra.ExactArgs(1),
 		RunE:  runDelete,
 	}
@@ -25,7 +24,7 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	id := args[0]
 	force, _ := cmd.Flags().GetBool("force")
 
-	c := client.New(getServerURL())
+	c := newClient()
 
 	if !force {
 		// Show memory info before confirming
@@ -49,6 +48,6 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("delete memory: %w", err)
 	}
 
-	printOK("Memory deleted.")
+	printOK("Memory moved to trash. Restore it with 'contextify trash restore " + id + "'.")
 	return nil
 }
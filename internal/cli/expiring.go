@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/atakanatali/contextify/internal/client"
+)
+
+func newExpiringCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expiring",
+		Short: "Review short-term memories that are about to expire",
+		Long: `Lists short-term memories that expire within the server's memory.review_window
+(24 hours by default), soonest first. Keep one for another TTL, promote it to
+long-term, or let it expire now; memories left alone go to the trash when
+they expire.`,
+		Args: cobra.NoArgs,
+		RunE: runExpiringList,
+	}
+	cmd.Flags().StringP("project", "p", "", "Filter by project ID")
+	cmd.Flags().String("within", "", "Look this far ahead, e.g. 6h (default: server's review window)")
+	cmd.Flags().IntP("limit", "l", 20, "Maximum number of memories")
+	cmd.Flags().String("cursor", "", "Continue from the cursor printed after a previous page")
+	cmd.AddCommand(
+		newExpiringReviewCmd("keep", "Keep memories for another TTL", "Kept"),
+		newExpiringReviewCmd("promote", "Promote memories to long-term", "Promoted"),
+		newExpiringReviewCmd("expire", "Move memories to the trash now", "Expired"),
+	)
+	return cmd
+}
+
+func runExpiringList(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	within, _ := cmd.Flags().GetString("within")
+	limit, _ := cmd.Flags().GetInt("limit")
+	cursor, _ := cmd.Flags().GetString("cursor")
+
+	c := newClient()
+	page, err := c.ListExpiring(cmd.Context(), client.ExpiringListOptions{
+		ProjectID: project,
+		Within:    within,
+		Limit:     limit,
+		Cursor:    cursor,
+	})
+	if err != nil {
+		return fmt.Errorf("list expiring memories: %w", err)
+	}
+	if len(page.Memories) == 0 {
+		printWarn("No memories expire within " + page.Within + ".")
+		return nil
+	}
+
+	printHeader("Expiring within " + page.Within)
+	for _, m := range page.Memories {
+		fmt.Printf("  %s %s\n", colorize(colorBold, m.Title), colorize(colorDim, m.ID))
+		lastRead := "never read"
+		if m.LastAccessedAt != nil {
+			lastRead = "last read " + formatTime(*m.LastAccessedAt)
+		}
+		expires := "-"
+		if m.ExpiresAt != nil {
+			expires = time.Until(*m.ExpiresAt).Round(time.Minute).String()
+		}
+		fmt.Printf("    %s\n", colorize(colorDim, fmt.Sprintf("%s, importance %.2f, %d reads, %s, expires in %s",
+			m.Type, m.Importance, m.AccessCount, lastRead, expires)))
+	}
+	fmt.Printf("\n  %s %d of %d memories\n", colorize(colorDim, "Showing:"), len(page.Memories), page.Total)
+	if page.NextCursor != "" {
+		fmt.Println(colorize(colorDim, "  more: --cursor "+page.NextCursor))
+	}
+	return nil
+}
+
+func newExpiringReviewCmd(action, short, done string) *cobra.Command {
+	return &cobra.Command{
+		Use:   action + " ID...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient()
+			for _, id := range args {
+				review, err := c.ReviewExpiring(cmd.Context(), id, action)
+				if err != nil {
+					return fmt.Errorf("%s %s: %w", action, id, err)
+				}
+				switch {
+				case review.Action != action:
+					printOK("Promoted " + id + " (its decay policy made it long-term)")
+				case review.ExpiresAt != nil:
+					printOK(fmt.Sprintf("%s %s until %s", done, id, formatTime(*review.ExpiresAt)))
+				default:
+					printOK(done + " " + id)
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/atakanatali/contextify/internal/client"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export memories to a JSONL archive",
+		Long: `Export memories, their relationships and consolidation history as a
+versioned JSONL archive that 'contextify import' can load on any install.
+
+  contextify export -o backup.jsonl
+  contextify export --project github.com/acme/api --since 2025-01-01 > api.jsonl`,
+		Args: cobra.NoArgs,
+		RunE: runExport,
+	}
+	cmd.Flags().StringP("output", "o", "", "Write to file instead of stdout")
+	cmd.Flags().StringP("project", "p", "", "Only memories of this project ID")
+	cmd.Flags().StringSliceP("type", "t", nil, "Only these memory types")
+	cmd.Flags().StringSliceP("tags", "T", nil, "Only memories carrying all these tags")
+	cmd.Flags().String("since", "", "Only memories created on or after (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().String("until", "", "Only memories created before (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().Bool("embeddings", false, "Include embedding vectors so import can skip re-embedding")
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	project, _ := cmd.Flags().GetString("project")
+	types, _ := cmd.Flags().GetStringSlice("type")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	embeddings, _ := cmd.Flags().GetBool("embeddings")
+
+	c := newClient()
+	body, err := c.Export(cmd.Context(), client.ExportOptions{
+		ProjectID:         project,
+		Types:             types,
+		Tags:              tags,
+		From:              since,
+		To:                until,
+		IncludeEmbeddings: embeddings,
+	})
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	defer body.Close()
+
+	if output == "" {
+		_, err := io.Copy(os.Stdout, body)
+		return err
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", output, err)
+	}
+	n, err := io.Copy(f, body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", output, err)
+	}
+
+	printOK(fmt.Sprintf("Exported to %s (%s)", output, formatBytes(n)))
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
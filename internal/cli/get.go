@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 
-	"github.com/atakanatali/contextify/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +16,7 @@ func newGetCmd() *cobra.Command {
 }
 
 func runGet(cmd *cobra.Command, args []string) error {
-	c := client.New(getServerURL())
+	c := newClient()
 	mem, err := c.GetMemory(cmd.Context(), args[0])
 	if err != nil {
 		return fmt.Errorf("get memory: %w", err)
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/atakanatali/contextify/internal/client"
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import memories from a JSONL archive",
+		Long: `Import an archive written by 'contextify export' into the current workspace.
+Use - to read from stdin.
+
+Memories whose ID already exists are skipped by default; --on-conflict
+overwrite replaces them and remap imports them under new IDs. With --dedup,
+new memories go through the same similarity check as 'contextify store' and
+near-duplicates are merged into existing memories.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImport,
+	}
+	cmd.Flags().String("on-conflict", "skip", "What to do with existing IDs (skip|overwrite|remap)")
+	cmd.Flags().Bool("reembed", false, "Recompute all embeddings instead of reusing archived ones")
+	cmd.Flags().Bool("dedup", false, "Merge near-duplicates into existing memories")
+	return cmd
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	onConflict, _ := cmd.Flags().GetString("on-conflict")
+	reembed, _ := cmd.Flags().GetBool("reembed")
+	dedup, _ := cmd.Flags().GetBool("dedup")
+
+	var in io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("open archive: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	c := newClient()
+	result, err := c.Import(cmd.Context(), in, client.ImportOptions{
+		OnConflict: onConflict,
+		Reembed:    reembed,
+		Dedup:      dedup,
+	})
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	printHeader("Import")
+	m := result.Memories
+	fmt.Printf("  Memories:       %d created, %d merged, %d overwritten, %d skipped (%d remapped)\n",
+		m.Created, m.Merged, m.Overwritten, m.Skipped, m.Remapped)
+	fmt.Printf("  Relationships:  %d created, %d skipped\n", result.Relationships.Created, result.Relationships.Skipped)
+	fmt.Printf("  Consolidations: %d created, %d skipped\n", result.Consolidations.Created, result.Consolidations.Skipped)
+
+	failed := m.Failed + result.Relationships.Failed + result.Consolidations.Failed
+	if failed == 0 {
+		fmt.Println()
+		printOK("Import complete.")
+		return nil
+	}
+	fmt.Println()
+	printWarn(fmt.Sprintf("%d records failed:", failed))
+	for _, e := range result.Errors {
+		fmt.Println("    " + colorize(colorDim, e))
+	}
+	return nil
+}
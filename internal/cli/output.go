@@ -121,6 +121,12 @@ func printSearchResults(results []client.SearchResult) {
 	}
 	for _, r := range results {
 		score := fmt.Sprintf("%.2f", r.Score)
+		if r.RerankScore != nil {
+			score += fmt.Sprintf(" rerank:%.2f", *r.RerankScore)
+		}
+		if r.Chunk != nil {
+			score += fmt.Sprintf(" chunk:%d-%d", r.Chunk.Start, r.Chunk.End)
+		}
 		typeColor := colorBlue
 		switch r.Memory.Type {
 		case "fix", "solution":
@@ -134,6 +140,24 @@ func printSearchResults(results []client.SearchResult) {
 			colorize(colorDim, "score:"+score),
 			colorize(colorDim, r.Memory.ID),
 		)
+		if e := r.Explanation; e != nil {
+			fmt.Printf("    %s\n", colorize(colorDim, fmt.Sprintf("%s rank_score=%.4f vector=%.3f (#%d) keyword=%.3f (#%d)",
+				e.Strategy, e.RankScore, e.VectorScore, e.VectorRank, e.KeywordScore, e.KeywordRank)))
+			if len(e.MatchedLexemes) > 0 {
+				fmt.Printf("    %s %s\n", colorize(colorDim, "matched:"), strings.Join(e.MatchedLexemes, ", "))
+			}
+			if len(e.MatchedSymbols) > 0 {
+				fmt.Printf("    %s %s\n", colorize(colorDim, fmt.Sprintf("symbols (%.2f, #%d):", e.SymbolScore, e.SymbolRank)), strings.Join(e.MatchedSymbols, ", "))
+			}
+			for _, h := range e.Highlights {
+				if isColorEnabled() {
+					h = strings.NewReplacer("<mark>", colorBold, "</mark>", colorReset).Replace(h)
+				} else {
+					h = strings.NewReplacer("<mark>", "", "</mark>", "").Replace(h)
+				}
+				fmt.Printf("    … %s\n", h)
+			}
+		}
 	}
 	fmt.Printf("\n  %s %d results\n", colorize(colorDim, "Total:"), len(results))
 }
@@ -144,6 +168,7 @@ func printStats(stats *client.Stats) {
 	fmt.Printf("  Long-term:         %s\n", colorize(colorGreen, fmt.Sprintf("%d", stats.LongTermCount)))
 	fmt.Printf("  Short-term:        %s\n", colorize(colorYellow, fmt.Sprintf("%d", stats.ShortTermCount)))
 	fmt.Printf("  Expiring soon:     %s\n", colorize(colorRed, fmt.Sprintf("%d", stats.ExpiringCount)))
+	fmt.Printf("  In trash:          %d\n", stats.TrashedCount)
 
 	if len(stats.ByType) > 0 {
 		fmt.Println()
@@ -162,6 +187,37 @@ func printStats(stats *client.Stats) {
 	fmt.Println()
 }
 
+func printReembedStatus(s *client.ReembedStatus) {
+	printHeader("Embedding Model")
+	fmt.Printf("  Serving search:    %s (%d dims)\n", s.Active.Model, s.Active.Dimensions)
+	fmt.Printf("  Configured:        %s (%d dims)\n", s.Configured.Model, s.Configured.Dimensions)
+	fmt.Printf("  Coverage:          %d/%d (%.1f%%)\n", s.Covered, s.Total, s.CoveragePct)
+
+	if s.Migration != nil {
+		m := s.Migration
+		fmt.Println()
+		fmt.Println(colorize(colorDim, "  Migration:"))
+		fmt.Printf("    %-16s %s\n", "status", m.Status)
+		fmt.Printf("    %-16s %s → %s\n", "models", m.SourceModel, m.TargetModel)
+		fmt.Printf("    %-16s %d\n", "processed", m.Processed)
+		fmt.Printf("    %-16s %s\n", "started", formatTime(m.StartedAt))
+		if m.CompletedAt != nil {
+			fmt.Printf("    %-16s %s\n", "completed", formatTime(*m.CompletedAt))
+		}
+		if m.LastError != nil && *m.LastError != "" {
+			fmt.Printf("    %-16s %s\n", "last error", colorize(colorRed, *m.LastError))
+		}
+	}
+	fmt.Println()
+
+	switch {
+	case s.InSync:
+		printOK("Stored embeddings match the configured model.")
+	case s.Migration == nil || s.Migration.Status != "running":
+		printWarn("Stored embeddings use another model. Run 'contextify reembed start' to migrate.")
+	}
+}
+
 func formatTime(t time.Time) string {
 	return t.Local().Format("2006-01-02 15:04")
 }
@@ -11,7 +11,7 @@ func newRecallCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "recall QUERY",
 		Short: "Semantic search for memories",
-		Long:  `Search memories using natural language. Uses hybrid vector + keyword matching.`,
+		Long:  `Search memories using natural language. Uses hybrid vector, keyword and symbol matching.`,
 		Args:  cobra.ExactArgs(1),
 		RunE:  runRecall,
 	}
@@ -19,6 +19,9 @@ func newRecallCmd() *cobra.Command {
 	cmd.Flags().StringP("project", "p", "", "Filter by project ID")
 	cmd.Flags().StringSliceP("tags", "T", nil, "Filter by tags")
 	cmd.Flags().StringP("type", "t", "", "Filter by memory type")
+	cmd.Flags().String("ranking", "", "Ranking strategy (linear|rrf|max)")
+	cmd.Flags().Bool("explain", false, "Show why each result matched")
+	cmd.Flags().Bool("rerank", false, "Rescore the top results with the server's rerank model (--rerank=false disables it)")
 	return cmd
 }
 
@@ -28,11 +31,15 @@ func runRecall(cmd *cobra.Command, args []string) error {
 	project, _ := cmd.Flags().GetString("project")
 	tags, _ := cmd.Flags().GetStringSlice("tags")
 	memType, _ := cmd.Flags().GetString("type")
+	ranking, _ := cmd.Flags().GetString("ranking")
+	explain, _ := cmd.Flags().GetBool("explain")
 
 	req := client.SearchRequest{
-		Query: query,
-		Limit: limit,
-		Tags:  tags,
+		Query:   query,
+		Limit:   limit,
+		Tags:    tags,
+		Ranking: ranking,
+		Explain: explain,
 	}
 	if project != "" {
 		req.ProjectID = &project
@@ -40,8 +47,12 @@ func runRecall(cmd *cobra.Command, args []string) error {
 	if memType != "" {
 		req.Type = &memType
 	}
+	if cmd.Flags().Changed("rerank") {
+		rerank, _ := cmd.Flags().GetBool("rerank")
+		req.Rerank = &rerank
+	}
 
-	c := client.New(getServerURL())
+	c := newClient()
 	results, err := c.Recall(cmd.Context(), req)
 	if err != nil {
 		return fmt.Errorf("recall: %w", err)
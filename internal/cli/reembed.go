@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newReembedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reembed [status|start|pause]",
+		Short: "Show or control re-embedding after an embedding model change",
+		Long: `Re-embed stored memories with the configured embedding model.
+
+The server keeps searching with the previous model until every memory has been
+re-embedded, then switches over. Without arguments this prints progress.`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{"status", "start", "pause"},
+		RunE:      runReembed,
+	}
+	cmd.Flags().BoolP("watch", "w", false, "Poll progress until the migration finishes")
+	return cmd
+}
+
+func runReembed(cmd *cobra.Command, args []string) error {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	watch, _ := cmd.Flags().GetBool("watch")
+
+	c := newClient()
+
+	switch action {
+	case "status":
+	case "start":
+		m, err := c.StartReembed(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("start reembed: %w", err)
+		}
+		printOK(fmt.Sprintf("Re-embedding to %s (%d dims) running.", m.TargetModel, m.TargetDimensions))
+	case "pause":
+		if _, err := c.PauseReembed(cmd.Context()); err != nil {
+			return fmt.Errorf("pause reembed: %w", err)
+		}
+		printOK("Re-embedding paused. Run 'contextify reembed start' to resume.")
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q (expected status, start or pause)", action)
+	}
+
+	for {
+		status, err := c.ReembedStatus(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("get reembed status: %w", err)
+		}
+		printReembedStatus(status)
+
+		running := status.Migration != nil && status.Migration.Status == "running"
+		if !watch || !running {
+			return nil
+		}
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-time.After(3 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/atakanatali/contextify/internal/client"
+)
+
+func newRetentionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Show or set the workspace's retention rules (requires an admin token)",
+		Long: `Retention rules set the default TTL and promotion thresholds of memories, and
+cap their age and count per project, by type, project glob and agent source.
+
+Rules set here apply to the caller's workspace and are checked before the
+memory.retention rules in the server's config.yaml.`,
+	}
+	cmd.AddCommand(newRetentionShowCmd(), newRetentionSetCmd(), newRetentionClearCmd())
+	return cmd
+}
+
+func newRetentionShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the retention rules",
+		Args:  cobra.NoArgs,
+		RunE:  runRetentionShow,
+	}
+}
+
+func runRetentionShow(cmd *cobra.Command, args []string) error {
+	c := newClient()
+	rules, err := c.RetentionRules(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("get retention rules: %w", err)
+	}
+	printRetentionRules("Workspace Retention Rules", rules.Rules)
+	printRetentionRules("Config Retention Rules", rules.ConfigRules)
+	return nil
+}
+
+func newRetentionSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set FILE",
+		Short: "Replace the workspace's rules with a JSON list of rules (- reads stdin)",
+		Example: `  echo '[{"type":"conversation","ttl":"6h","max_age":"720h"},
+         {"project":"github.com/acme/*","max_count":500}]' | contextify retention set -`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRetentionSet,
+	}
+}
+
+func runRetentionSet(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("read rules: %w", err)
+	}
+
+	var rules []client.RetentionRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		// Also accept the {"rules": [...]} shape the API returns.
+		var wrapped client.RetentionRules
+		if err2 := json.Unmarshal(data, &wrapped); err2 != nil {
+			return fmt.Errorf("parse rules: %w", err)
+		}
+		rules = wrapped.Rules
+	}
+
+	c := newClient()
+	result, err := c.SetRetentionRules(cmd.Context(), rules)
+	if err != nil {
+		return fmt.Errorf("set retention rules: %w", err)
+	}
+	printOK(fmt.Sprintf("Set %d retention rules.", len(result.Rules)))
+	return nil
+}
+
+func newRetentionClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove the workspace's rules, leaving the config rules",
+		Args:  cobra.NoArgs,
+		RunE:  runRetentionClear,
+	}
+}
+
+func runRetentionClear(cmd *cobra.Command, args []string) error {
+	c := newClient()
+	if _, err := c.SetRetentionRules(cmd.Context(), nil); err != nil {
+		return fmt.Errorf("clear retention rules: %w", err)
+	}
+	printOK("Cleared the workspace's retention rules.")
+	return nil
+}
+
+func printRetentionRules(title string, rules []client.RetentionRule) {
+	printHeader(title)
+	if len(rules) == 0 {
+		fmt.Println(colorize(colorDim, "  none"))
+		return
+	}
+	for i, r := range rules {
+		var match, set []string
+		for _, kv := range [][2]string{{"type", r.Type}, {"project", r.Project}, {"agent", r.AgentSource}} {
+			if kv[1] != "" {
+				match = append(match, kv[0]+"="+kv[1])
+			}
+		}
+		if len(match) == 0 {
+			match = []string{"all memories"}
+		}
+		if r.TTL != "" {
+			set = append(set, "ttl "+r.TTL)
+		}
+		if r.PromoteAccessCount > 0 {
+			set = append(set, fmt.Sprintf("promote at %d accesses", r.PromoteAccessCount))
+		}
+		if r.PromoteImportance != nil {
+			set = append(set, fmt.Sprintf("promote at importance %.2f", *r.PromoteImportance))
+		}
+		if r.MaxAge != "" {
+			set = append(set, "max age "+r.MaxAge)
+		}
+		if r.MaxCount > 0 {
+			set = append(set, fmt.Sprintf("max %d per project", r.MaxCount))
+		}
+		fmt.Printf("  %d. %s  %s\n", i+1, colorize(colorBold, strings.Join(match, " ")), strings.Join(set, ", "))
+	}
+}
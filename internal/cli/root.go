@@ -6,6 +6,7 @@ import (
 
 var (
 	flagServerURL string
+	flagToken     string
 	flagConfig    string
 	flagVerbose   bool
 )
@@ -23,6 +24,7 @@ and interact with the memory system from the command line.`,
 	}
 
 	rootCmd.PersistentFlags().StringVar(&flagServerURL, "server", "", "server URL (default http://localhost:8420)")
+	rootCmd.PersistentFlags().StringVar(&flagToken, "token", "", "API token (default $CONTEXTIFY_TOKEN or token in config)")
 	rootCmd.PersistentFlags().StringVar(&flagConfig, "config", "", "config file (default ~/.contextify/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "V", false, "verbose output")
 
@@ -43,9 +45,17 @@ and interact with the memory system from the command line.`,
 	rootCmd.AddCommand(newSearchCmd())
 	rootCmd.AddCommand(newGetCmd())
 	rootCmd.AddCommand(newDeleteCmd())
+	rootCmd.AddCommand(newTrashCmd())
+	rootCmd.AddCommand(newExpiringCmd())
 	rootCmd.AddCommand(newPromoteCmd())
 	rootCmd.AddCommand(newStatsCmd())
 	rootCmd.AddCommand(newContextCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newImportCmd())
+	rootCmd.AddCommand(newReembedCmd())
+	rootCmd.AddCommand(newTokenCmd())
+	rootCmd.AddCommand(newRetentionCmd())
+	rootCmd.AddCommand(newScheduleCmd())
 
 	return rootCmd
 }
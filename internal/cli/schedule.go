@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/atakanatali/contextify/internal/client"
+)
+
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "List, pause and trigger background jobs (requires an instance token)",
+		Long: `Background jobs such as TTL cleanup and the dedup scanner run on a schedule
+on the server instance holding the scheduler lock. Pauses and triggers are
+stored in the database, so they apply whichever instance runs the jobs.`,
+		Args: cobra.NoArgs,
+		RunE: runScheduleList,
+	}
+	cmd.AddCommand(
+		newScheduleListCmd(),
+		newScheduleActionCmd("pause", "Stop the scheduled runs of a job"),
+		newScheduleActionCmd("resume", "Resume the scheduled runs of a paused job"),
+		newScheduleActionCmd("trigger", "Run a job now, even when paused"),
+	)
+	return cmd
+}
+
+func newScheduleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the jobs and their last runs",
+		Args:  cobra.NoArgs,
+		RunE:  runScheduleList,
+	}
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	c := newClient()
+	schedules, err := c.Schedules(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("list schedules: %w", err)
+	}
+
+	printHeader("Scheduled Jobs")
+	if len(schedules.Jobs) == 0 {
+		fmt.Println(colorize(colorDim, "  none"))
+		return nil
+	}
+	for _, j := range schedules.Jobs {
+		state := "scheduled"
+		if j.Paused {
+			state = "paused"
+		}
+		if j.TriggerPending {
+			state += ", triggered"
+		}
+		fmt.Printf("  %s  %s  %s\n", colorize(colorBold, j.Name), j.Schedule, colorize(colorDim, state))
+		if j.NextRunAt != nil && !j.Paused {
+			fmt.Printf("    next run   %s\n", formatTime(*j.NextRunAt))
+		}
+		if j.LastRun != nil {
+			fmt.Printf("    last run   %s\n", formatScheduledRun(j.LastRun))
+		}
+		fmt.Printf("    runs       %d (%d failed)\n", j.RunCount, j.FailureCount)
+	}
+	if !schedules.Leader {
+		fmt.Println()
+		fmt.Println(colorize(colorDim, "  This instance does not run the jobs; another instance holds the scheduler lock."))
+	}
+	return nil
+}
+
+func formatScheduledRun(r *client.ScheduledRun) string {
+	parts := []string{formatTime(r.StartedAt), r.Status, r.TriggeredBy}
+	if r.DurationMs != nil {
+		parts = append(parts, fmt.Sprintf("%dms", *r.DurationMs))
+	}
+	if r.Error != nil {
+		parts = append(parts, *r.Error)
+	}
+	return strings.Join(parts, "  ")
+}
+
+func newScheduleActionCmd(action, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   action + " JOB",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient()
+			job, err := c.ControlSchedule(cmd.Context(), args[0], action)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", action, args[0], err)
+			}
+			switch action {
+			case "pause":
+				printOK(fmt.Sprintf("Paused %s.", job.Name))
+			case "resume":
+				printOK(fmt.Sprintf("Resumed %s.", job.Name))
+			case "trigger":
+				printOK(fmt.Sprintf("Triggered %s; it runs within a few seconds.", job.Name))
+			}
+			return nil
+		},
+	}
+}
@@ -11,9 +11,26 @@ func newSearchCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "search [QUERY]",
 		Short: "Search memories with filters",
-		Long:  `Search memories with advanced filters. Query is optional when using filters.`,
-		Args:  cobra.MaximumNArgs(1),
-		RunE:  runSearch,
+		Long: `Search memories with advanced filters. Query is optional when using filters.
+
+A query starting with symbol: matches identifiers and file paths instead of
+text, e.g. "symbol:Repository.HybridSearch parse_*" (a trailing * matches by
+prefix). Symbols are extracted from code blocks, snake_case, camelCase and
+qualified names, and paths.
+
+--filter takes space-separated terms, all of which must match; prefix a term
+with - to negate it:
+
+  tag:a,b                 any of the tags (tag:a+b for all of them)
+  type:fix,solution       also scope:, project:, agent:, lang:
+  created:>=2024-01-01    also updated:; =, >, >=, <, <= or a range a..b
+  updated:7d              within the last 7 days (h, d, w)
+  access:>=3              also importance:
+  has:relationship        linked to another memory (has:relationship:SOLVES)
+  include:expired         also match expired (replaced, all) memories
+  is:replaced             only replaced (or expired) memories`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runSearch,
 	}
 	cmd.Flags().StringP("type", "t", "", "Filter by memory type")
 	cmd.Flags().StringP("scope", "s", "", "Filter by scope (global|project)")
@@ -22,6 +39,9 @@ func newSearchCmd() *cobra.Command {
 	cmd.Flags().StringP("project", "p", "", "Filter by project ID")
 	cmd.Flags().StringP("agent", "a", "", "Filter by agent source")
 	cmd.Flags().IntP("limit", "l", 20, "Maximum number of results")
+	cmd.Flags().String("ranking", "", "Ranking strategy (linear|rrf|max)")
+	cmd.Flags().StringP("filter", "f", "", `Filter expression, e.g. "tag:redis -tag:wip updated:30d"`)
+	cmd.Flags().String("cursor", "", "Continue from the cursor printed after a previous page")
 	return cmd
 }
 
@@ -38,11 +58,17 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	project, _ := cmd.Flags().GetString("project")
 	agent, _ := cmd.Flags().GetString("agent")
 	limit, _ := cmd.Flags().GetInt("limit")
+	ranking, _ := cmd.Flags().GetString("ranking")
+	filter, _ := cmd.Flags().GetString("filter")
+	cursor, _ := cmd.Flags().GetString("cursor")
 
 	req := client.SearchRequest{
-		Query: query,
-		Tags:  tags,
-		Limit: limit,
+		Query:   query,
+		Tags:    tags,
+		Limit:   limit,
+		Ranking: ranking,
+		Filter:  filter,
+		Cursor:  cursor,
 	}
 	if memType != "" {
 		req.Type = &memType
@@ -60,12 +86,18 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		req.MinImportance = &minImp
 	}
 
-	c := client.New(getServerURL())
-	results, err := c.Search(cmd.Context(), req)
+	c := newClient()
+	page, err := c.SearchPage(cmd.Context(), req)
 	if err != nil {
 		return fmt.Errorf("search: %w", err)
 	}
 
-	printSearchResults(results)
+	printSearchResults(page.Results)
+	if page.NextCursor != "" {
+		fmt.Println(colorize(colorDim, "  more results: --cursor "+page.NextCursor))
+	}
+	if page.Truncated {
+		fmt.Println(colorize(colorDim, "  more results past the paging limit; narrow the search to see them"))
+	}
 	return nil
 }
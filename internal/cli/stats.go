@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 
-	"github.com/atakanatali/contextify/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -16,7 +15,7 @@ func newStatsCmd() *cobra.Command {
 }
 
 func runStats(cmd *cobra.Command, args []string) error {
-	c := client.New(getServerURL())
+	c := newClient()
 	stats, err := c.GetStats(cmd.Context())
 	if err != nil {
 		return fmt.Errorf("get stats: %w", err)
@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 
-	"github.com/atakanatali/contextify/internal/client"
 	"github.com/atakanatali/contextify/internal/docker"
 	"github.com/atakanatali/contextify/internal/toolconfig"
 	"github.com/spf13/cobra"
@@ -34,7 +33,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	// Health check
 	if status.Running {
-		c := client.New(getServerURL())
+		c := newClient()
 		if err := c.Health(cmd.Context()); err != nil {
 			printWarn("Server is not healthy yet")
 		} else {
@@ -29,6 +29,7 @@ Content can be provided via --content flag or piped from stdin:
 	cmd.Flags().StringP("scope", "s", "project", "Scope (global|project)")
 	cmd.Flags().StringP("project", "p", "", "Project ID (auto-detected from git)")
 	cmd.Flags().StringP("agent", "a", "cli", "Agent source identifier")
+	cmd.Flags().String("language", "", "Text search language, e.g. english, turkish or simple for code (auto-detected)")
 	return cmd
 }
 
@@ -41,6 +42,7 @@ func runStore(cmd *cobra.Command, args []string) error {
 	scope, _ := cmd.Flags().GetString("scope")
 	project, _ := cmd.Flags().GetString("project")
 	agent, _ := cmd.Flags().GetString("agent")
+	language, _ := cmd.Flags().GetString("language")
 
 	// Read content from stdin if not provided
 	if content == "" {
@@ -71,6 +73,7 @@ func runStore(cmd *cobra.Command, args []string) error {
 		Scope:      scope,
 		Importance: importance,
 		Tags:       tags,
+		Language:   language,
 	}
 	if project != "" {
 		req.ProjectID = &project
@@ -79,7 +82,7 @@ func runStore(cmd *cobra.Command, args []string) error {
 		req.AgentSource = &agent
 	}
 
-	c := client.New(getServerURL())
+	c := newClient()
 	mem, err := c.StoreMemory(cmd.Context(), req)
 	if err != nil {
 		return fmt.Errorf("store memory: %w", err)
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/atakanatali/contextify/internal/client"
+)
+
+func newTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage API tokens (requires an admin token)",
+	}
+	cmd.AddCommand(newTokenCreateCmd(), newTokenListCmd(), newTokenRevokeCmd())
+	return cmd
+}
+
+func newTokenCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a token; the secret is printed once",
+		Args:  cobra.NoArgs,
+		RunE:  runTokenCreate,
+	}
+	cmd.Flags().String("name", "", "Token name, e.g. the agent or machine using it (required)")
+	cmd.Flags().String("scope", "read", "Token scope (read, write, admin, instance)")
+	cmd.Flags().String("workspace", "", "Workspace the token belongs to (default: the caller's; others need the bootstrap token)")
+	cmd.Flags().String("expires", "", "Lifetime as a Go duration, e.g. 720h (default: never)")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func runTokenCreate(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	scope, _ := cmd.Flags().GetString("scope")
+	expires, _ := cmd.Flags().GetString("expires")
+	workspace, _ := cmd.Flags().GetString("workspace")
+
+	c := newClient()
+	result, err := c.CreateToken(cmd.Context(), client.CreateTokenRequest{
+		Name:      name,
+		Scope:     scope,
+		Workspace: workspace,
+		ExpiresIn: expires,
+	})
+	if err != nil {
+		return fmt.Errorf("create token: %w", err)
+	}
+
+	printOK(fmt.Sprintf("Created %s token %q in workspace %s (%s)", result.Token.Scope, result.Token.Name, result.Token.Workspace, result.Token.ID))
+	fmt.Println()
+	fmt.Println("  " + colorize(colorBold, result.RawToken))
+	fmt.Println()
+	printWarn("Store this token now; it cannot be shown again.")
+	return nil
+}
+
+func newTokenListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List API tokens",
+		Args:  cobra.NoArgs,
+		RunE:  runTokenList,
+	}
+	cmd.Flags().BoolP("all", "a", false, "Include revoked tokens")
+	return cmd
+}
+
+func runTokenList(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	c := newClient()
+	tokens, err := c.ListTokens(cmd.Context(), all)
+	if err != nil {
+		return fmt.Errorf("list tokens: %w", err)
+	}
+	if len(tokens) == 0 {
+		printWarn("No tokens found.")
+		return nil
+	}
+
+	printHeader("API Tokens")
+	for _, t := range tokens {
+		lastUsed := "never"
+		if t.LastUsedAt != nil {
+			lastUsed = formatTime(*t.LastUsedAt)
+		}
+		status := ""
+		switch {
+		case t.RevokedAt != nil:
+			status = colorize(colorRed, " revoked")
+		case t.ExpiresAt != nil:
+			status = colorize(colorDim, " expires "+formatTime(*t.ExpiresAt))
+		}
+		fmt.Printf("  %-6s %-16s %-24s %s  last used %s%s\n",
+			t.Scope,
+			t.Workspace,
+			colorize(colorBold, t.Name),
+			colorize(colorDim, t.Prefix+"… "+t.ID),
+			lastUsed,
+			status,
+		)
+	}
+	fmt.Printf("\n  %s %d tokens\n", colorize(colorDim, "Total:"), len(tokens))
+	return nil
+}
+
+func newTokenRevokeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke ID",
+		Short: "Revoke a token",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTokenRevoke,
+	}
+}
+
+func runTokenRevoke(cmd *cobra.Command, args []string) error {
+	c := newClient()
+	if err := c.RevokeToken(cmd.Context(), args[0]); err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	printOK("Token revoked.")
+	return nil
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/atakanatali/contextify/internal/client"
+)
+
+func newTrashCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, restore or purge deleted and expired memories",
+		Long: `Deleted memories, and memories whose TTL ran out, stay in the trash until
+the server's memory.trash_retention (30 days by default) has passed. They do
+not appear in search, and can be restored with their relationships and history.`,
+	}
+	cmd.AddCommand(newTrashListCmd(), newTrashRestoreCmd(), newTrashPurgeCmd())
+	return cmd
+}
+
+func newTrashListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List trashed memories, most recently deleted first",
+		Args:  cobra.NoArgs,
+		RunE:  runTrashList,
+	}
+	cmd.Flags().StringP("project", "p", "", "Filter by project ID")
+	cmd.Flags().String("reason", "", "Filter by reason (deleted|expired)")
+	cmd.Flags().IntP("limit", "l", 20, "Maximum number of memories")
+	cmd.Flags().String("cursor", "", "Continue from the cursor printed after a previous page")
+	return cmd
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	reason, _ := cmd.Flags().GetString("reason")
+	limit, _ := cmd.Flags().GetInt("limit")
+	cursor, _ := cmd.Flags().GetString("cursor")
+
+	c := newClient()
+	page, err := c.ListTrash(cmd.Context(), client.TrashListOptions{
+		ProjectID: project,
+		Reason:    reason,
+		Limit:     limit,
+		Cursor:    cursor,
+	})
+	if err != nil {
+		return fmt.Errorf("list trash: %w", err)
+	}
+	if len(page.Memories) == 0 {
+		printWarn("Trash is empty.")
+		return nil
+	}
+
+	printHeader("Trash")
+	for _, t := range page.Memories {
+		fmt.Printf("  %-8s %s %s\n",
+			t.DeletedReason,
+			colorize(colorBold, t.Title),
+			colorize(colorDim, t.ID),
+		)
+		fmt.Printf("           %s\n", colorize(colorDim, fmt.Sprintf("%s, deleted %s, purged %s",
+			t.Type, formatTime(t.DeletedAt), formatTime(t.PurgeAt))))
+	}
+	fmt.Printf("\n  %s %d of %d memories\n", colorize(colorDim, "Showing:"), len(page.Memories), page.Total)
+	if page.NextCursor != "" {
+		fmt.Println(colorize(colorDim, "  more: --cursor "+page.NextCursor))
+	}
+	return nil
+}
+
+func newTrashRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore ID...",
+		Short: "Restore trashed memories",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runTrashRestore,
+	}
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) error {
+	c := newClient()
+	for _, id := range args {
+		mem, err := c.RestoreFromTrash(cmd.Context(), id)
+		if err != nil {
+			return fmt.Errorf("restore %s: %w", id, err)
+		}
+		printOK(fmt.Sprintf("Restored %s (%s)", mem.Title, mem.ID))
+	}
+	return nil
+}
+
+func newTrashPurgeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge [ID...]",
+		Short: "Permanently delete trashed memories (requires an admin token)",
+		RunE:  runTrashPurge,
+	}
+	cmd.Flags().Bool("all", false, "Purge the whole trash")
+	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	return cmd
+}
+
+func runTrashPurge(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	force, _ := cmd.Flags().GetBool("force")
+	if all == (len(args) > 0) {
+		return fmt.Errorf("pass memory IDs or --all")
+	}
+
+	if !force {
+		if all {
+			fmt.Print("  Permanently delete every memory in the trash? [y/N] ")
+		} else {
+			fmt.Printf("  Permanently delete %d memories? [y/N] ", len(args))
+		}
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("  Cancelled.")
+			return nil
+		}
+	}
+
+	c := newClient()
+	if all {
+		count, err := c.EmptyTrash(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("empty trash: %w", err)
+		}
+		printOK(fmt.Sprintf("Purged %d memories.", count))
+		return nil
+	}
+	for _, id := range args {
+		if err := c.PurgeTrash(cmd.Context(), id); err != nil {
+			return fmt.Errorf("purge %s: %w", id, err)
+		}
+		printOK("Purged " + id)
+	}
+	return nil
+}
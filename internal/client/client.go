@@ -8,11 +8,14 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Client struct {
 	BaseURL    string
+	Token      string // bearer token; empty when the server runs without auth
 	HTTPClient *http.Client
 }
 
@@ -94,6 +97,140 @@ func (c *Client) Search(ctx context.Context, req SearchRequest) ([]SearchResult,
 	return results, nil
 }
 
+// SearchPage runs a cursor-paginated search. Pass the returned NextCursor as
+// req.Cursor to fetch the next page; it is empty on the last page.
+func (c *Client) SearchPage(ctx context.Context, req SearchRequest) (*SearchPage, error) {
+	req.Paginate = true
+	var page SearchPage
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/memories/search", req, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) ListSuggestions(ctx context.Context, opts SuggestionListOptions) (*SuggestionPage, error) {
+	q := url.Values{}
+	if opts.ProjectID != "" {
+		q.Set("project_id", opts.ProjectID)
+	}
+	if opts.Status != "" {
+		q.Set("status", opts.Status)
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	var page SuggestionPage
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/consolidation/suggestions?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) ListTrash(ctx context.Context, opts TrashListOptions) (*TrashPage, error) {
+	q := url.Values{}
+	if opts.ProjectID != "" {
+		q.Set("project_id", opts.ProjectID)
+	}
+	if opts.Reason != "" {
+		q.Set("reason", opts.Reason)
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	var page TrashPage
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/trash?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) RestoreFromTrash(ctx context.Context, id string) (*Memory, error) {
+	var mem Memory
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/trash/"+id+"/restore", nil, &mem); err != nil {
+		return nil, err
+	}
+	return &mem, nil
+}
+
+func (c *Client) PurgeTrash(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/v1/trash/"+id, nil, nil)
+}
+
+// EmptyTrash purges every trashed memory and returns how many there were.
+func (c *Client) EmptyTrash(ctx context.Context) (int64, error) {
+	var resp struct {
+		Count int64 `json:"count"`
+	}
+	if err := c.doJSON(ctx, http.MethodDelete, "/api/v1/trash", nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+func (c *Client) ListExpiring(ctx context.Context, opts ExpiringListOptions) (*ExpiringPage, error) {
+	q := url.Values{}
+	if opts.ProjectID != "" {
+		q.Set("project_id", opts.ProjectID)
+	}
+	if opts.Within != "" {
+		q.Set("within", opts.Within)
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	var page ExpiringPage
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/memories/expiring?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ReviewExpiring keeps, promotes or expires a memory in the expiry review
+// queue.
+func (c *Client) ReviewExpiring(ctx context.Context, id, action string) (*ExpiryReview, error) {
+	var review ExpiryReview
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/memories/"+id+"/review", map[string]string{"action": action}, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (c *Client) ListStewardRuns(ctx context.Context, opts StewardRunListOptions) (*StewardRunPage, error) {
+	q := url.Values{}
+	if opts.Status != "" {
+		q.Set("status", opts.Status)
+	}
+	if opts.JobType != "" {
+		q.Set("job_type", opts.JobType)
+	}
+	if opts.ProjectID != "" {
+		q.Set("project_id", opts.ProjectID)
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	if opts.IncludeTotal {
+		q.Set("include_total", "true")
+	}
+	var page StewardRunPage
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/steward/runs?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
 func (c *Client) GetContext(ctx context.Context, projectID string) ([]Memory, error) {
 	var memories []Memory
 	encoded := url.PathEscape(projectID)
@@ -111,6 +248,182 @@ func (c *Client) GetStats(ctx context.Context) (*Stats, error) {
 	return &stats, nil
 }
 
+func (c *Client) RetentionRules(ctx context.Context) (*RetentionRules, error) {
+	var rules RetentionRules
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/admin/retention", nil, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+func (c *Client) SetRetentionRules(ctx context.Context, rules []RetentionRule) (*RetentionRules, error) {
+	var result RetentionRules
+	body := map[string]any{"rules": rules}
+	if err := c.doJSON(ctx, http.MethodPut, "/api/v1/admin/retention", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) Schedules(ctx context.Context) (*Schedules, error) {
+	var schedules Schedules
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/admin/schedules", nil, &schedules); err != nil {
+		return nil, err
+	}
+	return &schedules, nil
+}
+
+// ControlSchedule pauses, resumes or triggers a scheduled job; action is
+// "pause", "resume" or "trigger".
+func (c *Client) ControlSchedule(ctx context.Context, name, action string) (*ScheduledJob, error) {
+	var job ScheduledJob
+	body := map[string]string{"name": name, "action": action}
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/admin/schedules", body, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (c *Client) ReembedStatus(ctx context.Context) (*ReembedStatus, error) {
+	var status ReembedStatus
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/admin/reembed", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Client) StartReembed(ctx context.Context) (*EmbeddingMigration, error) {
+	var m EmbeddingMigration
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/admin/reembed", nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *Client) PauseReembed(ctx context.Context) (*EmbeddingMigration, error) {
+	var m EmbeddingMigration
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/admin/reembed/pause", nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *Client) CreateToken(ctx context.Context, req CreateTokenRequest) (*CreateTokenResult, error) {
+	var result CreateTokenResult
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/admin/tokens", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) ListTokens(ctx context.Context, includeRevoked bool) ([]APIToken, error) {
+	path := "/api/v1/admin/tokens"
+	if includeRevoked {
+		path += "?include_revoked=true"
+	}
+	var result struct {
+		Tokens []APIToken `json:"tokens"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Tokens, nil
+}
+
+func (c *Client) RevokeToken(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/v1/admin/tokens/"+url.PathEscape(id), nil, nil)
+}
+
+// Export streams an archive of the workspace's memories. The caller must
+// close the returned reader.
+func (c *Client) Export(ctx context.Context, opts ExportOptions) (io.ReadCloser, error) {
+	q := url.Values{}
+	if opts.ProjectID != "" {
+		q.Set("project_id", opts.ProjectID)
+	}
+	if len(opts.Types) > 0 {
+		q.Set("type", strings.Join(opts.Types, ","))
+	}
+	if len(opts.Tags) > 0 {
+		q.Set("tags", strings.Join(opts.Tags, ","))
+	}
+	if opts.From != "" {
+		q.Set("from", opts.From)
+	}
+	if opts.To != "" {
+		q.Set("to", opts.To)
+	}
+	if opts.IncludeEmbeddings {
+		q.Set("include_embeddings", "true")
+	}
+	path := "/api/v1/export"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	resp, err := c.doStream(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Import uploads an archive read from r.
+func (c *Client) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	q := url.Values{}
+	if opts.OnConflict != "" {
+		q.Set("on_conflict", opts.OnConflict)
+	}
+	if opts.Reembed {
+		q.Set("reembed", "true")
+	}
+	if opts.Dedup {
+		q.Set("dedup", "true")
+	}
+	path := "/api/v1/import"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	resp, err := c.doStream(ctx, http.MethodPost, path, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// doStream sends body as-is and returns the open response. Exports and
+// imports can take minutes, so the client timeout does not apply.
+func (c *Client) doStream(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-ndjson")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	hc := *c.HTTPClient
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error %s: %s", resp.Status, string(respBody))
+	}
+	return resp, nil
+}
+
 func (c *Client) doJSON(ctx context.Context, method, path string, body, result any) error {
 	var bodyReader io.Reader
 	if body != nil {
@@ -128,6 +441,9 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body, result a
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
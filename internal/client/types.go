@@ -11,6 +11,7 @@ type Memory struct {
 	Scope       string    `json:"scope"`
 	ProjectID   *string   `json:"project_id,omitempty"`
 	AgentSource *string   `json:"agent_source,omitempty"`
+	Language    string    `json:"language,omitempty"`
 	Tags        []string  `json:"tags"`
 	Importance  float32   `json:"importance"`
 	TTLSeconds  *int      `json:"ttl_seconds,omitempty"`
@@ -41,6 +42,7 @@ type StoreRequest struct {
 	Tags        []string `json:"tags"`
 	Importance  float32  `json:"importance"`
 	TTLSeconds  *int     `json:"ttl_seconds,omitempty"`
+	Language    string   `json:"language,omitempty"`
 }
 
 type UpdateRequest struct {
@@ -50,6 +52,7 @@ type UpdateRequest struct {
 	Type       *string  `json:"type,omitempty"`
 	Tags       []string `json:"tags,omitempty"`
 	Importance *float32 `json:"importance,omitempty"`
+	Language   *string  `json:"language,omitempty"`
 }
 
 type SearchRequest struct {
@@ -62,12 +65,56 @@ type SearchRequest struct {
 	MinImportance *float32 `json:"min_importance,omitempty"`
 	Limit         int      `json:"limit"`
 	Offset        int      `json:"offset"`
+	Ranking       string   `json:"ranking,omitempty"`
+	Explain       bool     `json:"explain,omitempty"`
+	Rerank        *bool    `json:"rerank,omitempty"`
+	Filter        string   `json:"filter,omitempty"`
+	Cursor        string   `json:"cursor,omitempty"`
+	Paginate      bool     `json:"paginate,omitempty"`
+	IncludeTotal  bool     `json:"include_total,omitempty"`
+}
+
+// SearchPage is one page of a cursor-paginated search. TotalEstimate is a
+// lower bound on the matches. Truncated reports matches past the deepest
+// page a search serves.
+type SearchPage struct {
+	Results       []SearchResult `json:"results"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+	TotalEstimate *int           `json:"total_estimate,omitempty"`
+	Truncated     bool           `json:"truncated,omitempty"`
 }
 
 type SearchResult struct {
-	Memory    Memory  `json:"memory"`
-	Score     float64 `json:"score"`
-	MatchType string  `json:"match_type"`
+	Memory      Memory             `json:"memory"`
+	Score       float64            `json:"score"`
+	MatchType   string             `json:"match_type"`
+	Explanation *SearchExplanation `json:"explanation,omitempty"`
+	RerankScore *float64           `json:"rerank_score,omitempty"`
+	Chunk       *ChunkMatch        `json:"chunk,omitempty"`
+}
+
+// ChunkMatch is the passage of a long memory that matched a search, in
+// character offsets into its content.
+type ChunkMatch struct {
+	Index int     `json:"index"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Score float64 `json:"score"`
+}
+
+type SearchExplanation struct {
+	Strategy       string             `json:"strategy"`
+	RankScore      float64            `json:"rank_score"`
+	VectorScore    float64            `json:"vector_score"`
+	VectorRank     int                `json:"vector_rank"`
+	KeywordScore   float64            `json:"keyword_score"`
+	KeywordRank    int                `json:"keyword_rank"`
+	SymbolScore    float64            `json:"symbol_score"`
+	SymbolRank     int                `json:"symbol_rank"`
+	MatchedSymbols []string           `json:"matched_symbols,omitempty"`
+	Boosts         map[string]float64 `json:"boosts,omitempty"`
+	MatchedLexemes []string           `json:"matched_lexemes"`
+	Highlights     []string           `json:"highlights"`
 }
 
 type Stats struct {
@@ -78,4 +125,249 @@ type Stats struct {
 	LongTermCount  int            `json:"long_term_count"`
 	ShortTermCount int            `json:"short_term_count"`
 	ExpiringCount  int            `json:"expiring_count"`
+	TrashedCount   int            `json:"trashed_count"`
+}
+
+type ConsolidationSuggestion struct {
+	ID         string     `json:"id"`
+	MemoryAID  string     `json:"memory_a_id"`
+	MemoryBID  string     `json:"memory_b_id"`
+	Similarity float64    `json:"similarity"`
+	Status     string     `json:"status"`
+	ProjectID  *string    `json:"project_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	MemoryA    *Memory    `json:"memory_a,omitempty"`
+	MemoryB    *Memory    `json:"memory_b,omitempty"`
+}
+
+type SuggestionListOptions struct {
+	ProjectID string
+	Status    string // pending (default), accepted or dismissed
+	Limit     int
+	Cursor    string
+}
+
+type SuggestionPage struct {
+	Suggestions []ConsolidationSuggestion `json:"suggestions"`
+	Total       int                       `json:"total"`
+	NextCursor  string                    `json:"next_cursor,omitempty"`
+}
+
+type TrashedMemory struct {
+	Memory
+	DeletedAt     time.Time `json:"deleted_at"`
+	DeletedReason string    `json:"deleted_reason"`
+	PurgeAt       time.Time `json:"purge_at"`
+}
+
+type TrashListOptions struct {
+	ProjectID string
+	Reason    string // deleted, expired or empty for both
+	Limit     int
+	Cursor    string
+}
+
+type TrashPage struct {
+	Memories   []TrashedMemory `json:"memories"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type ExpiringMemory struct {
+	Memory
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
+
+type ExpiringListOptions struct {
+	ProjectID string
+	Within    string // Go duration; empty uses the server's review window
+	Limit     int
+	Cursor    string
+}
+
+type ExpiringPage struct {
+	Memories   []ExpiringMemory `json:"memories"`
+	Total      int              `json:"total"`
+	Within     string           `json:"within"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// ExpiryReview is the outcome of a keep, promote or expire review action.
+type ExpiryReview struct {
+	ID        string     `json:"id"`
+	Action    string     `json:"action"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RetentionRule mirrors the server's rule. Durations are Go duration
+// strings such as "72h".
+type RetentionRule struct {
+	Type               string   `json:"type,omitempty"`
+	Project            string   `json:"project,omitempty"`
+	AgentSource        string   `json:"agent_source,omitempty"`
+	TTL                string   `json:"ttl,omitempty"`
+	PromoteAccessCount int      `json:"promote_access_count,omitempty"`
+	PromoteImportance  *float64 `json:"promote_importance,omitempty"`
+	MaxAge             string   `json:"max_age,omitempty"`
+	MaxCount           int      `json:"max_count,omitempty"`
+}
+
+type RetentionRules struct {
+	Rules       []RetentionRule `json:"rules"`
+	ConfigRules []RetentionRule `json:"config_rules"`
+}
+
+type ScheduledRun struct {
+	ID          string         `json:"id"`
+	TriggeredBy string         `json:"triggered_by"`
+	Status      string         `json:"status"`
+	StartedAt   time.Time      `json:"started_at"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty"`
+	DurationMs  *int64         `json:"duration_ms,omitempty"`
+	Error       *string        `json:"error,omitempty"`
+	Result      map[string]any `json:"result,omitempty"`
+}
+
+type ScheduledJob struct {
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Schedule       string         `json:"schedule"`
+	Timeout        string         `json:"timeout"`
+	Paused         bool           `json:"paused"`
+	NextRunAt      *time.Time     `json:"next_run_at,omitempty"`
+	TriggerPending bool           `json:"trigger_pending"`
+	RunCount       int64          `json:"run_count"`
+	FailureCount   int64          `json:"failure_count"`
+	LastRun        *ScheduledRun  `json:"last_run,omitempty"`
+	RecentRuns     []ScheduledRun `json:"recent_runs"`
+}
+
+type Schedules struct {
+	Leader   bool           `json:"leader"`
+	WorkerID string         `json:"worker_id"`
+	Jitter   string         `json:"jitter"`
+	Jobs     []ScheduledJob `json:"jobs"`
+}
+
+// StewardRun mirrors the server's run record, which is encoded with Go
+// field names.
+type StewardRun struct {
+	ID           string     `json:"ID"`
+	JobID        *string    `json:"JobID"`
+	JobType      *string    `json:"JobType"`
+	ProjectID    *string    `json:"ProjectID"`
+	Model        *string    `json:"Model"`
+	TotalTokens  *int       `json:"TotalTokens"`
+	LatencyMs    *int       `json:"LatencyMs"`
+	Status       string     `json:"Status"`
+	ErrorClass   *string    `json:"ErrorClass"`
+	ErrorMessage *string    `json:"ErrorMessage"`
+	CreatedAt    time.Time  `json:"CreatedAt"`
+	CompletedAt  *time.Time `json:"CompletedAt"`
+}
+
+type StewardRunListOptions struct {
+	Status       string
+	JobType      string
+	ProjectID    string
+	Limit        int
+	Cursor       string
+	IncludeTotal bool
+}
+
+type StewardRunPage struct {
+	Runs       []StewardRun `json:"runs"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Total      *int         `json:"total,omitempty"`
+}
+
+type EmbeddingState struct {
+	Provider   string `json:"provider"`
+	Model      string `json:"model"`
+	Dimensions int    `json:"dimensions"`
+}
+
+type EmbeddingMigration struct {
+	ID               string     `json:"id"`
+	SourceModel      string     `json:"source_model"`
+	SourceDimensions int        `json:"source_dimensions"`
+	TargetProvider   string     `json:"target_provider"`
+	TargetModel      string     `json:"target_model"`
+	TargetDimensions int        `json:"target_dimensions"`
+	Status           string     `json:"status"`
+	Total            int        `json:"total"`
+	Processed        int        `json:"processed"`
+	Failed           int        `json:"failed"`
+	LastError        *string    `json:"last_error,omitempty"`
+	StartedAt        time.Time  `json:"started_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+}
+
+type ReembedStatus struct {
+	Active      EmbeddingState      `json:"active"`
+	Configured  EmbeddingState      `json:"configured"`
+	InSync      bool                `json:"in_sync"`
+	Total       int                 `json:"total"`
+	Covered     int                 `json:"covered"`
+	Pending     int                 `json:"pending"`
+	CoveragePct float64             `json:"coverage_pct"`
+	Migration   *EmbeddingMigration `json:"migration,omitempty"`
+	AutoStart   bool                `json:"auto_start"`
+}
+
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	Workspace  string     `json:"workspace"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreateTokenRequest struct {
+	Name      string `json:"name"`
+	Scope     string `json:"scope"`
+	Workspace string `json:"workspace,omitempty"`
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+type CreateTokenResult struct {
+	Token    APIToken `json:"token"`
+	RawToken string   `json:"raw_token"`
+}
+
+type ExportOptions struct {
+	ProjectID         string
+	Types             []string
+	Tags              []string
+	From              string // YYYY-MM-DD or RFC 3339
+	To                string
+	IncludeEmbeddings bool
+}
+
+type ImportOptions struct {
+	OnConflict string // skip, overwrite or remap
+	Reembed    bool
+	Dedup      bool
+}
+
+type ImportCounts struct {
+	Created     int `json:"created"`
+	Merged      int `json:"merged,omitempty"`
+	Overwritten int `json:"overwritten,omitempty"`
+	Remapped    int `json:"remapped,omitempty"`
+	Skipped     int `json:"skipped"`
+	Failed      int `json:"failed"`
+}
+
+type ImportResult struct {
+	Memories       ImportCounts `json:"memories"`
+	Relationships  ImportCounts `json:"relationships"`
+	Consolidations ImportCounts `json:"consolidations"`
+	Errors         []string     `json:"errors,omitempty"`
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad_AppliesStewardEnvOverrides(t *testing.T) {
@@ -50,6 +51,62 @@ func TestLoad_RejectsInvalidStewardDuration(t *testing.T) {
 	}
 }
 
+func TestLoad_RejectsUnknownEmbeddingProvider(t *testing.T) {
+	t.Setenv("EMBEDDING_PROVIDER", "bogus")
+
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for unknown embedding provider")
+	}
+}
+
+func TestLoad_NormalizesEmbeddingProvider(t *testing.T) {
+	t.Setenv("EMBEDDING_PROVIDER", " OpenAI")
+	t.Setenv("EMBEDDING_BASE_URL", "http://localhost:8080")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Embedding.Provider != "openai" {
+		t.Fatalf("expected provider openai, got %q", cfg.Embedding.Provider)
+	}
+}
+
+func TestLoad_OpenAIProviderRequiresBaseURL(t *testing.T) {
+	t.Setenv("EMBEDDING_PROVIDER", "openai")
+
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for missing embedding.base_url")
+	}
+
+	t.Setenv("EMBEDDING_BASE_URL", "http://localhost:8080")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Embedding.BaseURL != "http://localhost:8080" {
+		t.Fatalf("unexpected base_url: %q", cfg.Embedding.BaseURL)
+	}
+}
+
+func TestLoad_AuthEnvOverridesAndValidation(t *testing.T) {
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_BOOTSTRAP_TOKEN", "short")
+
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for short bootstrap token")
+	}
+
+	t.Setenv("AUTH_BOOTSTRAP_TOKEN", "0123456789abcdef")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Auth.Enabled || cfg.Auth.BootstrapToken != "0123456789abcdef" {
+		t.Fatalf("unexpected auth config: %+v", cfg.Auth)
+	}
+}
+
 func TestMain(m *testing.M) {
 	// Prevent ambient environment from affecting config tests unpredictably.
 	os.Unsetenv("STEWARD_ENABLED")
@@ -59,5 +116,223 @@ func TestMain(m *testing.M) {
 	os.Unsetenv("STEWARD_AUTO_MERGE_THRESHOLD")
 	os.Unsetenv("STEWARD_DERIVATION_MIN_CONFIDENCE")
 	os.Unsetenv("STEWARD_DERIVATION_MIN_NOVELTY")
+	os.Unsetenv("EMBEDDING_PROVIDER")
+	os.Unsetenv("EMBEDDING_BASE_URL")
+	os.Unsetenv("AUTH_ENABLED")
+	os.Unsetenv("AUTH_BOOTSTRAP_TOKEN")
+	os.Unsetenv("SEARCH_RANKING")
+	os.Unsetenv("SEARCH_RECENCY_HALF_LIFE")
+	os.Unsetenv("SEARCH_RECENCY_WEIGHT")
+	os.Unsetenv("EMBEDDING_CHUNKING_ENABLED")
+	os.Unsetenv("SEARCH_LANGUAGE_DEFAULT")
+	os.Unsetenv("SEARCH_LANGUAGE_DETECT")
+	os.Unsetenv("SEARCH_CACHE_NOTIFY")
+	os.Unsetenv("SEARCH_EMBEDDING_CACHE_SIZE")
+	os.Unsetenv("SEARCH_EMBEDDING_CACHE_TTL")
+	os.Unsetenv("TRASH_RETENTION")
+	os.Unsetenv("DECAY_POLICY")
+	os.Unsetenv("REVIEW_WINDOW")
+	os.Unsetenv("STEWARD_EXPIRY_REVIEW_ENABLED")
+	os.Unsetenv("SCHEDULER_JITTER")
 	os.Exit(m.Run())
 }
+
+func TestLoad_RejectsInvertedRelationshipSimilarity(t *testing.T) {
+	t.Setenv("STEWARD_RELATIONSHIPS_MIN_SIMILARITY", "0.95")
+
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error when min_similarity exceeds max_similarity")
+	}
+}
+
+func TestLoad_RejectsUnknownSearchRanking(t *testing.T) {
+	t.Setenv("SEARCH_RANKING", "bm25")
+
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for unknown ranking strategy")
+	}
+}
+
+func TestLoad_ChunkingDefaults(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Embedding.Chunking.Enabled || cfg.Embedding.Chunking.Size != 1500 || cfg.Embedding.Chunking.Overlap != 200 {
+		t.Fatalf("unexpected chunking defaults: %+v", cfg.Embedding.Chunking)
+	}
+
+	t.Setenv("EMBEDDING_CHUNKING_ENABLED", "false")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Embedding.Chunking.Enabled {
+		t.Fatalf("expected EMBEDDING_CHUNKING_ENABLED=false to disable chunking")
+	}
+}
+
+func TestLoad_SearchModifierEnvOverrides(t *testing.T) {
+	t.Setenv("SEARCH_RECENCY_HALF_LIFE", "720h")
+	t.Setenv("SEARCH_RECENCY_WEIGHT", "0.4")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Search.Modifiers.RecencyHalfLife != 720*time.Hour {
+		t.Fatalf("unexpected recency_half_life: %s", cfg.Search.Modifiers.RecencyHalfLife)
+	}
+	if cfg.Search.Modifiers.RecencyWeight != 0.4 {
+		t.Fatalf("unexpected recency_weight: %v", cfg.Search.Modifiers.RecencyWeight)
+	}
+
+	t.Setenv("SEARCH_RECENCY_WEIGHT", "1.5")
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for recency_weight outside [0,1]")
+	}
+}
+
+func TestLoad_SearchLanguage(t *testing.T) {
+	t.Setenv("SEARCH_LANGUAGE_DEFAULT", "turkish")
+	t.Setenv("SEARCH_LANGUAGE_DETECT", "false")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Search.Language.Default != "turkish" || cfg.Search.Language.Detect {
+		t.Fatalf("unexpected search language: %+v", cfg.Search.Language)
+	}
+
+	t.Setenv("SEARCH_LANGUAGE_DEFAULT", "klingon")
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for unknown language")
+	}
+}
+
+func TestLoad_SearchEmbeddingCache(t *testing.T) {
+	t.Setenv("SEARCH_CACHE_NOTIFY", "false")
+	t.Setenv("SEARCH_EMBEDDING_CACHE_SIZE", "0")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Search.CacheNotify || cfg.Search.EmbeddingCacheSize != 0 {
+		t.Fatalf("unexpected search cache config: %+v", cfg.Search)
+	}
+
+	t.Setenv("SEARCH_EMBEDDING_CACHE_SIZE", "100")
+	t.Setenv("SEARCH_EMBEDDING_CACHE_TTL", "0s")
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for zero embedding_cache_ttl")
+	}
+}
+
+func TestLoad_TrashRetention(t *testing.T) {
+	t.Setenv("TRASH_RETENTION", "168h")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Memory.TrashRetention != 7*24*time.Hour {
+		t.Fatalf("trash retention = %v, want 168h", cfg.Memory.TrashRetention)
+	}
+
+	t.Setenv("TRASH_RETENTION", "0s")
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for zero trash_retention")
+	}
+}
+
+func TestLoad_ReviewWindow(t *testing.T) {
+	t.Setenv("REVIEW_WINDOW", "6h")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Memory.ReviewWindow != 6*time.Hour {
+		t.Fatalf("review window = %v, want 6h", cfg.Memory.ReviewWindow)
+	}
+
+	t.Setenv("REVIEW_WINDOW", "0s")
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for zero review_window")
+	}
+}
+
+func TestLoad_SchedulerJitter(t *testing.T) {
+	t.Setenv("SCHEDULER_JITTER", "0s")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Scheduler.Jitter != 0 {
+		t.Fatalf("scheduler jitter = %v, want 0", cfg.Scheduler.Jitter)
+	}
+
+	t.Setenv("SCHEDULER_JITTER", "-1s")
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for negative scheduler.jitter")
+	}
+}
+
+func TestLoad_DecayPolicy(t *testing.T) {
+	t.Setenv("DECAY_POLICY", "exponential")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Memory.Decay.Policy != "exponential" {
+		t.Fatalf("decay policy = %q, want exponential", cfg.Memory.Decay.Policy)
+	}
+
+	t.Setenv("DECAY_POLICY", "forever")
+	if _, err := Load(""); err == nil {
+		t.Fatalf("expected validation error for unknown decay policy")
+	}
+}
+
+func TestValidateDecay_Rules(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	d := cfg.Memory.Decay
+
+	d.Rules = []DecayRule{{Type: "decision", Policy: "never"}, {Project: "github.com/acme/api", Policy: "spaced_repetition"}}
+	if err := validateDecay(d); err != nil {
+		t.Fatalf("validateDecay() error = %v", err)
+	}
+	d.Rules = []DecayRule{{Policy: "never"}}
+	if err := validateDecay(d); err == nil {
+		t.Fatalf("expected validation error for a rule without type or project")
+	}
+	d.Rules = []DecayRule{{Type: "decision", Policy: "sometimes"}}
+	if err := validateDecay(d); err == nil {
+		t.Fatalf("expected validation error for a rule with an unknown policy")
+	}
+}
+
+func TestValidateRetentionRule(t *testing.T) {
+	half := 0.5
+	if err := validateRetentionRule(RetentionRule{Type: "conversation", TTL: 6 * time.Hour, PromoteImportance: &half}); err != nil {
+		t.Fatalf("validateRetentionRule() error = %v", err)
+	}
+	tooHigh := 1.5
+	for _, r := range []RetentionRule{
+		{Type: "conversation"},
+		{TTL: time.Millisecond},
+		{MaxCount: -1},
+		{PromoteImportance: &tooHigh},
+	} {
+		if err := validateRetentionRule(r); err == nil {
+			t.Errorf("expected validation error for %+v", r)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+-- Contextify: Embedding model tracking and online re-embedding
+
+-- Which model produced memories.embedding, plus a shadow column that the
+-- re-embed job fills with the target model's vectors. The shadow column has
+-- no fixed dimensions so it can hold vectors for a model of a different size.
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS embedding_model TEXT;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS embedding_next vector;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS embedding_next_model TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_memories_embedding_model ON memories(embedding_model);
+
+-- The model currently serving HybridSearch. Single row.
+CREATE TABLE IF NOT EXISTS embedding_state (
+    id          BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    provider    TEXT NOT NULL,
+    model       TEXT NOT NULL,
+    dimensions  INTEGER NOT NULL CHECK (dimensions > 0),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Re-embedding runs. At most one migration is running or paused at a time.
+CREATE TABLE IF NOT EXISTS embedding_migrations (
+    id                UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source_model      TEXT NOT NULL,
+    source_dimensions INTEGER NOT NULL,
+    target_provider   TEXT NOT NULL,
+    target_model      TEXT NOT NULL,
+    target_dimensions INTEGER NOT NULL CHECK (target_dimensions > 0),
+    status            TEXT NOT NULL DEFAULT 'running',
+    total             INTEGER NOT NULL DEFAULT 0,
+    processed         INTEGER NOT NULL DEFAULT 0,
+    failed            INTEGER NOT NULL DEFAULT 0,
+    cursor_id         UUID,
+    last_error        TEXT,
+    started_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at      TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_embedding_migrations_active
+    ON embedding_migrations ((TRUE))
+    WHERE status IN ('running', 'paused');
+
+CREATE INDEX IF NOT EXISTS idx_embedding_migrations_started
+    ON embedding_migrations (started_at DESC);
+
+CREATE TRIGGER embedding_migrations_updated_at
+    BEFORE UPDATE ON embedding_migrations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+-- Re-embed batches and the cutover touch every row; they set
+-- contextify.skip_updated_at for the transaction so that maintenance writes
+-- do not reorder memories by recency.
+CREATE OR REPLACE FUNCTION update_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('contextify.skip_updated_at', true) = 'on' THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,72 @@
+-- Contextify: Memory version history
+-- Every insert or content-bearing update of a memory stores a full snapshot.
+
+CREATE TABLE IF NOT EXISTS memory_versions (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    memory_id      UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    version_no     INTEGER NOT NULL,
+    title          TEXT NOT NULL,
+    content        TEXT NOT NULL,
+    summary        TEXT,
+    type           memory_type NOT NULL,
+    scope          memory_scope NOT NULL,
+    project_id     TEXT,
+    tags           TEXT[] NOT NULL DEFAULT '{}',
+    importance     REAL NOT NULL,
+    change_reason  TEXT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (memory_id, version_no)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_versions_memory ON memory_versions(memory_id, version_no DESC);
+
+-- Writers may label a change with SET LOCAL contextify.change_reason; otherwise
+-- the reason is inferred: create, merge (memories.version bumped) or update.
+CREATE OR REPLACE FUNCTION record_memory_version()
+RETURNS TRIGGER AS $$
+DECLARE
+    reason TEXT;
+BEGIN
+    IF TG_OP = 'UPDATE' AND
+       (NEW.title, NEW.content, NEW.summary, NEW.type, NEW.scope, NEW.project_id, NEW.tags, NEW.importance)
+       IS NOT DISTINCT FROM
+       (OLD.title, OLD.content, OLD.summary, OLD.type, OLD.scope, OLD.project_id, OLD.tags, OLD.importance) THEN
+        RETURN NEW;
+    END IF;
+
+    reason := NULLIF(current_setting('contextify.change_reason', true), '');
+    IF reason IS NULL THEN
+        IF TG_OP = 'INSERT' THEN
+            reason := 'create';
+        ELSIF NEW.version <> OLD.version THEN
+            reason := 'merge';
+        ELSE
+            reason := 'update';
+        END IF;
+    END IF;
+
+    INSERT INTO memory_versions (
+        memory_id, version_no, title, content, summary, type, scope, project_id, tags, importance, change_reason
+    )
+    VALUES (
+        NEW.id,
+        COALESCE((SELECT MAX(version_no) FROM memory_versions WHERE memory_id = NEW.id), 0) + 1,
+        NEW.title, NEW.content, NEW.summary, NEW.type, NEW.scope, NEW.project_id,
+        COALESCE(NEW.tags, '{}'), NEW.importance, reason
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER memories_record_version
+    AFTER INSERT OR UPDATE ON memories
+    FOR EACH ROW
+    EXECUTE FUNCTION record_memory_version();
+
+-- Existing memories start their history with a baseline snapshot.
+INSERT INTO memory_versions (
+    memory_id, version_no, title, content, summary, type, scope, project_id, tags, importance, change_reason, created_at
+)
+SELECT id, 1, title, content, summary, type, scope, project_id, COALESCE(tags, '{}'), importance, 'baseline', updated_at
+FROM memories
+ON CONFLICT (memory_id, version_no) DO NOTHING;
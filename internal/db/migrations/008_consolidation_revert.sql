@@ -0,0 +1,6 @@
+-- Contextify: Reverting consolidations
+-- A revert is itself logged (merge_strategy = 'revert', reverts_id = original)
+-- and the original entry is stamped with reverted_at so it is undone once.
+
+ALTER TABLE consolidation_log ADD COLUMN IF NOT EXISTS reverted_at TIMESTAMPTZ;
+ALTER TABLE consolidation_log ADD COLUMN IF NOT EXISTS reverts_id UUID REFERENCES consolidation_log(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+-- Contextify: API tokens
+-- Only a SHA-256 hash of each token is stored; the raw value is shown once at
+-- creation. prefix keeps tokens recognisable in listings.
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id            UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name          TEXT NOT NULL,
+    prefix        TEXT NOT NULL,
+    token_hash    TEXT NOT NULL UNIQUE,
+    scope         TEXT NOT NULL CHECK (scope IN ('read', 'write', 'admin')),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at  TIMESTAMPTZ,
+    expires_at    TIMESTAMPTZ,
+    revoked_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_created ON api_tokens(created_at DESC);
@@ -0,0 +1,20 @@
+-- Contextify: Workspaces
+-- A workspace isolates one team's memories from another's on a shared
+-- instance; scope = 'global' means global within the workspace. The workspace
+-- comes from the caller's API token. Existing rows land in 'default', which is
+-- also where requests go when authentication is disabled.
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE memory_relationships ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE consolidation_suggestions ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE consolidation_log ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE memory_telemetry_events ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE steward_jobs ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE api_tokens ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_memories_workspace_project ON memories(workspace_id, project_id);
+CREATE INDEX IF NOT EXISTS idx_relationships_workspace ON memory_relationships(workspace_id);
+CREATE INDEX IF NOT EXISTS idx_suggestions_workspace_status ON consolidation_suggestions(workspace_id, status);
+CREATE INDEX IF NOT EXISTS idx_consolidation_log_workspace_created ON consolidation_log(workspace_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_telemetry_workspace_created ON memory_telemetry_events(workspace_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_steward_jobs_workspace_status ON steward_jobs(workspace_id, status);
@@ -0,0 +1,44 @@
+-- Contextify: Relationship types
+-- Relationship types are now validated against a registry in code and stored
+-- under their canonical upper snake case name. Existing free-text names are
+-- normalized; known inverse names (e.g. SOLVED_BY) become their canonical type
+-- with the ends swapped. Edges that collapse onto the same canonical edge keep
+-- the strongest one. Unknown names are normalized but otherwise left alone.
+
+CREATE TEMP TABLE relationship_canon ON COMMIT DROP AS
+WITH inverses(inverse, name) AS (
+    VALUES ('SOLVED_BY', 'SOLVES'),
+           ('CAUSED_BY', 'CAUSES'),
+           ('REQUIRED_BY', 'REQUIRES'),
+           ('ADDRESSED_BY', 'ADDRESSES'),
+           ('SUPERSEDED_BY', 'SUPERSEDES'),
+           ('SOURCE_OF', 'DERIVED_FROM')
+), normalized AS (
+    SELECT id, from_memory_id, to_memory_id, strength,
+           upper(trim(BOTH '_' FROM regexp_replace(trim(relationship), '[\s_-]+', '_', 'g'))) AS name
+    FROM memory_relationships
+)
+SELECT n.id,
+       COALESCE(i.name, n.name) AS name,
+       CASE WHEN i.name IS NULL THEN n.from_memory_id ELSE n.to_memory_id END AS from_id,
+       CASE WHEN i.name IS NULL THEN n.to_memory_id ELSE n.from_memory_id END AS to_id,
+       row_number() OVER (
+           PARTITION BY CASE WHEN i.name IS NULL THEN n.from_memory_id ELSE n.to_memory_id END,
+                        CASE WHEN i.name IS NULL THEN n.to_memory_id ELSE n.from_memory_id END,
+                        COALESCE(i.name, n.name)
+           ORDER BY COALESCE(n.strength, 0) DESC, n.id
+       ) AS rank
+FROM normalized n
+LEFT JOIN inverses i ON i.inverse = n.name;
+
+DELETE FROM memory_relationships r
+USING relationship_canon c
+WHERE r.id = c.id AND c.rank > 1;
+
+UPDATE memory_relationships r
+SET relationship = c.name, from_memory_id = c.from_id, to_memory_id = c.to_id
+FROM relationship_canon c
+WHERE r.id = c.id
+  AND (r.relationship <> c.name OR r.from_memory_id <> c.from_id);
+
+CREATE INDEX IF NOT EXISTS idx_relationships_workspace_type ON memory_relationships(workspace_id, relationship);
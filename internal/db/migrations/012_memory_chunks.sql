@@ -0,0 +1,36 @@
+-- Contextify: Chunked embeddings
+-- Long memories are split into overlapping chunks, each embedded on its own,
+-- so search can match a passage deep inside a pasted log or design doc.
+-- Offsets are character positions in memories.content. The vector column has
+-- no fixed dimensions: chunks record the model that produced them and search
+-- only compares chunks from the active model.
+
+CREATE TABLE IF NOT EXISTS memory_chunks (
+    memory_id       UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    chunk_index     INTEGER NOT NULL,
+    start_offset    INTEGER NOT NULL,
+    end_offset      INTEGER NOT NULL,
+    embedding       vector NOT NULL,
+    embedding_model TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (memory_id, chunk_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_chunks_model ON memory_chunks(embedding_model);
+
+-- Chunks describe one version of the text; any change drops them and the
+-- chunk indexer rebuilds them.
+CREATE OR REPLACE FUNCTION clear_memory_chunks()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.title, NEW.content) IS DISTINCT FROM (OLD.title, OLD.content) THEN
+        DELETE FROM memory_chunks WHERE memory_id = NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER memories_clear_chunks
+    AFTER UPDATE OF title, content ON memories
+    FOR EACH ROW
+    EXECUTE FUNCTION clear_memory_chunks();
@@ -0,0 +1,17 @@
+-- Contextify: Per-memory text search language
+-- Keyword search used the english configuration for every memory, so Turkish,
+-- German or code-heavy memories were stemmed and stopword-filtered as English.
+-- Each memory now records the text search configuration for its text
+-- ('simple' for code) and keeps a generated tsvector built with it.
+-- regconfig makes the generated expression immutable; existing rows stay
+-- english, which is what they were indexed with before.
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS language regconfig NOT NULL DEFAULT 'english';
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector(language, COALESCE(title, '') || ' ' || COALESCE(content, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_memories_search_vector ON memories USING gin (search_vector);
+
+-- Superseded by idx_memories_search_vector, which also covers the title.
+DROP INDEX IF EXISTS idx_memories_content_fts;
@@ -0,0 +1,65 @@
+-- Contextify: Symbol index
+-- Code memories are full of identifiers (Repository.HybridSearch,
+-- snake_case_names) and file paths that text search stems or splits apart.
+-- memory_symbols() extracts them, lowercased, so search can match them
+-- exactly or by prefix:
+--   * every identifier inside fenced code blocks and `inline code`
+--   * identifiers elsewhere that look like code: snake_case, camelCase, and
+--     names qualified with ., :: or ->, plus each part of a qualified name
+--   * file paths and their base names
+-- The function is immutable so it can back a generated column; the column is
+-- filled for existing rows when it is added.
+
+CREATE OR REPLACE FUNCTION memory_symbols(title TEXT, content TEXT)
+RETURNS TEXT[]
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $fn$
+    WITH body AS (
+        SELECT COALESCE(title, '') || E'\n' || COALESCE(content, '') AS t
+    ),
+    code AS (
+        SELECT m[1] AS t
+        FROM body, regexp_matches(body.t, '```[^\n]*?\n(.*?)```', 'g') AS m
+        UNION ALL
+        SELECT m[1]
+        FROM body, regexp_matches(body.t, '`([^`\n]+)`', 'g') AS m
+    ),
+    names AS (
+        SELECT m[1] AS s, true AS in_code
+        FROM code, regexp_matches(code.t, '([A-Za-z_$][A-Za-z0-9_$]*(?:(?:\.|::|->)[A-Za-z_$][A-Za-z0-9_$]*)*)', 'g') AS m
+        UNION ALL
+        SELECT m[1], false
+        FROM body, regexp_matches(body.t, '([A-Za-z_$][A-Za-z0-9_$]*(?:(?:\.|::|->)[A-Za-z_$][A-Za-z0-9_$]*)*)', 'g') AS m
+    ),
+    identifiers AS (
+        SELECT s FROM names
+        WHERE (in_code OR s ~ '[_$]|\.|::|->|[a-z0-9][A-Z]')
+          -- "e.g" and "i.e" are not names
+          AND s !~ '(\.|::|->)[A-Za-z_$]$'
+    ),
+    paths AS (
+        SELECT m[1] AS s
+        FROM body, regexp_matches(body.t, '((?:[A-Za-z0-9_.~-]*/)+[A-Za-z0-9_.-]*[A-Za-z0-9_])', 'g') AS m
+    ),
+    symbols AS (
+        SELECT s FROM identifiers
+        UNION ALL
+        SELECT p FROM identifiers, regexp_split_to_table(s, '\.|::|->') AS p WHERE s ~ '\.|::|->'
+        UNION ALL
+        SELECT s FROM paths
+        UNION ALL
+        SELECT regexp_replace(s, '^.*/', '') FROM paths
+    )
+    SELECT COALESCE(array_agg(s), '{}') FROM (
+        SELECT DISTINCT lower(s) AS s
+        FROM symbols
+        WHERE length(s) BETWEEN 3 AND 200
+        ORDER BY 1
+        LIMIT 500
+    ) kept
+$fn$;
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS symbols TEXT[]
+    GENERATED ALWAYS AS (memory_symbols(title, content)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_memories_symbols ON memories USING gin (symbols);
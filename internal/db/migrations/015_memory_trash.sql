@@ -0,0 +1,13 @@
+-- Contextify: Trash
+-- Deleting a memory, or its TTL running out, moves it to the trash instead of
+-- removing the row: relationships, versions and chunks survive and the memory
+-- can be restored. Trashed memories are hidden from every read and search.
+-- The cleanup scheduler purges them once memory.trash_retention has passed.
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS deleted_reason TEXT
+    CHECK (deleted_reason IN ('deleted', 'expired'));
+
+CREATE INDEX IF NOT EXISTS idx_memories_deleted_at
+    ON memories (workspace_id, deleted_at DESC, id)
+    WHERE deleted_at IS NOT NULL;
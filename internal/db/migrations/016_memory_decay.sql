@@ -0,0 +1,12 @@
+-- Contextify: Decay policies
+-- Short-term memories expire according to a decay policy chosen per type or
+-- project (memory.decay). Policies that reinforce on use need to know when a
+-- memory was last read; NULL means never, and policies count from created_at.
+-- The cleanup scheduler re-evaluates every short-term memory, so changing a
+-- policy in config also applies to memories stored before the change.
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS last_accessed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_memories_decay
+    ON memories (id)
+    WHERE ttl_seconds IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,11 @@
+-- Contextify: Retention rules
+-- Each workspace can set retention rules through the admin API, on top of
+-- the memory.retention rules in config.yaml. A workspace's rules are kept as
+-- one ordered JSON list, since their order decides which rule's TTL and
+-- promotion thresholds apply.
+
+CREATE TABLE IF NOT EXISTS retention_rules (
+    workspace_id TEXT PRIMARY KEY,
+    rules        JSONB NOT NULL DEFAULT '[]',
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
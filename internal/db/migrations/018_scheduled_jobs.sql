@@ -0,0 +1,31 @@
+-- Contextify: Scheduled jobs
+-- The background jobs (TTL cleanup, dedup scan, project normalization) run
+-- on the instance holding the scheduler's advisory lock. Their state lives
+-- here so pauses, manual triggers and the next run survive restarts and
+-- apply whichever instance leads.
+
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    name                 TEXT PRIMARY KEY,
+    paused               BOOLEAN NOT NULL DEFAULT FALSE,
+    next_run_at          TIMESTAMPTZ,
+    trigger_requested_at TIMESTAMPTZ,
+    run_count            BIGINT NOT NULL DEFAULT 0,
+    failure_count        BIGINT NOT NULL DEFAULT 0,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One row per run; the scheduler keeps the most recent runs of each job.
+CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_name    TEXT NOT NULL REFERENCES scheduled_jobs(name) ON DELETE CASCADE,
+    triggered_by TEXT NOT NULL CHECK (triggered_by IN ('schedule', 'manual')),
+    status      TEXT NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    started_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    duration_ms BIGINT,
+    error       TEXT,
+    result      JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_job
+    ON scheduled_job_runs (job_name, started_at DESC);
@@ -0,0 +1,7 @@
+-- Contextify: instance token scope
+-- instance tokens may run operations that affect every workspace
+-- (re-embedding, project normalization, scheduler and steward control).
+
+ALTER TABLE api_tokens DROP CONSTRAINT IF EXISTS api_tokens_scope_check;
+ALTER TABLE api_tokens ADD CONSTRAINT api_tokens_scope_check
+    CHECK (scope IN ('read', 'write', 'admin', 'instance'));
@@ -0,0 +1,63 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+const (
+	ProviderOllama = "ollama"
+	ProviderOpenAI = "openai"
+	ProviderHash   = "hash"
+)
+
+// Embedder generates vector embeddings for text. Implementations must return
+// vectors of Dimensions() length so they fit the memories.embedding column.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+	// EnsureModel prepares the backing model (pull, warm-up). It is a no-op
+	// for providers that have nothing to prepare.
+	EnsureModel(ctx context.Context) error
+	Dimensions() int
+	Model() string
+}
+
+// New returns the Embedder selected by cfg.Provider, which config.Load has
+// normalized to lower case. An empty provider is Ollama.
+func New(cfg config.EmbeddingConfig) (Embedder, error) {
+	switch cfg.Provider {
+	case "", ProviderOllama:
+		return NewOllamaClient(cfg), nil
+	case ProviderOpenAI:
+		return NewOpenAIClient(cfg), nil
+	case ProviderHash:
+		return NewHashEmbedder(cfg.Dimensions), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", cfg.Provider)
+	}
+}
+
+// embedOne runs a single-text batch and unwraps the result.
+func embedOne(ctx context.Context, e Embedder, text string) ([]float32, error) {
+	results, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("empty embedding response")
+	}
+	return results[0], nil
+}
+
+// NewWithModel returns an Embedder that reuses cfg's endpoints and
+// credentials but targets a specific provider, model and dimensions. It is
+// used to keep serving a previously active model during a re-embed.
+func NewWithModel(cfg config.EmbeddingConfig, provider, model string, dimensions int) (Embedder, error) {
+	cfg.Provider = provider
+	cfg.Model = model
+	cfg.Dimensions = dimensions
+	return New(cfg)
+}
@@ -0,0 +1,126 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+func TestNew_SelectsProvider(t *testing.T) {
+	cases := map[string]string{
+		"ollama": "*embedding.OllamaClient",
+		"openai": "*embedding.OpenAIClient",
+		"hash":   "*embedding.HashEmbedder",
+		"":       "*embedding.OllamaClient",
+	}
+	for provider, want := range cases {
+		e, err := New(config.EmbeddingConfig{Provider: provider, Dimensions: 8})
+		if err != nil {
+			t.Fatalf("provider %q: unexpected error: %v", provider, err)
+		}
+		if got := fmt.Sprintf("%T", e); got != want {
+			t.Fatalf("provider %q: expected %s, got %s", provider, want, got)
+		}
+	}
+	for _, provider := range []string{"nope", "OpenAI"} {
+		if _, err := New(config.EmbeddingConfig{Provider: provider}); err == nil {
+			t.Fatalf("expected error for provider %q", provider)
+		}
+	}
+}
+
+func TestHashEmbedder_DeterministicAndNormalized(t *testing.T) {
+	h := NewHashEmbedder(64)
+	a, err := h.Embed(context.Background(), "Redis timeout tuning")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := h.Embed(context.Background(), "redis TIMEOUT tuning")
+	if len(a) != 64 {
+		t.Fatalf("expected 64 dimensions, got %d", len(a))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected identical vectors for case-insensitive input")
+		}
+	}
+	var norm float64
+	for _, v := range a {
+		norm += float64(v) * float64(v)
+	}
+	if math.Abs(norm-1) > 1e-5 {
+		t.Fatalf("expected unit vector, got norm^2=%f", norm)
+	}
+}
+
+func TestHashEmbedder_SharedVocabularyIsCloser(t *testing.T) {
+	h := NewHashEmbedder(256)
+	vecs, _ := h.EmbedBatch(context.Background(), []string{
+		"postgres connection pool exhausted",
+		"postgres connection pool size",
+		"frontend button color",
+	})
+	if dot(vecs[0], vecs[1]) <= dot(vecs[0], vecs[2]) {
+		t.Fatalf("expected overlapping texts to be more similar")
+	}
+}
+
+func TestHashEmbedder_EmptyInputIsNonZero(t *testing.T) {
+	v, _ := NewHashEmbedder(8).Embed(context.Background(), "")
+	if dot(v, v) == 0 {
+		t.Fatalf("expected non-zero vector for empty input")
+	}
+}
+
+func TestOpenAIClient_EmbedBatchOrdersByIndex(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		var req openAIEmbedRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "text-embedding-3-small" || len(req.Input) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewOpenAIClient(config.EmbeddingConfig{BaseURL: srv.URL + "/", APIKey: "secret", Model: "text-embedding-3-small", Dimensions: 2})
+	out, err := c.EmbedBatch(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out[0][0] != 1 || out[1][1] != 1 {
+		t.Fatalf("expected results in input order, got %v", out)
+	}
+}
+
+func TestOpenAIClient_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	c := NewOpenAIClient(config.EmbeddingConfig{BaseURL: srv.URL, Model: "m", Dimensions: 2})
+	if _, err := c.Embed(context.Background(), "x"); err == nil {
+		t.Fatalf("expected error on non-200 response")
+	}
+}
+
+func dot(a, b []float32) float64 {
+	var s float64
+	for i := range a {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
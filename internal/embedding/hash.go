@@ -0,0 +1,99 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// HashEmbedder is a deterministic, dependency-free embedder based on feature
+// hashing of word unigrams and bigrams. It has no semantic understanding but
+// gives stable vectors where shared vocabulary means higher cosine similarity,
+// which is enough for tests and air-gapped installs without a model server.
+type HashEmbedder struct {
+	dimensions int
+}
+
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = 768
+	}
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Embed generates an embedding for a single text.
+func (h *HashEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return embedOne(ctx, h, text)
+}
+
+// EmbedBatch generates embeddings for multiple texts.
+func (h *HashEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out[i] = h.vector(t)
+	}
+	return out, nil
+}
+
+// EnsureModel is a no-op: the hashing embedder has no model to prepare.
+func (h *HashEmbedder) EnsureModel(ctx context.Context) error {
+	return nil
+}
+
+// Dimensions returns the embedding dimensions.
+func (h *HashEmbedder) Dimensions() int {
+	return h.dimensions
+}
+
+// Model returns a stable identifier for the hashing scheme.
+func (h *HashEmbedder) Model() string {
+	return fmt.Sprintf("hash-%d", h.dimensions)
+}
+
+func (h *HashEmbedder) vector(text string) []float32 {
+	vec := make([]float32, h.dimensions)
+	tokens := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, tok := range tokens {
+		h.add(vec, tok, 1.0)
+		if i > 0 {
+			h.add(vec, tokens[i-1]+" "+tok, 0.5)
+		}
+	}
+
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		// pgvector rejects cosine distance on zero vectors; keep empty input usable.
+		vec[0] = 1
+		return vec
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vec {
+		vec[i] *= scale
+	}
+	return vec
+}
+
+// add hashes a feature into a bucket; a second hash bit picks the sign so
+// collisions cancel out on average instead of accumulating.
+func (h *HashEmbedder) add(vec []float32, feature string, weight float32) {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(feature))
+	sum := hasher.Sum64()
+	idx := int(sum % uint64(h.dimensions))
+	if (sum>>63)&1 == 1 {
+		weight = -weight
+	}
+	vec[idx] += weight
+}
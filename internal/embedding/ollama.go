@@ -13,7 +13,8 @@ import (
 	"github.com/atakanatali/contextify/internal/config"
 )
 
-type Client struct {
+// OllamaClient embeds text through Ollama's /api/embed endpoint.
+type OllamaClient struct {
 	baseURL    string
 	model      string
 	dimensions int
@@ -29,8 +30,8 @@ type embedResponse struct {
 	Embeddings [][]float32 `json:"embeddings"`
 }
 
-func NewClient(cfg config.EmbeddingConfig) *Client {
-	return &Client{
+func NewOllamaClient(cfg config.EmbeddingConfig) *OllamaClient {
+	return &OllamaClient{
 		baseURL:    cfg.OllamaURL,
 		model:      cfg.Model,
 		dimensions: cfg.Dimensions,
@@ -39,7 +40,7 @@ func NewClient(cfg config.EmbeddingConfig) *Client {
 }
 
 // Embed generates an embedding for a single text.
-func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+func (c *OllamaClient) Embed(ctx context.Context, text string) ([]float32, error) {
 	results, err := c.EmbedBatch(ctx, []string{text})
 	if err != nil {
 		return nil, err
@@ -51,7 +52,7 @@ func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
 }
 
 // EmbedBatch generates embeddings for multiple texts in a single request.
-func (c *Client) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+func (c *OllamaClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	reqBody := embedRequest{
 		Model: c.model,
 		Input: texts,
@@ -88,7 +89,7 @@ func (c *Client) EmbedBatch(ctx context.Context, texts []string) ([][]float32, e
 }
 
 // EnsureModel pulls the embedding model if it's not already available.
-func (c *Client) EnsureModel(ctx context.Context) error {
+func (c *OllamaClient) EnsureModel(ctx context.Context) error {
 	slog.Info("checking embedding model", "model", c.model)
 
 	// Check if model exists
@@ -135,6 +136,11 @@ func (c *Client) EnsureModel(ctx context.Context) error {
 }
 
 // Dimensions returns the expected embedding dimensions.
-func (c *Client) Dimensions() int {
+func (c *OllamaClient) Dimensions() int {
 	return c.dimensions
 }
+
+// Model returns the configured embedding model name.
+func (c *OllamaClient) Model() string {
+	return c.model
+}
@@ -0,0 +1,123 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+// OpenAIClient embeds text through any OpenAI-compatible /v1/embeddings
+// endpoint (OpenAI, llama.cpp server, vLLM, LocalAI).
+type OpenAIClient struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	dimensions int
+	httpClient *http.Client
+}
+
+type openAIEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func NewOpenAIClient(cfg config.EmbeddingConfig) *OpenAIClient {
+	return &OpenAIClient{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:     cfg.APIKey,
+		model:      cfg.Model,
+		dimensions: cfg.Dimensions,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Embed generates an embedding for a single text.
+func (c *OpenAIClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	return embedOne(ctx, c, text)
+}
+
+// EmbedBatch generates embeddings for multiple texts in a single request.
+// Results are returned in input order regardless of the server's ordering.
+func (c *OpenAIClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(openAIEmbedRequest{Model: c.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshal embed request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send embed request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("openai embed failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode embed response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("openai embed returned %d embeddings for %d inputs", len(result.Data), len(texts))
+	}
+
+	sort.Slice(result.Data, func(i, j int) bool { return result.Data[i].Index < result.Data[j].Index })
+	out := make([][]float32, len(result.Data))
+	for i, d := range result.Data {
+		out[i] = d.Embedding
+	}
+	return out, nil
+}
+
+// EnsureModel verifies the endpoint is reachable and that the model produces
+// vectors of the configured dimensions. Remote servers manage their own
+// models, so nothing is pulled.
+func (c *OpenAIClient) EnsureModel(ctx context.Context) error {
+	slog.Info("checking embedding endpoint", "provider", ProviderOpenAI, "base_url", c.baseURL, "model", c.model)
+	vec, err := c.Embed(ctx, "contextify")
+	if err != nil {
+		return fmt.Errorf("probe embedding endpoint: %w", err)
+	}
+	if c.dimensions > 0 && len(vec) != c.dimensions {
+		return fmt.Errorf("embedding model %s returned %d dimensions, expected %d", c.model, len(vec), c.dimensions)
+	}
+	slog.Info("embedding endpoint available", "model", c.model, "dimensions", len(vec))
+	return nil
+}
+
+// Dimensions returns the expected embedding dimensions.
+func (c *OpenAIClient) Dimensions() int {
+	return c.dimensions
+}
+
+// Model returns the configured embedding model name.
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
@@ -2,21 +2,28 @@ package mcp
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/atakanatali/contextify/internal/auth"
 	"github.com/atakanatali/contextify/internal/memory"
 )
 
 type Server struct {
 	mcpServer *mcp.Server
 	svc       *memory.Service
+	authSvc   *auth.Service
 }
 
-func NewServer(svc *memory.Service) *Server {
-	s := &Server{svc: svc}
+func NewServer(svc *memory.Service, authSvc *auth.Service) *Server {
+	s := &Server{svc: svc, authSvc: authSvc}
 
 	s.mcpServer = mcp.NewServer(&mcp.Implementation{
 		Name:    "contextify",
@@ -41,6 +48,11 @@ func (s *Server) Handler() http.Handler {
 		return s.mcpServer
 	}, nil)
 
+	var handler http.Handler = base
+	if s.authEnabled() {
+		handler = mcpauth.RequireBearerToken(s.verifyToken, nil)(base)
+	}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		reqID := r.Header.Get("X-Request-ID")
 		if reqID == "" {
@@ -54,10 +66,61 @@ func (s *Server) Handler() http.Handler {
 			ctx = context.WithValue(ctx, "session_id", sessionID)
 		}
 
-		base.ServeHTTP(w, r.WithContext(ctx))
+		handler.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+func (s *Server) authEnabled() bool {
+	return s.authSvc != nil && s.authSvc.Enabled()
+}
+
+// verifyToken adapts API tokens to the SDK's bearer-token middleware. The
+// token ID becomes the session user so a session cannot be reused with
+// another token; the workspace travels in Extra.
+func (s *Server) verifyToken(ctx context.Context, raw string, _ *http.Request) (*mcpauth.TokenInfo, error) {
+	tok, err := s.authSvc.Authenticate(ctx, raw)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, fmt.Errorf("%w: %v", mcpauth.ErrInvalidToken, err)
+		}
+		return nil, err
+	}
+	// The SDK requires an expiry; tokens are re-verified on every request.
+	exp := time.Now().Add(time.Hour)
+	if tok.ExpiresAt != nil {
+		exp = *tok.ExpiresAt
+	}
+	return &mcpauth.TokenInfo{
+		Scopes:     tok.Scope.Implied(),
+		Expiration: exp,
+		UserID:     tok.ID.String(),
+		Extra:      map[string]any{workspaceExtraKey: tok.Workspace},
+	}, nil
+}
+
+const workspaceExtraKey = "workspace"
+
+// requireScope wraps a tool handler so it only runs for tokens that include
+// scope, scoped to the token's workspace. With authentication disabled every
+// call passes and runs in the default workspace.
+func requireScope[In any](s *Server, scope auth.Scope, h mcp.ToolHandlerFor[In, any]) mcp.ToolHandlerFor[In, any] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, any, error) {
+		if s.authEnabled() {
+			var info *mcpauth.TokenInfo
+			if req.Extra != nil {
+				info = req.Extra.TokenInfo
+			}
+			if info == nil || !slices.Contains(info.Scopes, string(scope)) {
+				return nil, nil, fmt.Errorf("token scope does not allow this tool; requires %s", scope)
+			}
+			if ws, ok := info.Extra[workspaceExtraKey].(string); ok {
+				ctx = memory.WithWorkspace(ctx, ws)
+			}
+		}
+		return h(ctx, req, input)
+	}
+}
+
 func (s *Server) MCPServer() *mcp.Server {
 	return s.mcpServer
 }
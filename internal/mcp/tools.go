@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/atakanatali/contextify/internal/auth"
 	"github.com/atakanatali/contextify/internal/memory"
 )
 
@@ -24,27 +26,36 @@ type StoreMemoryInput struct {
 	Tags        []string `json:"tags,omitempty" jsonschema:"Tags for categorization"`
 	Importance  float32  `json:"importance" jsonschema:"Importance score 0.0-1.0"`
 	TTLSeconds  *int     `json:"ttl_seconds,omitempty" jsonschema:"Time-to-live in seconds. Null for permanent."`
+	Language    string   `json:"language,omitempty" jsonschema:"Text search language, e.g. english, turkish, german, or simple for code. Detected when omitted."`
 }
 
 type RecallInput struct {
-	Query         string   `json:"query" jsonschema:"Natural language query,required"`
-	ProjectID     *string  `json:"project_id,omitempty" jsonschema:"Filter by project"`
-	Tags          []string `json:"tags,omitempty" jsonschema:"Filter by tags"`
-	Type          *string  `json:"type,omitempty" jsonschema:"Filter by memory type"`
-	MinImportance *float32 `json:"min_importance,omitempty" jsonschema:"Minimum importance threshold"`
-	Limit         int      `json:"limit,omitempty" jsonschema:"Max results (default 20)"`
+	Query         string                 `json:"query" jsonschema:"Natural language query, or 'symbol:' followed by identifiers or file paths to match exactly (a trailing * matches by prefix),required"`
+	ProjectID     *string                `json:"project_id,omitempty" jsonschema:"Filter by project"`
+	Tags          []string               `json:"tags,omitempty" jsonschema:"Filter by tags"`
+	Type          *string                `json:"type,omitempty" jsonschema:"Filter by memory type"`
+	MinImportance *float32               `json:"min_importance,omitempty" jsonschema:"Minimum importance threshold"`
+	Limit         int                    `json:"limit,omitempty" jsonschema:"Max results (default 20)"`
+	Ranking       string                 `json:"ranking,omitempty" jsonschema:"Ranking strategy: linear, rrf (reciprocal rank fusion) or max (default from server config)"`
+	Modifiers     *memory.ScoreModifiers `json:"modifiers,omitempty" jsonschema:"Override score modifiers: recency_half_life_days, recency_weight (0-1), importance_weight, access_weight"`
+	Explain       bool                   `json:"explain,omitempty" jsonschema:"Include per-result score components, matched terms and highlighted snippets"`
+	Rerank        *bool                  `json:"rerank,omitempty" jsonschema:"Rescore the top results with a local model (default from server config)"`
 }
 
 type SearchInput struct {
-	Query         string   `json:"query,omitempty" jsonschema:"Search query"`
-	Tags          []string `json:"tags,omitempty" jsonschema:"Filter by tags"`
-	Type          *string  `json:"type,omitempty" jsonschema:"Filter by type"`
-	Scope         *string  `json:"scope,omitempty" jsonschema:"Filter by scope"`
-	ProjectID     *string  `json:"project_id,omitempty" jsonschema:"Filter by project"`
-	AgentSource   *string  `json:"agent_source,omitempty" jsonschema:"Filter by agent source"`
-	MinImportance *float32 `json:"min_importance,omitempty" jsonschema:"Minimum importance"`
-	Limit         int      `json:"limit,omitempty" jsonschema:"Max results"`
-	Offset        int      `json:"offset,omitempty" jsonschema:"Pagination offset"`
+	Query         string                 `json:"query,omitempty" jsonschema:"Search query; 'symbol:Repository.HybridSearch parse_*' matches identifiers and file paths exactly or by prefix"`
+	Tags          []string               `json:"tags,omitempty" jsonschema:"Filter by tags"`
+	Type          *string                `json:"type,omitempty" jsonschema:"Filter by type"`
+	Scope         *string                `json:"scope,omitempty" jsonschema:"Filter by scope"`
+	ProjectID     *string                `json:"project_id,omitempty" jsonschema:"Filter by project"`
+	AgentSource   *string                `json:"agent_source,omitempty" jsonschema:"Filter by agent source"`
+	MinImportance *float32               `json:"min_importance,omitempty" jsonschema:"Minimum importance"`
+	Limit         int                    `json:"limit,omitempty" jsonschema:"Max results"`
+	Offset        int                    `json:"offset,omitempty" jsonschema:"Pagination offset"`
+	Ranking       string                 `json:"ranking,omitempty" jsonschema:"Ranking strategy: linear, rrf (reciprocal rank fusion) or max"`
+	Modifiers     *memory.ScoreModifiers `json:"modifiers,omitempty" jsonschema:"Override score modifiers: recency_half_life_days, recency_weight (0-1), importance_weight, access_weight"`
+	Rerank        *bool                  `json:"rerank,omitempty" jsonschema:"Rescore the top results with a local model (default from server config)"`
+	Filter        string                 `json:"filter,omitempty" jsonschema:"Filter expression, e.g. 'tag:redis,postgres -tag:wip lang:turkish created:>=2024-01-01 updated:7d access:>=3 has:relationship include:expired'"`
 }
 
 type GetMemoryInput struct {
@@ -59,6 +70,7 @@ type UpdateMemoryInput struct {
 	Type       *string  `json:"type,omitempty" jsonschema:"New type"`
 	Tags       []string `json:"tags,omitempty" jsonschema:"New tags"`
 	Importance *float32 `json:"importance,omitempty" jsonschema:"New importance"`
+	Language   *string  `json:"language,omitempty" jsonschema:"New text search language, e.g. english or simple"`
 }
 
 type DeleteMemoryInput struct {
@@ -68,16 +80,45 @@ type DeleteMemoryInput struct {
 type CreateRelationshipInput struct {
 	FromMemoryID string  `json:"from_memory_id" jsonschema:"Source memory UUID,required"`
 	ToMemoryID   string  `json:"to_memory_id" jsonschema:"Target memory UUID,required"`
-	Relationship string  `json:"relationship" jsonschema:"Type: SOLVES|CAUSES|RELATED_TO|REQUIRES|ADDRESSES|SUPERSEDES,required"`
+	Relationship string  `json:"relationship" jsonschema:"Type: SOLVES|CAUSES|RELATED_TO|REQUIRES|ADDRESSES|SUPERSEDES|DERIVED_FROM, or an inverse such as SOLVED_BY,required"`
 	Strength     float32 `json:"strength,omitempty" jsonschema:"Strength 0.0-1.0"`
 	Context      *string `json:"context,omitempty" jsonschema:"Description of the relationship"`
 }
 
+type ListRelationshipsInput struct {
+	ProjectID         *string  `json:"project_id,omitempty" jsonschema:"Only relationships touching memories of this project"`
+	MemoryID          *string  `json:"memory_id,omitempty" jsonschema:"Only relationships touching this memory UUID"`
+	RelationshipTypes []string `json:"relationship_types,omitempty" jsonschema:"Filter by relationship types"`
+	Limit             int      `json:"limit,omitempty" jsonschema:"Max results (default 50)"`
+	Offset            int      `json:"offset,omitempty" jsonschema:"Results to skip"`
+}
+
+type UpdateRelationshipInput struct {
+	RelationshipID string   `json:"relationship_id" jsonschema:"Relationship UUID,required"`
+	Relationship   *string  `json:"relationship,omitempty" jsonschema:"New type; an inverse name such as SOLVED_BY swaps the ends"`
+	Strength       *float32 `json:"strength,omitempty" jsonschema:"New strength 0.0-1.0"`
+	Context        *string  `json:"context,omitempty" jsonschema:"New description; empty clears it"`
+}
+
+type DeleteRelationshipInput struct {
+	RelationshipID string `json:"relationship_id" jsonschema:"Relationship UUID,required"`
+}
+
 type GetRelatedInput struct {
 	MemoryID          string   `json:"memory_id" jsonschema:"Memory UUID,required"`
 	RelationshipTypes []string `json:"relationship_types,omitempty" jsonschema:"Filter by relationship types"`
 }
 
+type TraverseGraphInput struct {
+	MemoryID          string   `json:"memory_id" jsonschema:"Memory UUID to start from,required"`
+	TargetID          *string  `json:"target_id,omitempty" jsonschema:"If set, return the shortest path to this memory instead of the surrounding subgraph"`
+	Depth             int      `json:"depth,omitempty" jsonschema:"Max hops (default 2, max 5)"`
+	RelationshipTypes []string `json:"relationship_types,omitempty" jsonschema:"Only follow these relationship types"`
+	Direction         string   `json:"direction,omitempty" jsonschema:"Follow edges outgoing|incoming|both (default both)"`
+	MinStrength       float32  `json:"min_strength,omitempty" jsonschema:"Ignore edges weaker than this (0.0-1.0)"`
+	Limit             int      `json:"limit,omitempty" jsonschema:"Max memories returned (default 100)"`
+}
+
 type GetContextInput struct {
 	ProjectID string `json:"project_id" jsonschema:"Project identifier,required"`
 }
@@ -86,6 +127,31 @@ type PromoteMemoryInput struct {
 	MemoryID string `json:"memory_id" jsonschema:"Memory UUID to promote,required"`
 }
 
+type ListExpiringInput struct {
+	ProjectID *string `json:"project_id,omitempty" jsonschema:"Filter by project"`
+	Within    string  `json:"within,omitempty" jsonschema:"How far ahead to look, e.g. 6h (default from server config, 24h)"`
+	Limit     int     `json:"limit,omitempty" jsonschema:"Max memories (default 20)"`
+}
+
+type ReviewExpiringInput struct {
+	MemoryID string `json:"memory_id" jsonschema:"Memory UUID,required"`
+	Action   string `json:"action" jsonschema:"keep (another TTL), promote (long-term) or expire (trash now),required"`
+}
+
+// --- Version history tool inputs ---
+
+type GetMemoryHistoryInput struct {
+	MemoryID    string `json:"memory_id" jsonschema:"Memory UUID,required"`
+	FromVersion int    `json:"from_version,omitempty" jsonschema:"Include a unified diff starting at this version"`
+	ToVersion   int    `json:"to_version,omitempty" jsonschema:"Diff end version (default latest)"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Max versions (default 20)"`
+}
+
+type RestoreMemoryVersionInput struct {
+	MemoryID string `json:"memory_id" jsonschema:"Memory UUID,required"`
+	Version  int    `json:"version" jsonschema:"Version number to restore,required"`
+}
+
 // --- Consolidation tool inputs ---
 
 type ConsolidateMemoriesInput struct {
@@ -109,68 +175,109 @@ func (s *Server) registerTools() {
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "store_memory",
 		Description: "Store a new memory with automatic embedding generation. Memories with importance >= 0.8 are automatically permanent.",
-	}, s.storeMemory)
+	}, requireScope(s, auth.ScopeWrite, s.storeMemory))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "recall_memories",
 		Description: "Recall memories using natural language semantic search. Best for fuzzy/conceptual queries.",
-	}, s.recallMemories)
+	}, requireScope(s, auth.ScopeRead, s.recallMemories))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "search_memories",
 		Description: "Advanced search with filters (tags, type, scope, importance). Use for precise filtering.",
-	}, s.searchMemories)
+	}, requireScope(s, auth.ScopeRead, s.searchMemories))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "get_memory",
 		Description: "Retrieve a specific memory by its ID.",
-	}, s.getMemory)
+	}, requireScope(s, auth.ScopeRead, s.getMemory))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "update_memory",
 		Description: "Update an existing memory. Re-embeds if content changes.",
-	}, s.updateMemory)
+	}, requireScope(s, auth.ScopeWrite, s.updateMemory))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "delete_memory",
-		Description: "Delete a memory and all its relationships.",
-	}, s.deleteMemory)
+		Description: "Move a memory to the trash. It stops appearing in search and can be restored with its relationships until the trash retention period ends.",
+	}, requireScope(s, auth.ScopeAdmin, s.deleteMemory))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "create_relationship",
-		Description: "Link two memories with a typed relationship (SOLVES, CAUSES, RELATED_TO, REQUIRES, ADDRESSES, SUPERSEDES).",
-	}, s.createRelationship)
+		Description: "Link two memories with a typed relationship (SOLVES, CAUSES, RELATED_TO, REQUIRES, ADDRESSES, SUPERSEDES, DERIVED_FROM). Inverse names such as SOLVED_BY or CAUSED_BY are accepted and stored the canonical way round.",
+	}, requireScope(s, auth.ScopeWrite, s.createRelationship))
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_relationships",
+		Description: "List relationships, strongest first, optionally filtered by project, memory or type.",
+	}, requireScope(s, auth.ScopeRead, s.listRelationships))
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "update_relationship",
+		Description: "Change a relationship's type, strength or context.",
+	}, requireScope(s, auth.ScopeWrite, s.updateRelationship))
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "delete_relationship",
+		Description: "Delete a wrong or stale relationship. The linked memories are kept.",
+	}, requireScope(s, auth.ScopeAdmin, s.deleteRelationship))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "get_related_memories",
 		Description: "Find memories connected to a specific memory via relationships.",
-	}, s.getRelatedMemories)
+	}, requireScope(s, auth.ScopeRead, s.getRelatedMemories))
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "traverse_memory_graph",
+		Description: "Walk relationships several hops from a memory, e.g. from an error through what caused it to what solved it. Returns the reachable memories with their distance and the edges between them, or with target_id the shortest path between two memories.",
+	}, requireScope(s, auth.ScopeRead, s.traverseMemoryGraph))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "get_context",
 		Description: "Get all important memories for a project. Use at session start to load context.",
-	}, s.getContext)
+	}, requireScope(s, auth.ScopeRead, s.getContext))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "promote_memory",
 		Description: "Manually promote a short-term memory to permanent long-term storage.",
-	}, s.promoteMemory)
+	}, requireScope(s, auth.ScopeWrite, s.promoteMemory))
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_expiring_memories",
+		Description: "List short-term memories that are about to expire, soonest first, with how often and how recently they were read. Review them with review_expiring_memory before cleanup moves them to the trash.",
+	}, requireScope(s, auth.ScopeRead, s.listExpiring))
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "review_expiring_memory",
+		Description: "Decide on a short-term memory before it expires: keep it for another TTL, promote it to long-term, or expire it now (it stays restorable from the trash).",
+	}, requireScope(s, auth.ScopeWrite, s.reviewExpiring))
+
+	// Version history tools
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_memory_history",
+		Description: "List past versions of a memory, newest first. Pass from_version (and optionally to_version) to include a unified diff.",
+	}, requireScope(s, auth.ScopeRead, s.getMemoryHistory))
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "restore_memory_version",
+		Description: "Restore a memory to a past version. The restore is recorded as a new version, so it can be undone.",
+	}, requireScope(s, auth.ScopeWrite, s.restoreMemoryVersion))
 
 	// Consolidation tools
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "consolidate_memories",
 		Description: "Merge multiple memories into one target memory. Source memories are marked as superseded. Use when you find duplicate or overlapping memories.",
-	}, s.consolidateMemories)
+	}, requireScope(s, auth.ScopeWrite, s.consolidateMemories))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "find_similar",
 		Description: "Find memories similar to a given memory ID using vector similarity. Useful for detecting duplicates.",
-	}, s.findSimilar)
+	}, requireScope(s, auth.ScopeRead, s.findSimilar))
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "suggest_consolidations",
 		Description: "Get server-generated suggestions for memories that should be consolidated. Returns pairs of similar memories.",
-	}, s.suggestConsolidations)
+	}, requireScope(s, auth.ScopeRead, s.suggestConsolidations))
 }
 
 func (s *Server) storeMemory(ctx context.Context, req *mcp.CallToolRequest, input *StoreMemoryInput) (*mcp.CallToolResult, any, error) {
@@ -194,6 +301,7 @@ func (s *Server) storeMemory(ctx context.Context, req *mcp.CallToolRequest, inpu
 		Tags:        input.Tags,
 		Importance:  input.Importance,
 		TTLSeconds:  input.TTLSeconds,
+		Language:    input.Language,
 	}
 
 	result, err := s.svc.Store(ctx, storeReq)
@@ -221,6 +329,10 @@ func (s *Server) recallMemories(ctx context.Context, req *mcp.CallToolRequest, i
 		ProjectID: input.ProjectID,
 		Tags:      input.Tags,
 		Limit:     input.Limit,
+		Ranking:   memory.RankingStrategy(input.Ranking),
+		Modifiers: input.Modifiers,
+		Explain:   input.Explain,
+		Rerank:    input.Rerank,
 	}
 	if input.Type != nil {
 		t := memory.MemoryType(*input.Type)
@@ -245,6 +357,10 @@ func (s *Server) searchMemories(ctx context.Context, req *mcp.CallToolRequest, i
 		MinImportance: input.MinImportance,
 		Limit:         input.Limit,
 		Offset:        input.Offset,
+		Ranking:       memory.RankingStrategy(input.Ranking),
+		Modifiers:     input.Modifiers,
+		Rerank:        input.Rerank,
+		Filter:        input.Filter,
 	}
 	if input.Type != nil {
 		t := memory.MemoryType(*input.Type)
@@ -292,6 +408,7 @@ func (s *Server) updateMemory(ctx context.Context, req *mcp.CallToolRequest, inp
 		Summary:    input.Summary,
 		Tags:       input.Tags,
 		Importance: input.Importance,
+		Language:   input.Language,
 	}
 	if input.Type != nil {
 		t := memory.MemoryType(*input.Type)
@@ -316,7 +433,7 @@ func (s *Server) deleteMemory(ctx context.Context, req *mcp.CallToolRequest, inp
 		return nil, nil, fmt.Errorf("delete memory: %w", err)
 	}
 
-	return makeTextResult(fmt.Sprintf("Deleted memory: %s", id)), nil, nil
+	return makeTextResult(fmt.Sprintf("Moved memory to trash: %s", id)), nil, nil
 }
 
 func (s *Server) createRelationship(ctx context.Context, req *mcp.CallToolRequest, input *CreateRelationshipInput) (*mcp.CallToolResult, any, error) {
@@ -346,6 +463,60 @@ func (s *Server) createRelationship(ctx context.Context, req *mcp.CallToolReques
 		rel.FromMemoryID, rel.Relationship, rel.ToMemoryID, rel.ID)), nil, nil
 }
 
+func (s *Server) listRelationships(ctx context.Context, req *mcp.CallToolRequest, input *ListRelationshipsInput) (*mcp.CallToolResult, any, error) {
+	f := memory.RelationshipFilter{
+		ProjectID: input.ProjectID,
+		Types:     input.RelationshipTypes,
+		Limit:     input.Limit,
+		Offset:    input.Offset,
+	}
+	if input.MemoryID != nil {
+		id, err := uuid.Parse(*input.MemoryID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid memory_id: %w", err)
+		}
+		f.MemoryID = &id
+	}
+
+	list, err := s.svc.ListRelationships(ctx, f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list relationships: %w", err)
+	}
+	return makeJSONResult(list)
+}
+
+func (s *Server) updateRelationship(ctx context.Context, req *mcp.CallToolRequest, input *UpdateRelationshipInput) (*mcp.CallToolResult, any, error) {
+	id, err := uuid.Parse(input.RelationshipID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid relationship_id: %w", err)
+	}
+
+	rel, err := s.svc.UpdateRelationship(ctx, id, memory.RelationshipUpdate{
+		Relationship: input.Relationship,
+		Strength:     input.Strength,
+		Context:      input.Context,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("update relationship: %w", err)
+	}
+
+	return makeTextResult(fmt.Sprintf("Updated relationship: %s -[%s %.2f]-> %s (id: %s)",
+		rel.FromMemoryID, rel.Relationship, rel.Strength, rel.ToMemoryID, rel.ID)), nil, nil
+}
+
+func (s *Server) deleteRelationship(ctx context.Context, req *mcp.CallToolRequest, input *DeleteRelationshipInput) (*mcp.CallToolResult, any, error) {
+	id, err := uuid.Parse(input.RelationshipID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid relationship_id: %w", err)
+	}
+
+	if err := s.svc.DeleteRelationship(ctx, id); err != nil {
+		return nil, nil, fmt.Errorf("delete relationship: %w", err)
+	}
+
+	return makeTextResult(fmt.Sprintf("Deleted relationship: %s", id)), nil, nil
+}
+
 func (s *Server) getRelatedMemories(ctx context.Context, req *mcp.CallToolRequest, input *GetRelatedInput) (*mcp.CallToolResult, any, error) {
 	id, err := uuid.Parse(input.MemoryID)
 	if err != nil {
@@ -364,6 +535,39 @@ func (s *Server) getRelatedMemories(ctx context.Context, req *mcp.CallToolReques
 	return makeJSONResult(result)
 }
 
+func (s *Server) traverseMemoryGraph(ctx context.Context, req *mcp.CallToolRequest, input *TraverseGraphInput) (*mcp.CallToolResult, any, error) {
+	id, err := uuid.Parse(input.MemoryID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid memory_id: %w", err)
+	}
+
+	graphReq := memory.GraphRequest{
+		Depth:             input.Depth,
+		RelationshipTypes: input.RelationshipTypes,
+		Direction:         memory.GraphDirection(input.Direction),
+		MinStrength:       input.MinStrength,
+		Limit:             input.Limit,
+	}
+
+	if input.TargetID != nil && *input.TargetID != "" {
+		targetID, err := uuid.Parse(*input.TargetID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid target_id: %w", err)
+		}
+		path, err := s.svc.ShortestPath(ctx, id, targetID, graphReq)
+		if err != nil {
+			return nil, nil, fmt.Errorf("shortest path: %w", err)
+		}
+		return makeJSONResult(path)
+	}
+
+	graph, err := s.svc.TraverseGraph(ctx, id, graphReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("traverse graph: %w", err)
+	}
+	return makeJSONResult(graph)
+}
+
 func (s *Server) getContext(ctx context.Context, req *mcp.CallToolRequest, input *GetContextInput) (*mcp.CallToolResult, any, error) {
 	memories, err := s.svc.GetContext(ctx, input.ProjectID)
 	if err != nil {
@@ -386,6 +590,87 @@ func (s *Server) promoteMemory(ctx context.Context, req *mcp.CallToolRequest, in
 	return makeTextResult(fmt.Sprintf("Promoted memory %s to long-term storage", id)), nil, nil
 }
 
+func (s *Server) listExpiring(ctx context.Context, req *mcp.CallToolRequest, input *ListExpiringInput) (*mcp.CallToolResult, any, error) {
+	f := memory.ExpiringFilter{ProjectID: input.ProjectID}
+	if input.Within != "" {
+		d, err := time.ParseDuration(input.Within)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("invalid within %q: must be a positive duration such as 24h", input.Within)
+		}
+		f.Within = d
+	}
+
+	page, err := s.svc.ListExpiring(ctx, f, input.Limit, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("list expiring memories: %w", err)
+	}
+	return makeJSONResult(page)
+}
+
+func (s *Server) reviewExpiring(ctx context.Context, req *mcp.CallToolRequest, input *ReviewExpiringInput) (*mcp.CallToolResult, any, error) {
+	id, err := uuid.Parse(input.MemoryID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid memory_id: %w", err)
+	}
+
+	review, err := s.svc.ReviewExpiring(ctx, id, input.Action)
+	if err != nil {
+		return nil, nil, fmt.Errorf("review expiring memory: %w", err)
+	}
+
+	switch {
+	case review.Action != input.Action:
+		return makeTextResult(fmt.Sprintf("Promoted memory %s to long-term storage: its decay policy made it permanent", id)), nil, nil
+	case review.ExpiresAt != nil:
+		return makeTextResult(fmt.Sprintf("Kept memory %s until %s", id, review.ExpiresAt.Format(time.RFC3339))), nil, nil
+	case review.Action == memory.ReviewPromote:
+		return makeTextResult(fmt.Sprintf("Promoted memory %s to long-term storage", id)), nil, nil
+	default:
+		return makeTextResult(fmt.Sprintf("Moved memory %s to trash as expired", id)), nil, nil
+	}
+}
+
+// --- Version history tool handlers ---
+
+func (s *Server) getMemoryHistory(ctx context.Context, req *mcp.CallToolRequest, input *GetMemoryHistoryInput) (*mcp.CallToolResult, any, error) {
+	id, err := uuid.Parse(input.MemoryID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid memory_id: %w", err)
+	}
+
+	versions, total, err := s.svc.ListVersions(ctx, id, input.Limit, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get memory history: %w", err)
+	}
+
+	result := map[string]any{
+		"versions": versions,
+		"total":    total,
+	}
+	if input.FromVersion > 0 || input.ToVersion > 0 {
+		diff, err := s.svc.DiffVersions(ctx, id, input.FromVersion, input.ToVersion)
+		if err != nil {
+			return nil, nil, fmt.Errorf("diff versions: %w", err)
+		}
+		result["diff"] = diff
+	}
+	return makeJSONResult(result)
+}
+
+func (s *Server) restoreMemoryVersion(ctx context.Context, req *mcp.CallToolRequest, input *RestoreMemoryVersionInput) (*mcp.CallToolResult, any, error) {
+	id, err := uuid.Parse(input.MemoryID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid memory_id: %w", err)
+	}
+
+	mem, err := s.svc.RestoreVersion(ctx, id, input.Version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("restore version: %w", err)
+	}
+
+	return makeJSONResult(mem)
+}
+
 // --- Consolidation tool handlers ---
 
 func (s *Server) consolidateMemories(ctx context.Context, req *mcp.CallToolRequest, input *ConsolidateMemoriesInput) (*mcp.CallToolResult, any, error) {
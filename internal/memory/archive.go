@@ -0,0 +1,501 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+)
+
+// An archive is JSON Lines: a header record, then memories, then the
+// relationships and consolidation history between them. Later records refer
+// to memories by their archived IDs, so the order matters on import.
+const (
+	ArchiveFormat  = "contextify-export"
+	ArchiveVersion = 1
+)
+
+const (
+	RecordHeader        = "header"
+	RecordMemory        = "memory"
+	RecordRelationship  = "relationship"
+	RecordConsolidation = "consolidation"
+)
+
+// maxImportErrors caps the per-record errors reported back by Import.
+const maxImportErrors = 100
+
+// ArchiveRecord is one line of an archive; exactly one payload is set,
+// matching Kind.
+type ArchiveRecord struct {
+	Kind          string            `json:"kind"`
+	Header        *ArchiveHeader    `json:"header,omitempty"`
+	Memory        *ArchivedMemory   `json:"memory,omitempty"`
+	Relationship  *Relationship     `json:"relationship,omitempty"`
+	Consolidation *ConsolidationLog `json:"consolidation,omitempty"`
+}
+
+type ArchiveHeader struct {
+	Format              string       `json:"format"`
+	Version             int          `json:"version"`
+	ExportedAt          time.Time    `json:"exported_at"`
+	Workspace           string       `json:"workspace"`
+	EmbeddingModel      string       `json:"embedding_model"`
+	EmbeddingDimensions int          `json:"embedding_dimensions"`
+	Filter              ExportFilter `json:"filter"`
+}
+
+// ArchivedMemory is a memory as written to an archive. Vector is only present
+// when the export asked for embeddings.
+type ArchivedMemory struct {
+	Memory
+	Vector []float32 `json:"embedding,omitempty"`
+}
+
+// ExportFilter selects the memories written by Export. Relationships and
+// consolidation history follow the selected memories.
+type ExportFilter struct {
+	ProjectID         *string      `json:"project_id,omitempty"`
+	Types             []MemoryType `json:"types,omitempty"`
+	Tags              []string     `json:"tags,omitempty"` // memory must carry all of them
+	From              *time.Time   `json:"from,omitempty"` // created_at >= From
+	To                *time.Time   `json:"to,omitempty"`   // created_at < To
+	IncludeEmbeddings bool         `json:"include_embeddings,omitempty"`
+}
+
+type ExportStats struct {
+	Memories       int `json:"memories"`
+	Relationships  int `json:"relationships"`
+	Consolidations int `json:"consolidations"`
+}
+
+// ConflictMode decides what Import does with a memory whose ID already exists.
+type ConflictMode string
+
+const (
+	ConflictSkip      ConflictMode = "skip"      // keep the stored memory
+	ConflictOverwrite ConflictMode = "overwrite" // replace it with the archived one
+	ConflictRemap     ConflictMode = "remap"     // import under a new ID
+)
+
+// ValidConflictModes contains the accepted values for ImportOptions.OnConflict.
+var ValidConflictModes = map[ConflictMode]bool{
+	ConflictSkip: true, ConflictOverwrite: true, ConflictRemap: true,
+}
+
+type ImportOptions struct {
+	OnConflict ConflictMode `json:"on_conflict"`
+	// Reembed recomputes every embedding. Otherwise archived vectors are
+	// reused when they match the active model, and missing ones are computed.
+	Reembed bool `json:"reembed"`
+	// Dedup passes new memories through Store, so one that is nearly
+	// identical to a stored memory is merged into it instead.
+	Dedup bool `json:"dedup"`
+}
+
+type ImportResult struct {
+	Memories       ImportCounts `json:"memories"`
+	Relationships  ImportCounts `json:"relationships"`
+	Consolidations ImportCounts `json:"consolidations"`
+	Errors         []string     `json:"errors,omitempty"`
+}
+
+// ImportCounts tallies one record kind. Remapped counts imports that got a
+// new ID and is included in Created or Merged.
+type ImportCounts struct {
+	Created     int `json:"created"`
+	Merged      int `json:"merged,omitempty"`
+	Overwritten int `json:"overwritten,omitempty"`
+	Remapped    int `json:"remapped,omitempty"`
+	Skipped     int `json:"skipped"`
+	Failed      int `json:"failed"`
+}
+
+// Export streams the workspace's memories matching f, followed by their
+// relationships and consolidation history, to w as an archive.
+func (s *Service) Export(ctx context.Context, w io.Writer, f ExportFilter) (*ExportStats, error) {
+	s.normalizeProjectPtr(f.ProjectID)
+	enc := json.NewEncoder(w)
+	emb := s.activeEmbedder()
+
+	header := &ArchiveHeader{
+		Format:              ArchiveFormat,
+		Version:             ArchiveVersion,
+		ExportedAt:          time.Now().UTC(),
+		Workspace:           WorkspaceFromContext(ctx),
+		EmbeddingModel:      emb.Model(),
+		EmbeddingDimensions: emb.Dimensions(),
+		Filter:              f,
+	}
+	if err := enc.Encode(ArchiveRecord{Kind: RecordHeader, Header: header}); err != nil {
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+
+	stats := &ExportStats{}
+	var ids []uuid.UUID
+	err := s.repo.ExportMemories(ctx, f, func(m *Memory) error {
+		rec := &ArchivedMemory{Memory: *m}
+		if m.Embedding != nil {
+			rec.Vector = m.Embedding.Slice()
+		}
+		ids = append(ids, m.ID)
+		stats.Memories++
+		return enc.Encode(ArchiveRecord{Kind: RecordMemory, Memory: rec})
+	})
+	if err != nil {
+		return stats, err
+	}
+	if len(ids) == 0 {
+		return stats, nil
+	}
+
+	err = s.repo.ExportRelationships(ctx, ids, func(rel *Relationship) error {
+		stats.Relationships++
+		return enc.Encode(ArchiveRecord{Kind: RecordRelationship, Relationship: rel})
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	err = s.repo.ExportConsolidationLog(ctx, ids, func(l *ConsolidationLog) error {
+		stats.Consolidations++
+		return enc.Encode(ArchiveRecord{Kind: RecordConsolidation, Consolidation: l})
+	})
+	return stats, err
+}
+
+// Import reads an archive from r into the caller's workspace. A malformed
+// header or stream aborts with ErrInvalidArchive; invalid individual records
+// are counted as failed and reported in the result.
+func (s *Service) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	if opts.OnConflict == "" {
+		opts.OnConflict = ConflictSkip
+	}
+	if !ValidConflictModes[opts.OnConflict] {
+		return nil, fmt.Errorf("unknown conflict mode %q", opts.OnConflict)
+	}
+
+	dec := json.NewDecoder(r)
+	var first ArchiveRecord
+	if err := dec.Decode(&first); err != nil {
+		return nil, fmt.Errorf("%w: read header: %v", ErrInvalidArchive, err)
+	}
+	if err := checkArchiveHeader(&first); err != nil {
+		return nil, err
+	}
+
+	imp := &importer{
+		s:         s,
+		opts:      opts,
+		result:    &ImportResult{},
+		ids:       map[uuid.UUID]uuid.UUID{},
+		logIDs:    map[uuid.UUID]uuid.UUID{},
+		workspace: WorkspaceFromContext(ctx),
+	}
+	defer s.invalidateSearchCache(ctx)
+
+	var err error
+	for n := 2; ; n++ {
+		var rec ArchiveRecord
+		if err = dec.Decode(&rec); errors.Is(err, io.EOF) {
+			err = nil
+			break
+		}
+		if err != nil {
+			err = fmt.Errorf("%w: record %d: %v", ErrInvalidArchive, n, err)
+			break
+		}
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		imp.record(ctx, n, &rec)
+	}
+
+	// Whatever was imported before an abort stays, so finish its wiring.
+	imp.markReplaced(ctx)
+	if err != nil {
+		return imp.result, err
+	}
+	slog.Info("imported archive",
+		"workspace", imp.workspace,
+		"memories_created", imp.result.Memories.Created,
+		"memories_merged", imp.result.Memories.Merged,
+		"memories_skipped", imp.result.Memories.Skipped,
+		"failed", imp.result.Memories.Failed+imp.result.Relationships.Failed+imp.result.Consolidations.Failed,
+	)
+	return imp.result, nil
+}
+
+func checkArchiveHeader(rec *ArchiveRecord) error {
+	if rec.Kind != RecordHeader || rec.Header == nil {
+		return fmt.Errorf("%w: first record must be a header, got %q", ErrInvalidArchive, rec.Kind)
+	}
+	if rec.Header.Format != ArchiveFormat {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, rec.Header.Format)
+	}
+	if rec.Header.Version < 1 || rec.Header.Version > ArchiveVersion {
+		return fmt.Errorf("%w: unsupported version %d (this server reads up to %d)", ErrInvalidArchive, rec.Header.Version, ArchiveVersion)
+	}
+	return nil
+}
+
+// importer holds the state of one Import: archived IDs map to the IDs the
+// records were stored under, so later records can be rewired.
+type importer struct {
+	s         *Service
+	opts      ImportOptions
+	result    *ImportResult
+	ids       map[uuid.UUID]uuid.UUID
+	logIDs    map[uuid.UUID]uuid.UUID
+	workspace string
+
+	// replaced is applied after all memories are in, since a memory may be
+	// archived before the one that replaced it.
+	replaced []replacement
+}
+
+type replacement struct {
+	id, archivedTarget uuid.UUID
+}
+
+func (imp *importer) fail(counts *ImportCounts, n int, err error) {
+	counts.Failed++
+	if len(imp.result.Errors) < maxImportErrors {
+		imp.result.Errors = append(imp.result.Errors, fmt.Sprintf("record %d: %v", n, err))
+	}
+}
+
+func (imp *importer) record(ctx context.Context, n int, rec *ArchiveRecord) {
+	switch {
+	case rec.Kind == RecordMemory && rec.Memory != nil:
+		if err := imp.memory(ctx, rec.Memory); err != nil {
+			imp.fail(&imp.result.Memories, n, err)
+		}
+	case rec.Kind == RecordRelationship && rec.Relationship != nil:
+		if err := imp.relationship(ctx, rec.Relationship); err != nil {
+			imp.fail(&imp.result.Relationships, n, err)
+		}
+	case rec.Kind == RecordConsolidation && rec.Consolidation != nil:
+		if err := imp.consolidation(ctx, rec.Consolidation); err != nil {
+			imp.fail(&imp.result.Consolidations, n, err)
+		}
+	default:
+		if len(imp.result.Errors) < maxImportErrors {
+			imp.result.Errors = append(imp.result.Errors, fmt.Sprintf("record %d: unexpected %q record ignored", n, rec.Kind))
+		}
+	}
+}
+
+func (imp *importer) memory(ctx context.Context, am *ArchivedMemory) error {
+	counts := &imp.result.Memories
+	mem := am.Memory
+	if mem.ID == uuid.Nil {
+		return errors.New("memory without id")
+	}
+	if mem.Title == "" || mem.Content == "" {
+		return fmt.Errorf("memory %s: title and content are required", mem.ID)
+	}
+	archivedID := mem.ID
+	mem.Type = NormalizeType(mem.Type)
+	if mem.Scope == "" {
+		mem.Scope = ScopeProject
+	}
+	if mem.Tags == nil {
+		mem.Tags = []string{}
+	}
+	if !ValidLanguage(mem.Language) {
+		// Archives written before memories had a language.
+		mem.Language = imp.s.textLanguage(mem.Title, mem.Content)
+	}
+	if mem.CreatedAt.IsZero() {
+		mem.CreatedAt = time.Now()
+	}
+	if mem.UpdatedAt.IsZero() {
+		mem.UpdatedAt = mem.CreatedAt
+	}
+	imp.s.normalizeProjectPtr(mem.ProjectID)
+
+	owner, err := imp.s.repo.MemoryWorkspace(ctx, archivedID)
+	if err != nil {
+		return err
+	}
+	remapped := false
+	switch {
+	case owner == "":
+	case owner == imp.workspace && imp.opts.OnConflict == ConflictSkip:
+		imp.ids[archivedID] = archivedID
+		counts.Skipped++
+		return nil
+	case owner == imp.workspace && imp.opts.OnConflict == ConflictOverwrite:
+		emb, model, err := imp.s.importEmbedding(ctx, am, imp.opts.Reembed)
+		if err != nil {
+			return fmt.Errorf("memory %s: embed: %w", archivedID, err)
+		}
+		mem.Embedding, mem.EmbeddingModel = &emb, &model
+		if err := imp.s.repo.OverwriteMemory(ctx, &mem); err != nil {
+			return err
+		}
+		imp.ids[archivedID] = archivedID
+		counts.Overwritten++
+		return nil
+	default:
+		// Remap requested, or the ID belongs to another workspace, which an
+		// import must never touch.
+		mem.ID = uuid.New()
+		remapped = true
+	}
+
+	emb, model, err := imp.s.importEmbedding(ctx, am, imp.opts.Reembed)
+	if err != nil {
+		return fmt.Errorf("memory %s: embed: %w", archivedID, err)
+	}
+	mem.Embedding, mem.EmbeddingModel = &emb, &model
+
+	storedID := mem.ID
+	switch {
+	case mem.ReplacedBy != nil:
+		// Superseded memories are history: keep them as they were.
+		imp.replaced = append(imp.replaced, replacement{id: mem.ID, archivedTarget: *mem.ReplacedBy})
+		mem.ReplacedBy = nil
+		if err := imp.s.repo.ImportMemory(ctx, &mem); err != nil {
+			return err
+		}
+		counts.Created++
+	case imp.opts.Dedup:
+		result, err := imp.s.Store(ctx, StoreRequest{
+			Title:       mem.Title,
+			Content:     mem.Content,
+			Summary:     mem.Summary,
+			Type:        mem.Type,
+			Scope:       mem.Scope,
+			ProjectID:   mem.ProjectID,
+			AgentSource: mem.AgentSource,
+			Tags:        mem.Tags,
+			Importance:  mem.Importance,
+			TTLSeconds:  mem.TTLSeconds,
+			Language:    mem.Language,
+			imported:    &mem,
+		})
+		if err != nil {
+			return err
+		}
+		if result.Memory == nil {
+			return fmt.Errorf("memory %s: store returned no memory", archivedID)
+		}
+		storedID = result.Memory.ID
+		if result.Action == "updated" {
+			counts.Merged++
+		} else {
+			counts.Created++
+		}
+	default:
+		if err := imp.s.repo.ImportMemory(ctx, &mem); err != nil {
+			return err
+		}
+		counts.Created++
+	}
+
+	if remapped {
+		counts.Remapped++
+	}
+	imp.ids[archivedID] = storedID
+	return nil
+}
+
+func (imp *importer) relationship(ctx context.Context, rel *Relationship) error {
+	counts := &imp.result.Relationships
+	from, okFrom := imp.ids[rel.FromMemoryID]
+	to, okTo := imp.ids[rel.ToMemoryID]
+	if !okFrom || !okTo || from == to {
+		counts.Skipped++
+		return nil
+	}
+	name, inverted, err := ResolveRelationshipType(rel.Relationship)
+	if err != nil {
+		return err
+	}
+	if inverted {
+		from, to = to, from
+	}
+	imported := *rel
+	imported.ID = uuid.New()
+	imported.FromMemoryID, imported.ToMemoryID = from, to
+	imported.Relationship = name
+	if err := imp.s.repo.StoreRelationship(ctx, &imported); err != nil {
+		return err
+	}
+	counts.Created++
+	return nil
+}
+
+func (imp *importer) consolidation(ctx context.Context, l *ConsolidationLog) error {
+	counts := &imp.result.Consolidations
+	target, ok := imp.ids[l.TargetID]
+	if !ok {
+		counts.Skipped++
+		return nil
+	}
+	imported := *l
+	imported.TargetID = target
+	imported.SourceIDs = make([]uuid.UUID, len(l.SourceIDs))
+	for i, id := range l.SourceIDs {
+		if mapped, ok := imp.ids[id]; ok {
+			id = mapped
+		}
+		imported.SourceIDs[i] = id
+	}
+	if target != l.TargetID {
+		imported.ID = uuid.New()
+	}
+	if imported.CreatedAt.IsZero() {
+		imported.CreatedAt = time.Now()
+	}
+	imported.RevertsID = nil
+	if l.RevertsID != nil {
+		if mapped, ok := imp.logIDs[*l.RevertsID]; ok {
+			imported.RevertsID = &mapped
+		}
+	}
+
+	inserted, err := imp.s.repo.ImportConsolidationLog(ctx, &imported)
+	if err != nil {
+		return err
+	}
+	imp.logIDs[l.ID] = imported.ID
+	if !inserted {
+		counts.Skipped++
+		return nil
+	}
+	counts.Created++
+	return nil
+}
+
+// markReplaced restores replaced_by on imported superseded memories. Targets
+// outside the archive keep their archived ID, so cleanup still purges them.
+func (imp *importer) markReplaced(ctx context.Context) {
+	for _, r := range imp.replaced {
+		target := r.archivedTarget
+		if mapped, ok := imp.ids[target]; ok {
+			target = mapped
+		}
+		if err := imp.s.repo.MarkReplaced(ctx, r.id, target); err != nil {
+			slog.Warn("failed to restore replaced_by on import", "id", r.id, "error", err)
+		}
+	}
+}
+
+// importEmbedding returns the vector to store for an archived memory: the
+// archived one if it came from the active model, a fresh one otherwise.
+func (s *Service) importEmbedding(ctx context.Context, am *ArchivedMemory, reembed bool) (pgvector.Vector, string, error) {
+	e := s.activeEmbedder()
+	if !reembed && len(am.Vector) == e.Dimensions() && am.EmbeddingModel != nil && *am.EmbeddingModel == e.Model() {
+		return pgvector.NewVector(am.Vector), e.Model(), nil
+	}
+	return s.embed(ctx, am.Title+" "+am.Content)
+}
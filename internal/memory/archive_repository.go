@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ExportMemories calls fn for every memory in the workspace matching f,
+// oldest first, including ones replaced by a merge. Rows are streamed, so fn
+// must not call back into the repository.
+func (r *Repository) ExportMemories(ctx context.Context, f ExportFilter, fn func(*Memory) error) error {
+	conditions := []string{"workspace_id = $1"}
+	args := []any{WorkspaceFromContext(ctx), f.IncludeEmbeddings}
+	argIdx := 3
+
+	if f.ProjectID != nil {
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", argIdx))
+		args = append(args, *f.ProjectID)
+		argIdx++
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			types[i] = string(t)
+		}
+		conditions = append(conditions, fmt.Sprintf("type::text = ANY($%d)", argIdx))
+		args = append(args, types)
+		argIdx++
+	}
+	if len(f.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf("tags @> $%d", argIdx))
+		args = append(args, f.Tags)
+		argIdx++
+	}
+	if f.From != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIdx))
+		args = append(args, *f.From)
+		argIdx++
+	}
+	if f.To != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", argIdx))
+		args = append(args, *f.To)
+		argIdx++
+	}
+
+	conditions = append(conditions, "deleted_at IS NULL")
+	query := fmt.Sprintf(`
+		SELECT id, title, content, summary, CASE WHEN $2 THEN embedding END, embedding_model,
+		       type, scope, project_id, agent_source, language::text, tags, importance, ttl_seconds, access_count,
+		       created_at, updated_at, expires_at, version, merged_from, replaced_by
+		FROM memories
+		WHERE %s
+		ORDER BY created_at, id
+	`, strings.Join(conditions, " AND "))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("export memories: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m Memory
+		err := rows.Scan(
+			&m.ID, &m.Title, &m.Content, &m.Summary, &m.Embedding, &m.EmbeddingModel,
+			&m.Type, &m.Scope, &m.ProjectID, &m.AgentSource, &m.Language, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
+			&m.CreatedAt, &m.UpdatedAt, &m.ExpiresAt, &m.Version, &m.MergedFrom, &m.ReplacedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("scan exported memory: %w", err)
+		}
+		if err := fn(&m); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ExportRelationships calls fn for every relationship whose two ends are
+// both among ids.
+func (r *Repository) ExportRelationships(ctx context.Context, ids []uuid.UUID, fn func(*Relationship) error) error {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, from_memory_id, to_memory_id, relationship, strength, context, created_at
+		FROM memory_relationships
+		WHERE workspace_id = $1 AND from_memory_id = ANY($2) AND to_memory_id = ANY($2)
+		ORDER BY created_at, id
+	`, WorkspaceFromContext(ctx), ids)
+	if err != nil {
+		return fmt.Errorf("export relationships: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rel Relationship
+		if err := rows.Scan(&rel.ID, &rel.FromMemoryID, &rel.ToMemoryID, &rel.Relationship, &rel.Strength, &rel.Context, &rel.CreatedAt); err != nil {
+			return fmt.Errorf("scan exported relationship: %w", err)
+		}
+		if err := fn(&rel); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ExportConsolidationLog calls fn for every consolidation log entry targeting
+// one of ids, oldest first so reverts follow the merges they undo.
+func (r *Repository) ExportConsolidationLog(ctx context.Context, ids []uuid.UUID, fn func(*ConsolidationLog) error) error {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, target_id, source_ids, merge_strategy, similarity_score, content_before, content_after, performed_by, created_at,
+		       reverted_at, reverts_id
+		FROM consolidation_log
+		WHERE workspace_id = $1 AND target_id = ANY($2)
+		ORDER BY created_at, id
+	`, WorkspaceFromContext(ctx), ids)
+	if err != nil {
+		return fmt.Errorf("export consolidation log: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l ConsolidationLog
+		err := rows.Scan(
+			&l.ID, &l.TargetID, &l.SourceIDs, &l.MergeStrategy,
+			&l.SimilarityScore, &l.ContentBefore, &l.ContentAfter, &l.PerformedBy, &l.CreatedAt,
+			&l.RevertedAt, &l.RevertsID,
+		)
+		if err != nil {
+			return fmt.Errorf("scan exported consolidation log: %w", err)
+		}
+		if err := fn(&l); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// MemoryWorkspace returns the workspace holding the memory with the given ID
+// in any workspace, or "" if the ID is unused. Import uses it to detect ID
+// conflicts, which span workspaces because IDs are globally unique.
+func (r *Repository) MemoryWorkspace(ctx context.Context, id uuid.UUID) (string, error) {
+	var workspace string
+	err := r.pool.QueryRow(ctx, "SELECT workspace_id FROM memories WHERE id = $1", id).Scan(&workspace)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get memory workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ImportMemory inserts an archived memory with its own ID, timestamps and
+// counters. The version trigger records it as an import.
+func (r *Repository) ImportMemory(ctx context.Context, mem *Memory) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin import memory: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT set_config('contextify.change_reason', 'import', true)"); err != nil {
+		return fmt.Errorf("set change_reason: %w", err)
+	}
+
+	mergedFrom := mem.MergedFrom
+	if mergedFrom == nil {
+		mergedFrom = []uuid.UUID{}
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO memories (
+			id, title, content, summary, embedding, embedding_model, type, scope, project_id, agent_source,
+			tags, importance, ttl_seconds, access_count, created_at, updated_at, expires_at, version, merged_from,
+			replaced_by, workspace_id, language
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22::regconfig)
+	`,
+		mem.ID, mem.Title, mem.Content, mem.Summary, mem.Embedding, mem.EmbeddingModel,
+		mem.Type, mem.Scope, mem.ProjectID, mem.AgentSource,
+		mem.Tags, mem.Importance, mem.TTLSeconds, mem.AccessCount,
+		mem.CreatedAt, mem.UpdatedAt, mem.ExpiresAt, max(mem.Version, 1), mergedFrom, mem.ReplacedBy,
+		WorkspaceFromContext(ctx), mem.Language,
+	)
+	if err != nil {
+		return fmt.Errorf("import memory: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// OverwriteMemory replaces a stored memory's fields with an archived copy's.
+// Access statistics and merge bookkeeping stay with the stored memory.
+func (r *Repository) OverwriteMemory(ctx context.Context, mem *Memory) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin overwrite memory: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT set_config('contextify.change_reason', 'import', true)"); err != nil {
+		return fmt.Errorf("set change_reason: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE memories
+		SET title = $2, content = $3, summary = $4, type = $5, scope = $6, project_id = $7, agent_source = $8,
+		    tags = $9, importance = $10, ttl_seconds = $11, expires_at = $12,
+		    embedding = $13, embedding_model = $14, embedding_next = NULL, embedding_next_model = NULL,
+		    language = $16::regconfig, deleted_at = NULL, deleted_reason = NULL
+		WHERE id = $1 AND workspace_id = $15
+	`,
+		mem.ID, mem.Title, mem.Content, mem.Summary, mem.Type, mem.Scope, mem.ProjectID, mem.AgentSource,
+		mem.Tags, mem.Importance, mem.TTLSeconds, mem.ExpiresAt,
+		mem.Embedding, mem.EmbeddingModel, WorkspaceFromContext(ctx), mem.Language,
+	)
+	if err != nil {
+		return fmt.Errorf("overwrite memory: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrMemoryNotFound, mem.ID)
+	}
+	return tx.Commit(ctx)
+}
+
+// ImportConsolidationLog inserts an archived log entry as-is. It reports
+// false when an entry with the same ID already exists.
+func (r *Repository) ImportConsolidationLog(ctx context.Context, log *ConsolidationLog) (bool, error) {
+	result, err := r.pool.Exec(ctx, `
+		INSERT INTO consolidation_log (
+			id, target_id, source_ids, merge_strategy, similarity_score, content_before, content_after,
+			performed_by, created_at, reverted_at, reverts_id, workspace_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO NOTHING
+	`,
+		log.ID, log.TargetID, log.SourceIDs, log.MergeStrategy, log.SimilarityScore,
+		log.ContentBefore, log.ContentAfter, log.PerformedBy, log.CreatedAt, log.RevertedAt, log.RevertsID,
+		WorkspaceFromContext(ctx),
+	)
+	if err != nil {
+		return false, fmt.Errorf("import consolidation log: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
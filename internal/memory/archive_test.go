@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/embedding"
+)
+
+func TestArchivedMemory_JSONRoundTrip(t *testing.T) {
+	project := "github.com/acme/api"
+	rec := ArchiveRecord{Kind: RecordMemory, Memory: &ArchivedMemory{
+		Memory: Memory{ID: uuid.New(), Title: "t", Content: "c", Type: TypeFix, ProjectID: &project, Tags: []string{"go"}},
+		Vector: []float32{0.5, -1},
+	}}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var flat map[string]any
+	json.Unmarshal(data, &flat)
+	mem := flat["memory"].(map[string]any)
+	if mem["title"] != "t" || mem["embedding"] == nil {
+		t.Fatalf("memory fields should be inlined next to the embedding, got %s", data)
+	}
+
+	var back ArchiveRecord
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if back.Memory.ID != rec.Memory.ID || *back.Memory.ProjectID != project || len(back.Memory.Vector) != 2 {
+		t.Fatalf("round trip lost fields: %+v", back.Memory)
+	}
+}
+
+func TestCheckArchiveHeader(t *testing.T) {
+	valid := &ArchiveHeader{Format: ArchiveFormat, Version: ArchiveVersion}
+	if err := checkArchiveHeader(&ArchiveRecord{Kind: RecordHeader, Header: valid}); err != nil {
+		t.Fatalf("valid header rejected: %v", err)
+	}
+
+	cases := map[string]*ArchiveRecord{
+		"not a header":   {Kind: RecordMemory, Memory: &ArchivedMemory{}},
+		"missing header": {Kind: RecordHeader},
+		"wrong format":   {Kind: RecordHeader, Header: &ArchiveHeader{Format: "other", Version: 1}},
+		"future version": {Kind: RecordHeader, Header: &ArchiveHeader{Format: ArchiveFormat, Version: ArchiveVersion + 1}},
+	}
+	for name, rec := range cases {
+		if err := checkArchiveHeader(rec); !errors.Is(err, ErrInvalidArchive) {
+			t.Errorf("%s: expected ErrInvalidArchive, got %v", name, err)
+		}
+	}
+}
+
+func TestImport_RejectsBadInputBeforeWriting(t *testing.T) {
+	s := &Service{}
+	if _, err := s.Import(context.Background(), strings.NewReader(""), ImportOptions{}); !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("empty input: expected ErrInvalidArchive, got %v", err)
+	}
+	if _, err := s.Import(context.Background(), strings.NewReader(`{"kind":"memory"}`), ImportOptions{}); !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("headerless input: expected ErrInvalidArchive, got %v", err)
+	}
+	if _, err := s.Import(context.Background(), strings.NewReader(""), ImportOptions{OnConflict: "merge"}); err == nil {
+		t.Error("unknown conflict mode: expected error")
+	}
+}
+
+func TestImportEmbedding_ReusesMatchingVector(t *testing.T) {
+	e := embedding.NewHashEmbedder(4)
+	s := &Service{embedder: e}
+	model := e.Model()
+	am := &ArchivedMemory{
+		Memory: Memory{Title: "a", Content: "b", EmbeddingModel: &model},
+		Vector: []float32{1, 2, 3, 4},
+	}
+
+	vec, _, err := s.importEmbedding(context.Background(), am, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vec.Slice()[3] != 4 {
+		t.Fatalf("expected archived vector to be reused, got %v", vec.Slice())
+	}
+
+	vec, _, err = s.importEmbedding(context.Background(), am, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, _ := e.Embed(context.Background(), "a b")
+	if vec.Slice()[0] != want[0] {
+		t.Fatalf("reembed should ignore the archived vector")
+	}
+
+	other := "other-model"
+	am.EmbeddingModel = &other
+	vec, _, _ = s.importEmbedding(context.Background(), am, false)
+	if vec.Slice()[0] != want[0] {
+		t.Fatalf("vector from another model should be recomputed")
+	}
+}
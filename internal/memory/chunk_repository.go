@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// ListUnchunked returns current memories longer than minChars characters
+// that have no chunks embedded with model.
+func (r *Repository) ListUnchunked(ctx context.Context, model string, minChars, limit int) ([]ReembedItem, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT m.id, m.title, m.content
+		FROM memories m
+		WHERE m.replaced_by IS NULL
+		  AND m.deleted_at IS NULL
+		  AND char_length(m.content) > $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM memory_chunks c
+		      WHERE c.memory_id = m.id AND c.embedding_model = $1
+		  )
+		ORDER BY m.id
+		LIMIT $3
+	`, model, minChars, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list unchunked memories: %w", err)
+	}
+	defer rows.Close()
+
+	var items []ReembedItem
+	for rows.Next() {
+		var it ReembedItem
+		if err := rows.Scan(&it.ID, &it.Title, &it.Content); err != nil {
+			return nil, fmt.Errorf("scan unchunked memory: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// ReplaceChunks swaps a memory's chunks for the given ones. It writes nothing
+// and returns false if the memory was deleted or its title or content no
+// longer match it.
+func (r *Repository) ReplaceChunks(ctx context.Context, it ReembedItem, model string, chunks []Chunk, vectors []pgvector.Vector) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin replace chunks: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var unchanged bool
+	err = tx.QueryRow(ctx, `
+		SELECT title = $2 AND content = $3 FROM memories WHERE id = $1 FOR UPDATE
+	`, it.ID, it.Title, it.Content).Scan(&unchanged)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("lock memory for chunks: %w", err)
+	}
+	if !unchanged {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM memory_chunks WHERE memory_id = $1`, it.ID); err != nil {
+		return false, fmt.Errorf("delete chunks: %w", err)
+	}
+	for i, c := range chunks {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO memory_chunks (memory_id, chunk_index, start_offset, end_offset, embedding, embedding_model)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, it.ID, c.Index, c.Start, c.End, vectors[i], model)
+		if err != nil {
+			return false, fmt.Errorf("insert chunk: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit replace chunks: %w", err)
+	}
+	return true, nil
+}
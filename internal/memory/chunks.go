@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+// Long memories get one embedding per overlapping chunk of content in
+// addition to the whole-memory embedding. Search scores a memory by the
+// better of the two, so a passage deep inside a pasted log can still match.
+// Chunks are built in the background by ChunkStep; any change to a memory's
+// title or content drops its chunks (trigger in 012_memory_chunks.sql).
+
+// maxChunksPerMemory bounds the embedding work for one memory; text past the
+// last chunk is only covered by the whole-memory embedding.
+const maxChunksPerMemory = 100
+
+// chunkSweepBackoff is how long ChunkStep waits after finding nothing to do.
+const chunkSweepBackoff = 30 * time.Second
+
+// Chunk is a span of a memory's content in characters (runes), end exclusive.
+type Chunk struct {
+	Index int `json:"index"`
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ChunkMatch is a memory's best-matching chunk for a search.
+type ChunkMatch struct {
+	Chunk
+	Score float64 `json:"score"`
+}
+
+// splitChunks splits content into chunks of at most size characters, each
+// starting overlap characters before the previous one ended. A chunk ends at
+// the last paragraph break, line break, sentence end or space in its final
+// fifth when there is one. Content of size characters or less is not split.
+func splitChunks(content string, size, overlap int) []Chunk {
+	runes := []rune(content)
+	if size <= 0 || len(runes) <= size {
+		return nil
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []Chunk
+	start := 0
+	for start < len(runes) && len(chunks) < maxChunksPerMemory {
+		end := start + size
+		if end >= len(runes) {
+			end = len(runes)
+		} else {
+			end = chunkBreak(runes, start+size*4/5, end)
+		}
+		chunks = append(chunks, Chunk{Index: len(chunks), Start: start, End: end})
+		if end == len(runes) {
+			break
+		}
+		next := end - overlap
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+	return chunks
+}
+
+// chunkBreak returns the best place to end a chunk in runes[from:to]: just
+// after a paragraph break, then a line break, then a sentence end, then a
+// space, else to.
+func chunkBreak(runes []rune, from, to int) int {
+	window := string(runes[from:to])
+	for _, sep := range []string{"\n\n", "\n", ". ", " "} {
+		if i := strings.LastIndex(window, sep); i >= 0 {
+			return from + len([]rune(window[:i+len(sep)]))
+		}
+	}
+	return to
+}
+
+// chunkText returns the text of c within content.
+func chunkText(content string, c Chunk) string {
+	runes := []rune(content)
+	return string(runes[c.Start:c.End])
+}
+
+// SetChunking configures chunked embeddings.
+func (s *Service) SetChunking(cfg config.ChunkingConfig) {
+	s.chunking = cfg
+}
+
+// kickChunker makes the next ChunkStep run immediately, so a freshly written
+// long memory gets its chunks within one worker tick.
+func (s *Service) kickChunker() {
+	s.embMu.Lock()
+	s.nextChunkSweep = time.Time{}
+	s.embMu.Unlock()
+}
+
+// ChunkStep builds chunk embeddings with the active model for a batch of long
+// memories that lack them. It backs off once a sweep finds nothing. Returns
+// the number of memories chunked.
+func (s *Service) ChunkStep(ctx context.Context) (int, error) {
+	if !s.chunking.Enabled {
+		return 0, nil
+	}
+	s.embMu.Lock()
+	if time.Now().Before(s.nextChunkSweep) {
+		s.embMu.Unlock()
+		return 0, nil
+	}
+	s.embMu.Unlock()
+
+	batchSize := s.reembedCfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 32
+	}
+	active := s.activeEmbedder()
+	items, err := s.repo.ListUnchunked(ctx, active.Model(), s.chunking.Size, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		s.embMu.Lock()
+		s.nextChunkSweep = time.Now().Add(chunkSweepBackoff)
+		s.embMu.Unlock()
+		return 0, nil
+	}
+
+	n := 0
+	for _, it := range items {
+		written, err := s.indexChunks(ctx, it, batchSize)
+		if err != nil {
+			return n, err
+		}
+		if written {
+			n++
+		}
+	}
+	if n > 0 {
+		s.invalidateAllSearchCaches(ctx)
+	}
+	return n, nil
+}
+
+// indexChunks embeds the chunks of one memory and replaces its stored ones.
+// It reports false if the memory changed while it was being embedded.
+func (s *Service) indexChunks(ctx context.Context, it ReembedItem, batchSize int) (bool, error) {
+	chunks := splitChunks(it.Content, s.chunking.Size, s.chunking.Overlap)
+	if len(chunks) == 0 {
+		return false, nil
+	}
+	active := s.activeEmbedder()
+	vectors := make([]pgvector.Vector, 0, len(chunks))
+	for i := 0; i < len(chunks); i += batchSize {
+		batch := chunks[i:min(i+batchSize, len(chunks))]
+		texts := make([]string, len(batch))
+		for j, c := range batch {
+			texts[j] = it.Title + " " + chunkText(it.Content, c)
+		}
+		raw, err := active.EmbedBatch(ctx, texts)
+		if err != nil {
+			return false, fmt.Errorf("embed chunks of %s: %w", it.ID, err)
+		}
+		if len(raw) != len(batch) {
+			return false, fmt.Errorf("embed batch returned %d vectors for %d chunks", len(raw), len(batch))
+		}
+		for _, v := range raw {
+			vectors = append(vectors, pgvector.NewVector(v))
+		}
+	}
+	return s.repo.ReplaceChunks(ctx, it, active.Model(), chunks, vectors)
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks_ShortContentIsNotSplit(t *testing.T) {
+	if got := splitChunks("short note", 100, 10); got != nil {
+		t.Fatalf("expected no chunks, got %+v", got)
+	}
+}
+
+func TestSplitChunks_CoversContentWithOverlap(t *testing.T) {
+	content := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 60)
+	runes := []rune(content)
+	chunks := splitChunks(content, 300, 50)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	if chunks[0].Start != 0 || chunks[len(chunks)-1].End != len(runes) {
+		t.Fatalf("chunks do not cover the content: first %+v, last %+v", chunks[0], chunks[len(chunks)-1])
+	}
+	for i, c := range chunks {
+		if c.Index != i {
+			t.Errorf("chunk %d has index %d", i, c.Index)
+		}
+		if c.End-c.Start > 300 {
+			t.Errorf("chunk %d is %d characters, want <= 300", i, c.End-c.Start)
+		}
+		if i > 0 {
+			prev := chunks[i-1]
+			if c.Start != prev.End-50 {
+				t.Errorf("chunk %d starts at %d, want %d (50 before the previous end)", i, c.Start, prev.End-50)
+			}
+		}
+		if i < len(chunks)-1 && !strings.HasSuffix(chunkText(content, c), ". ") {
+			t.Errorf("chunk %d does not end at a sentence: %q", i, chunkText(content, c))
+		}
+	}
+}
+
+func TestSplitChunks_MultibyteOffsets(t *testing.T) {
+	content := strings.Repeat("ğüşiöç", 50)
+	chunks := splitChunks(content, 100, 0)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	var rebuilt strings.Builder
+	for _, c := range chunks {
+		rebuilt.WriteString(chunkText(content, c))
+	}
+	if rebuilt.String() != content {
+		t.Fatalf("chunks without overlap should rebuild the content")
+	}
+}
+
+func TestSplitChunks_CapsChunkCount(t *testing.T) {
+	content := strings.Repeat("x", 10*maxChunksPerMemory+10)
+	if got := len(splitChunks(content, 10, 0)); got != maxChunksPerMemory {
+		t.Fatalf("expected %d chunks, got %d", maxChunksPerMemory, got)
+	}
+}
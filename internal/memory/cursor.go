@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursors are opaque to clients: base64url-encoded JSON holding the sort key
+// and id of the last item on a page. The next page starts strictly after that
+// key, so items written or removed meanwhile do not shift it.
+
+// EncodeCursor returns the cursor token for v.
+func EncodeCursor(v any) string {
+	b, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes a token made by EncodeCursor into v.
+func DecodeCursor(token string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("%w: not a cursor", ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: not a cursor", ErrInvalidCursor)
+	}
+	return nil
+}
+
+// maxSearchDepth caps how many results a paginated search serves in total.
+// Search scores in Go, so every page fetches and ranks again all candidates
+// up to its position: a page costs as much as an offset to it would. The
+// cap bounds that cost.
+const maxSearchDepth = 1000
+
+// SearchPage is one page of a cursor-paginated search.
+type SearchPage struct {
+	Results []SearchResult `json:"results"`
+	// NextCursor continues the search; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// TotalEstimate is a lower bound on the number of matches, when the
+	// request asked for it: the candidates fetched for this page, which
+	// grows as a broad search is paged.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+	// Truncated reports that matches remain past maxSearchDepth, which no
+	// page reaches; a narrower search finds them.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// searchCursor is the position after a search result. Results are ordered by
+// score, then updated_at descending, then id. The next page is the results
+// after that key, but it is cut from a candidate set re-ranked from the top,
+// which Seen sizes. Now pins the time used for score modifiers and relative
+// filter dates, so scores stay comparable across pages.
+type searchCursor struct {
+	Score     float64   `json:"s"`
+	UpdatedAt time.Time `json:"u"`
+	ID        uuid.UUID `json:"id"`
+	Seen      int       `json:"n"`
+	Now       time.Time `json:"t"`
+	Query     string    `json:"q"`
+}
+
+// after reports whether r sorts after the cursor position.
+func (c *searchCursor) after(r SearchResult) bool {
+	if r.Score != c.Score {
+		return r.Score < c.Score
+	}
+	if !r.Memory.UpdatedAt.Equal(c.UpdatedAt) {
+		return r.Memory.UpdatedAt.Before(c.UpdatedAt)
+	}
+	return bytes.Compare(r.Memory.ID[:], c.ID[:]) > 0
+}
+
+// cursorPage returns the limit results after cursor (all results when it is
+// nil) with the cursor for the next page. A page ending at maxSearchDepth
+// has no cursor and is marked truncated when results remain.
+func cursorPage(results []SearchResult, cursor *searchCursor, limit int, fingerprint string, now time.Time, includeTotal bool) *SearchPage {
+	page := &SearchPage{}
+	if includeTotal {
+		total := len(results)
+		page.TotalEstimate = &total
+	}
+	seen := 0
+	if cursor != nil {
+		seen = cursor.Seen
+		i := 0
+		for i < len(results) && !cursor.after(results[i]) {
+			i++
+		}
+		results = results[i:]
+	}
+	if left := max(maxSearchDepth-seen, 0); limit >= left {
+		if len(results) > left {
+			page.Truncated = true
+			results = results[:left]
+		}
+		page.Results = results
+		return page
+	}
+	if len(results) > limit {
+		last := results[limit-1]
+		page.NextCursor = EncodeCursor(searchCursor{
+			Score:     last.Score,
+			UpdatedAt: last.Memory.UpdatedAt,
+			ID:        last.Memory.ID,
+			Seen:      seen + limit,
+			Now:       now,
+			Query:     fingerprint,
+		})
+		results = results[:limit]
+	}
+	page.Results = results
+	return page
+}
+
+// searchFingerprint identifies the search a cursor belongs to, ignoring the
+// page size and position.
+func searchFingerprint(workspace string, req SearchRequest) string {
+	req.Limit, req.Offset, req.Cursor, req.Explain = 0, 0, "", false
+	key, _ := cacheKey(workspace, req)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SuggestionCursor is the position after a consolidation suggestion, ordered
+// by similarity descending, then id.
+type SuggestionCursor struct {
+	Similarity float64   `json:"s"`
+	ID         uuid.UUID `json:"id"`
+}
+
+// SuggestionPage is one page of consolidation suggestions.
+type SuggestionPage struct {
+	Suggestions []ConsolidationSuggestion `json:"suggestions"`
+	Total       int                       `json:"total"`
+	NextCursor  string                    `json:"next_cursor,omitempty"`
+}
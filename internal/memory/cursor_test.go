@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	in := searchCursor{Score: 0.1 + 0.2, UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC), ID: uuid.New(), Seen: 20, Query: "abc"}
+	var out searchCursor
+	if err := DecodeCursor(EncodeCursor(in), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Score != in.Score || !out.UpdatedAt.Equal(in.UpdatedAt) || out.ID != in.ID || out.Seen != 20 || out.Query != "abc" {
+		t.Fatalf("round trip changed the cursor: %+v", out)
+	}
+
+	for _, bad := range []string{"not base64!", EncodeCursor("a string")} {
+		if err := DecodeCursor(bad, &out); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%q: expected ErrInvalidCursor, got %v", bad, err)
+		}
+	}
+}
+
+func TestCursorPage_KeysetSurvivesInserts(t *testing.T) {
+	now := time.Now()
+	var results []SearchResult
+	for i := 0; i < 5; i++ {
+		results = append(results, SearchResult{Memory: Memory{ID: uuid.New(), UpdatedAt: now}, Score: 1 - float64(i)/10})
+	}
+
+	first := cursorPage(results, nil, 2, "q", now, true)
+	if len(first.Results) != 2 || first.NextCursor == "" || first.TotalEstimate == nil || *first.TotalEstimate != 5 {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+
+	// A better match written between pages must not shift the second page.
+	inserted := SearchResult{Memory: Memory{ID: uuid.New(), UpdatedAt: now}, Score: 2}
+	withInsert := append([]SearchResult{inserted}, results...)
+
+	var cur searchCursor
+	if err := DecodeCursor(first.NextCursor, &cur); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	second := cursorPage(withInsert, &cur, 2, "q", now, false)
+	if len(second.Results) != 2 || second.Results[0].Memory.ID != results[2].Memory.ID || second.Results[1].Memory.ID != results[3].Memory.ID {
+		t.Fatalf("second page should continue after the first: %+v", second.Results)
+	}
+	if second.TotalEstimate != nil {
+		t.Fatalf("total was not requested")
+	}
+
+	if err := DecodeCursor(second.NextCursor, &cur); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if cur.Seen != 4 {
+		t.Fatalf("cursor should count the results served, got %d", cur.Seen)
+	}
+	last := cursorPage(withInsert, &cur, 2, "q", now, false)
+	if len(last.Results) != 1 || last.NextCursor != "" {
+		t.Fatalf("expected a final page of one without a cursor: %+v", last)
+	}
+}
+
+func TestCursorAfter_TieBreaks(t *testing.T) {
+	now := time.Now()
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000005")
+	c := &searchCursor{Score: 0.5, UpdatedAt: now, ID: id}
+
+	cases := []struct {
+		name string
+		r    SearchResult
+		want bool
+	}{
+		{"lower score", SearchResult{Score: 0.4, Memory: Memory{UpdatedAt: now.Add(time.Hour)}}, true},
+		{"higher score", SearchResult{Score: 0.6, Memory: Memory{UpdatedAt: now}}, false},
+		{"older", SearchResult{Score: 0.5, Memory: Memory{UpdatedAt: now.Add(-time.Second)}}, true},
+		{"newer", SearchResult{Score: 0.5, Memory: Memory{UpdatedAt: now.Add(time.Second)}}, false},
+		{"greater id", SearchResult{Score: 0.5, Memory: Memory{UpdatedAt: now, ID: uuid.MustParse("00000000-0000-0000-0000-000000000006")}}, true},
+		{"same id", SearchResult{Score: 0.5, Memory: Memory{UpdatedAt: now, ID: id}}, false},
+	}
+	for _, tc := range cases {
+		if got := c.after(tc.r); got != tc.want {
+			t.Errorf("%s: after = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCursorPage_StopsAtMaxDepth(t *testing.T) {
+	now := time.Now()
+	results := make([]SearchResult, maxSearchDepth+5)
+	for i := range results {
+		results[i] = SearchResult{Memory: Memory{ID: uuid.New(), UpdatedAt: now}, Score: float64(len(results) - i)}
+	}
+
+	last := results[maxSearchDepth-11]
+	cur := &searchCursor{Score: last.Score, UpdatedAt: now, ID: last.Memory.ID, Seen: maxSearchDepth - 10}
+	page := cursorPage(results, cur, 20, "q", now, false)
+	if len(page.Results) != 10 || page.NextCursor != "" || !page.Truncated {
+		t.Fatalf("expected the last 10 results before the cap, truncated: got %d, cursor %q, truncated %v", len(page.Results), page.NextCursor, page.Truncated)
+	}
+	if page.Results[9].Memory.ID != results[maxSearchDepth-1].Memory.ID {
+		t.Fatalf("page should end at the cap")
+	}
+
+	short := cursorPage(results[:maxSearchDepth-5], cur, 20, "q", now, false)
+	if len(short.Results) != 5 || short.Truncated {
+		t.Fatalf("a search ending before the cap is not truncated: %+v", short)
+	}
+}
@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+// Built-in decay policies.
+const (
+	// DecayFixedTTL extends a memory's TTL by memory.ttl_extend_factor on
+	// each access and promotes it at its promote_access_count accesses.
+	DecayFixedTTL = "fixed_ttl"
+	// DecayExponential forgets memories along an exponential curve whose
+	// stability grows with use and importance.
+	DecayExponential = "exponential"
+	// DecaySpacedRepetition multiplies the interval to the next expiry on
+	// each access and promotes memories whose interval grows long enough.
+	DecaySpacedRepetition = "spaced_repetition"
+	// DecayNever keeps memories for good.
+	DecayNever = "never"
+)
+
+// DecayState is what a DecayPolicy decides a short-term memory's fate from.
+type DecayState struct {
+	Importance  float64
+	AccessCount int
+	// TTL is the memory's own TTL, the base every policy scales.
+	TTL       time.Duration
+	CreatedAt time.Time
+	// LastAccessedAt is CreatedAt for a memory that was never read.
+	LastAccessedAt time.Time
+	// PromoteAccessCount and PromoteImportance are the memory's promotion
+	// thresholds, from memory config or a retention rule.
+	PromoteAccessCount int
+	PromoteImportance  float64
+}
+
+// DecayPolicy decides when a short-term memory expires. It is evaluated when
+// a memory is stored, on every access and by the cleanup scheduler, so a
+// policy must be a pure function of the state.
+type DecayPolicy interface {
+	Name() string
+	// Permanent reports whether the memory should be promoted to long-term.
+	Permanent(s DecayState) bool
+	// ExpiresAt is when a memory that is not permanent expires.
+	ExpiresAt(s DecayState) time.Time
+}
+
+// NewDecayPolicy returns the built-in policy called name, configured from cfg.
+func NewDecayPolicy(name string, cfg config.MemoryConfig) (DecayPolicy, error) {
+	switch name {
+	case DecayFixedTTL, "":
+		return fixedTTLDecay{extendFactor: cfg.TTLExtendFactor}, nil
+	case DecayExponential:
+		return exponentialDecay{
+			minRetention: cfg.Decay.Exponential.MinRetention,
+			strengthGain: cfg.Decay.Exponential.StrengthGain,
+		}, nil
+	case DecaySpacedRepetition:
+		return spacedRepetitionDecay{
+			ease:             cfg.Decay.SpacedRepetition.Ease,
+			graduateInterval: cfg.Decay.SpacedRepetition.GraduateInterval,
+		}, nil
+	case DecayNever:
+		return neverDecay{}, nil
+	}
+	return nil, fmt.Errorf("unknown decay policy %q (want %s, %s, %s or %s)", name, DecayFixedTTL, DecayExponential, DecaySpacedRepetition, DecayNever)
+}
+
+// fixedTTLDecay is the original TTL behaviour: every access adds
+// extendFactor × TTL to the expiry. A memory also never expires sooner than
+// one TTL after its last access, so a restored memory is not trashed again
+// at once.
+type fixedTTLDecay struct {
+	extendFactor float64
+}
+
+func (fixedTTLDecay) Name() string { return DecayFixedTTL }
+
+func (d fixedTTLDecay) Permanent(s DecayState) bool {
+	return s.Importance >= s.PromoteImportance || s.AccessCount >= s.PromoteAccessCount
+}
+
+func (d fixedTTLDecay) ExpiresAt(s DecayState) time.Time {
+	extended := s.CreatedAt.Add(scaleDuration(s.TTL, 1+d.extendFactor*float64(s.AccessCount)))
+	if floor := s.LastAccessedAt.Add(s.TTL); floor.After(extended) {
+		return floor
+	}
+	return extended
+}
+
+// exponentialDecay models retention as exp(-t/S) since the last access, with
+// stability S = TTL × (1 + strengthGain × accesses) × (1 + importance). The
+// memory expires when retention falls below minRetention, i.e. S × ln(1/minRetention)
+// after its last access.
+type exponentialDecay struct {
+	minRetention float64
+	strengthGain float64
+}
+
+func (exponentialDecay) Name() string { return DecayExponential }
+
+func (exponentialDecay) Permanent(s DecayState) bool {
+	return s.Importance >= s.PromoteImportance
+}
+
+func (d exponentialDecay) ExpiresAt(s DecayState) time.Time {
+	stability := (1 + d.strengthGain*float64(s.AccessCount)) * (1 + s.Importance)
+	return s.LastAccessedAt.Add(scaleDuration(s.TTL, stability*math.Log(1/d.minRetention)))
+}
+
+// spacedRepetitionDecay gives a memory TTL × ease^accesses from its last
+// access, so each review pushes the next one further out, and graduates it
+// once that interval reaches graduateInterval.
+type spacedRepetitionDecay struct {
+	ease             float64
+	graduateInterval time.Duration
+}
+
+func (spacedRepetitionDecay) Name() string { return DecaySpacedRepetition }
+
+func (d spacedRepetitionDecay) interval(s DecayState) time.Duration {
+	return scaleDuration(s.TTL, math.Pow(d.ease, float64(s.AccessCount)))
+}
+
+func (d spacedRepetitionDecay) Permanent(s DecayState) bool {
+	return s.Importance >= s.PromoteImportance || d.interval(s) >= d.graduateInterval
+}
+
+func (d spacedRepetitionDecay) ExpiresAt(s DecayState) time.Time {
+	return s.LastAccessedAt.Add(d.interval(s))
+}
+
+type neverDecay struct{}
+
+func (neverDecay) Name() string                   { return DecayNever }
+func (neverDecay) Permanent(DecayState) bool      { return true }
+func (neverDecay) ExpiresAt(DecayState) time.Time { return time.Time{} } // never asked: always permanent
+
+// scaleDuration multiplies d by f, saturating instead of overflowing.
+func scaleDuration(d time.Duration, f float64) time.Duration {
+	scaled := float64(d) * f
+	if scaled >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(scaled)
+}
+
+// decayPolicies picks the policy of each memory from memory.decay: the first
+// rule matching its type and project, or the default policy.
+type decayPolicies struct {
+	fallback DecayPolicy
+	rules    []decayRule
+}
+
+type decayRule struct {
+	typ     MemoryType // empty matches any type
+	project string     // normalized; empty matches any project
+	policy  DecayPolicy
+}
+
+// newDecayPolicies builds the policies in cfg. Rule projects are normalized
+// with normalize so they compare equal to stored project IDs. Invalid names
+// are rejected by config validation; should one get here it falls back to
+// fixed_ttl.
+func newDecayPolicies(cfg config.MemoryConfig, normalize func(string) string) *decayPolicies {
+	policy := func(name string) DecayPolicy {
+		p, err := NewDecayPolicy(name, cfg)
+		if err != nil {
+			slog.Warn("falling back to fixed_ttl decay", "error", err)
+			p, _ = NewDecayPolicy(DecayFixedTTL, cfg)
+		}
+		return p
+	}
+
+	p := &decayPolicies{fallback: policy(cfg.Decay.Policy)}
+	for _, r := range cfg.Decay.Rules {
+		rule := decayRule{typ: MemoryType(strings.ToLower(strings.TrimSpace(r.Type))), policy: policy(r.Policy)}
+		if rule.typ != "" && !ValidTypes[rule.typ] {
+			slog.Warn("decay rule names an unknown memory type", "type", r.Type)
+		}
+		if r.Project != "" {
+			rule.project = normalize(r.Project)
+		}
+		p.rules = append(p.rules, rule)
+	}
+	return p
+}
+
+// For returns the policy of memories of typ in projectID.
+func (p *decayPolicies) For(typ MemoryType, projectID *string) DecayPolicy {
+	for _, r := range p.rules {
+		if r.typ != "" && r.typ != typ {
+			continue
+		}
+		if r.project != "" && (projectID == nil || *projectID != r.project) {
+			continue
+		}
+		return r.policy
+	}
+	return p.fallback
+}
+
+// decayState is the state of mem in workspace as of its last access,
+// lastAccess. A memory without a TTL of its own gets the one its retention
+// rules set.
+func (s *Service) decayState(workspace string, mem *Memory, lastAccess time.Time) DecayState {
+	set := s.retentionFor(workspace, mem)
+	ttl := set.TTL
+	if mem.TTLSeconds != nil {
+		ttl = *mem.TTLSeconds
+	}
+	return DecayState{
+		Importance:         float64(mem.Importance),
+		AccessCount:        mem.AccessCount,
+		TTL:                time.Duration(ttl) * time.Second,
+		CreatedAt:          mem.CreatedAt,
+		LastAccessedAt:     lastAccess,
+		PromoteAccessCount: set.PromoteAccessCount,
+		PromoteImportance:  set.PromoteImportance,
+	}
+}
+
+// keptForever reports whether mem's decay policy makes it permanent.
+func (s *Service) keptForever(ctx context.Context, mem *Memory) bool {
+	s.refreshRetention(ctx, false)
+	state := s.decayState(WorkspaceFromContext(ctx), mem, mem.CreatedAt)
+	return s.decay.For(mem.Type, mem.ProjectID).Permanent(state)
+}
+
+// recordAccess counts a read of mem and moves its expiry as its decay
+// policy says, promoting it once the policy makes it permanent.
+func (s *Service) recordAccess(ctx context.Context, mem *Memory) {
+	var expiresAt *time.Time
+	promote := false
+	if mem.TTLSeconds != nil {
+		s.refreshRetention(ctx, false)
+		state := s.decayState(WorkspaceFromContext(ctx), mem, time.Now())
+		state.AccessCount++
+		policy := s.decay.For(mem.Type, mem.ProjectID)
+		if policy.Permanent(state) {
+			promote = true
+		} else {
+			at := policy.ExpiresAt(state)
+			expiresAt = &at
+		}
+	}
+
+	if err := s.repo.IncrementAccess(ctx, mem.ID, expiresAt); err != nil {
+		slog.Warn("failed to increment access", "id", mem.ID, "error", err)
+		return
+	}
+	if !promote {
+		return
+	}
+	if err := s.repo.PromoteToLongTerm(ctx, mem.ID); err != nil {
+		slog.Warn("failed to auto-promote", "id", mem.ID, "error", err)
+		return
+	}
+	slog.Info("auto-promoted memory to long-term", "id", mem.ID, "access_count", mem.AccessCount+1)
+	mem.TTLSeconds = nil
+	mem.ExpiresAt = nil
+}
+
+// decayBatchSize is how many short-term memories CleanupExpired evaluates
+// per query.
+const decayBatchSize = 500
+
+// decayingMemory is what the cleanup scheduler reads of a short-term memory
+// to evaluate its decay policy.
+type decayingMemory struct {
+	ID             uuid.UUID
+	Workspace      string
+	Type           MemoryType
+	ProjectID      *string
+	AgentSource    *string
+	Importance     float32
+	TTLSeconds     int
+	AccessCount    int
+	CreatedAt      time.Time
+	LastAccessedAt *time.Time
+	ExpiresAt      *time.Time
+}
+
+// decayDecisions is what one cleanup batch does to the memories it read.
+type decayDecisions struct {
+	promote       []uuid.UUID
+	expire        []uuid.UUID
+	rescheduleIDs []uuid.UUID
+	rescheduleAt  []time.Time
+}
+
+// evaluateDecay decides what happens to m as of now. Expiry times are
+// rewritten only when they moved by more than a second, since the stored
+// ones have microsecond precision.
+func (s *Service) evaluateDecay(m decayingMemory, now time.Time, d *decayDecisions) {
+	mem := &Memory{Type: m.Type, ProjectID: m.ProjectID, AgentSource: m.AgentSource, Importance: m.Importance, TTLSeconds: &m.TTLSeconds, AccessCount: m.AccessCount, CreatedAt: m.CreatedAt}
+	lastAccess := m.CreatedAt
+	if m.LastAccessedAt != nil {
+		lastAccess = *m.LastAccessedAt
+	}
+	state := s.decayState(m.Workspace, mem, lastAccess)
+	policy := s.decay.For(m.Type, m.ProjectID)
+
+	if policy.Permanent(state) {
+		d.promote = append(d.promote, m.ID)
+		return
+	}
+	at := policy.ExpiresAt(state)
+	switch {
+	case !at.After(now):
+		d.expire = append(d.expire, m.ID)
+	case m.ExpiresAt == nil || at.Sub(*m.ExpiresAt).Abs() > time.Second:
+		d.rescheduleIDs = append(d.rescheduleIDs, m.ID)
+		d.rescheduleAt = append(d.rescheduleAt, at)
+	}
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListDecaying returns up to limit live short-term memories with IDs after
+// after, in every workspace, ordered by ID. Replaced memories are left to
+// CleanupReplaced.
+func (r *Repository) ListDecaying(ctx context.Context, after uuid.UUID, limit int) ([]decayingMemory, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, workspace_id, type, project_id, agent_source, importance, ttl_seconds, access_count,
+		       created_at, last_accessed_at, expires_at
+		FROM memories
+		WHERE ttl_seconds IS NOT NULL AND deleted_at IS NULL AND replaced_by IS NULL AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list decaying memories: %w", err)
+	}
+	defer rows.Close()
+
+	var out []decayingMemory
+	for rows.Next() {
+		var m decayingMemory
+		if err := rows.Scan(&m.ID, &m.Workspace, &m.Type, &m.ProjectID, &m.AgentSource, &m.Importance, &m.TTLSeconds, &m.AccessCount,
+			&m.CreatedAt, &m.LastAccessedAt, &m.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("scan decaying memory: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// ApplyDecay promotes, trashes and reschedules memories as decided by a
+// cleanup batch read at asOf. A memory read since asOf is not trashed, since
+// the access may have pushed its expiry out. It returns how many memories
+// were trashed and promoted.
+func (r *Repository) ApplyDecay(ctx context.Context, d *decayDecisions, asOf time.Time) (trashed, promoted int64, err error) {
+	if len(d.promote) > 0 {
+		result, err := r.pool.Exec(ctx, `
+			UPDATE memories SET ttl_seconds = NULL, expires_at = NULL
+			WHERE id = ANY($1) AND deleted_at IS NULL AND replaced_by IS NULL
+		`, d.promote)
+		if err != nil {
+			return 0, 0, fmt.Errorf("promote decayed memories: %w", err)
+		}
+		promoted = result.RowsAffected()
+	}
+	if len(d.expire) > 0 {
+		result, err := r.pool.Exec(ctx, `
+			UPDATE memories SET deleted_at = NOW(), deleted_reason = 'expired'
+			WHERE id = ANY($1) AND deleted_at IS NULL AND replaced_by IS NULL AND ttl_seconds IS NOT NULL
+			  AND (last_accessed_at IS NULL OR last_accessed_at < $2)
+		`, d.expire, asOf)
+		if err != nil {
+			return 0, promoted, fmt.Errorf("trash expired memories: %w", err)
+		}
+		trashed = result.RowsAffected()
+	}
+	if len(d.rescheduleIDs) > 0 {
+		_, err := r.pool.Exec(ctx, `
+			UPDATE memories m SET expires_at = v.expires_at
+			FROM unnest($1::uuid[], $2::timestamptz[]) AS v(id, expires_at)
+			WHERE m.id = v.id AND m.ttl_seconds IS NOT NULL AND m.deleted_at IS NULL
+			  AND (m.last_accessed_at IS NULL OR m.last_accessed_at < $3)
+		`, d.rescheduleIDs, d.rescheduleAt, asOf)
+		if err != nil {
+			return trashed, promoted, fmt.Errorf("reschedule decaying memories: %w", err)
+		}
+	}
+	return trashed, promoted, nil
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+func testDecayConfig() config.MemoryConfig {
+	return config.MemoryConfig{
+		DefaultTTL: 86400, PromoteAccessCount: 5, PromoteImportance: 0.8, TTLExtendFactor: 0.5,
+		Decay: config.DecayConfig{
+			Policy:           DecayFixedTTL,
+			Exponential:      config.ExponentialDecay{MinRetention: 0.5, StrengthGain: 1},
+			SpacedRepetition: config.SpacedRepetitionDecay{Ease: 2, GraduateInterval: 30 * 24 * time.Hour},
+		},
+	}
+}
+
+func TestDecayPolicies(t *testing.T) {
+	cfg := testDecayConfig()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	state := func(accesses int, lastAccess time.Duration) DecayState {
+		return DecayState{
+			Importance: 0.5, AccessCount: accesses, TTL: day, CreatedAt: created, LastAccessedAt: created.Add(lastAccess),
+			PromoteAccessCount: 5, PromoteImportance: 0.8,
+		}
+	}
+	policy := func(name string) DecayPolicy {
+		p, err := NewDecayPolicy(name, cfg)
+		if err != nil {
+			t.Fatalf("NewDecayPolicy(%q) error = %v", name, err)
+		}
+		return p
+	}
+
+	fixed := policy(DecayFixedTTL)
+	if got := fixed.ExpiresAt(state(2, time.Hour)); !got.Equal(created.Add(2 * day)) {
+		t.Fatalf("fixed_ttl: two accesses should extend the TTL by 2 × 50%%, got %v", got)
+	}
+	if got := fixed.ExpiresAt(state(1, 3*day)); !got.Equal(created.Add(4 * day)) {
+		t.Fatalf("fixed_ttl: expiry should be at least one TTL after the last access, got %v", got)
+	}
+	if fixed.Permanent(state(4, 0)) || !fixed.Permanent(state(5, 0)) {
+		t.Fatalf("fixed_ttl: expected promotion at 5 accesses")
+	}
+
+	exp := policy(DecayExponential)
+	// S = 1d × (1 + 1×1) × 1.5 = 3d; expires S × ln 2 after the last access.
+	want := created.Add(day).Add(time.Duration(3 * float64(day) * math.Ln2))
+	if got := exp.ExpiresAt(state(1, day)); got.Sub(want).Abs() > time.Millisecond {
+		t.Fatalf("exponential: expires at %v, want %v", got, want)
+	}
+	if exp.Permanent(state(100, 0)) {
+		t.Fatalf("exponential: access alone should not promote")
+	}
+
+	spaced := policy(DecaySpacedRepetition)
+	if got := spaced.ExpiresAt(state(3, day)); !got.Equal(created.Add(9 * day)) {
+		t.Fatalf("spaced_repetition: expected 8 day interval after the last access, got %v", got)
+	}
+	if spaced.Permanent(state(4, 0)) || !spaced.Permanent(state(5, 0)) {
+		t.Fatalf("spaced_repetition: expected graduation once the interval reaches 30 days")
+	}
+
+	if !policy(DecayNever).Permanent(state(0, 0)) {
+		t.Fatalf("never: expected every memory to be permanent")
+	}
+	if _, err := NewDecayPolicy("forever", cfg); err == nil {
+		t.Fatalf("expected an error for an unknown policy")
+	}
+}
+
+func TestDecayPolicies_Rules(t *testing.T) {
+	cfg := testDecayConfig()
+	cfg.Decay.Rules = []config.DecayRule{
+		{Type: "Decision", Policy: DecayNever},
+		{Project: "Acme/API", Policy: DecayExponential},
+		{Type: "task", Project: "acme/api", Policy: DecaySpacedRepetition},
+	}
+	p := newDecayPolicies(cfg, func(id string) string { return "norm:" + id })
+
+	acme, other := "norm:Acme/API", "norm:other"
+	cases := []struct {
+		typ     MemoryType
+		project *string
+		want    string
+	}{
+		{TypeDecision, &acme, DecayNever},
+		{TypeTask, &acme, DecayExponential}, // the project rule comes first
+		{TypeFix, &acme, DecayExponential},
+		{TypeFix, &other, DecayFixedTTL},
+		{TypeFix, nil, DecayFixedTTL},
+	}
+	for _, c := range cases {
+		if got := p.For(c.typ, c.project).Name(); got != c.want {
+			t.Errorf("For(%s, %v) = %s, want %s", c.typ, c.project, got, c.want)
+		}
+	}
+}
+
+func TestEvaluateDecay(t *testing.T) {
+	cfg := testDecayConfig()
+	cfg.Decay.Rules = []config.DecayRule{{Type: "decision", Policy: DecayNever}}
+	s := &Service{cfg: cfg}
+	s.decay = newDecayPolicies(cfg, func(id string) string { return id })
+
+	now := time.Now()
+	stale := now.Add(-2 * 24 * time.Hour)
+	fresh := now.Add(-time.Hour)
+	inDay := fresh.Add(24 * time.Hour)
+	var d decayDecisions
+	s.evaluateDecay(decayingMemory{Type: TypeDecision, TTLSeconds: 86400, CreatedAt: stale}, now, &d)
+	s.evaluateDecay(decayingMemory{Type: TypeFix, TTLSeconds: 86400, CreatedAt: stale}, now, &d)
+	s.evaluateDecay(decayingMemory{Type: TypeFix, TTLSeconds: 86400, CreatedAt: fresh, ExpiresAt: &inDay}, now, &d)
+	s.evaluateDecay(decayingMemory{Type: TypeFix, TTLSeconds: 86400, CreatedAt: fresh, ExpiresAt: &now}, now, &d)
+
+	if len(d.promote) != 1 || len(d.expire) != 1 || len(d.rescheduleIDs) != 1 {
+		t.Fatalf("expected one promotion, expiry and reschedule, got %+v", d)
+	}
+	if !d.rescheduleAt[0].Equal(inDay) {
+		t.Fatalf("rescheduled to %v, want %v", d.rescheduleAt[0], inDay)
+	}
+}
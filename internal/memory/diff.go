@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContextLines = 3
+
+type diffOp struct {
+	kind   byte // ' ', '-', '+'
+	line   string
+	ai, bi int // position in a and b before this op
+}
+
+// renderVersion lays a snapshot out as text so metadata changes show up in
+// the diff alongside content changes.
+func renderVersion(v *MemoryVersion) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "title: %s\n", v.Title)
+	fmt.Fprintf(&sb, "type: %s\n", v.Type)
+	fmt.Fprintf(&sb, "tags: %s\n", strings.Join(v.Tags, ", "))
+	fmt.Fprintf(&sb, "importance: %.2f\n", v.Importance)
+	if v.Summary != nil {
+		fmt.Fprintf(&sb, "summary: %s\n", *v.Summary)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(v.Content)
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// unifiedDiff returns a line-based unified diff of a and b, or "" if they are
+// equal.
+func unifiedDiff(fromName, toName, a, b string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for c := 0; c < len(changes); {
+		start := max(changes[c]-diffContextLines, 0)
+		last := changes[c]
+		for c++; c < len(changes) && changes[c]-last <= 2*diffContextLines; c++ {
+			last = changes[c]
+		}
+		end := min(last+diffContextLines+1, len(ops))
+
+		aCount, bCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(ops[start].ai, aCount), hunkRange(ops[start].bi, bCount))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// diffLines computes an edit script from the longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i], ai: i, bi: j})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffOp{kind: '+', line: b[j], ai: i, bi: j})
+			j++
+		default:
+			ops = append(ops, diffOp{kind: '-', line: a[i], ai: i, bi: j})
+			i++
+		}
+	}
+	return ops
+}
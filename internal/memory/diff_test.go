@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff_Equal(t *testing.T) {
+	if got := unifiedDiff("v1", "v2", "a\nb\n", "a\nb\n"); got != "" {
+		t.Fatalf("expected empty diff, got %q", got)
+	}
+}
+
+func TestUnifiedDiff_SingleChange(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight"
+	b := "one\ntwo\nthree\nfour\nFIVE\nsix\nseven\neight"
+	want := strings.Join([]string{
+		"--- v1",
+		"+++ v2",
+		"@@ -2,7 +2,7 @@",
+		" two",
+		" three",
+		" four",
+		"-five",
+		"+FIVE",
+		" six",
+		" seven",
+		" eight",
+		"",
+	}, "\n")
+	if got := unifiedDiff("v1", "v2", a, b); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		line := string(rune('a' + i))
+		a = append(a, line)
+		b = append(b, line)
+	}
+	b[1] = "X"
+	b[18] = "Y"
+	got := unifiedDiff("v1", "v2", strings.Join(a, "\n"), strings.Join(b, "\n"))
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@") || !strings.Contains(got, "@@ -16,5 +16,5 @@") {
+		t.Fatalf("unexpected hunk headers:\n%s", got)
+	}
+}
+
+func TestUnifiedDiff_FromEmpty(t *testing.T) {
+	got := unifiedDiff("v1", "v2", "", "new\nlines")
+	if !strings.Contains(got, "@@ -0,0 +1,2 @@\n+new\n+lines\n") {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+}
+
+func TestRenderVersion_IncludesMetadata(t *testing.T) {
+	v := &MemoryVersion{Title: "t", Content: "body", Type: TypeDecision, Tags: []string{"a", "b"}, Importance: 0.5}
+	out := renderVersion(v)
+	for _, want := range []string{"title: t\n", "type: decision\n", "tags: a, b\n", "importance: 0.50\n", "\nbody"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in rendered version:\n%s", want, out)
+		}
+	}
+}
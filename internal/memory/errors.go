@@ -3,3 +3,46 @@ package memory
 import "errors"
 
 var ErrMemoryNotFound = errors.New("memory not found")
+
+var (
+	ErrReembedNotNeeded  = errors.New("stored embeddings already match the configured model")
+	ErrReembedInProgress = errors.New("an embedding migration is already in progress")
+	ErrReembedNotActive  = errors.New("no embedding migration is in progress")
+)
+
+var ErrVersionNotFound = errors.New("memory version not found")
+
+var (
+	ErrConsolidationNotFound        = errors.New("consolidation log entry not found")
+	ErrConsolidationAlreadyReverted = errors.New("consolidation has already been reverted")
+	ErrConsolidationNotRevertible   = errors.New("consolidation cannot be reverted")
+)
+
+var ErrInvalidArchive = errors.New("invalid archive")
+
+var ErrNoGraphPath = errors.New("no path between memories")
+
+var (
+	ErrRelationshipNotFound    = errors.New("relationship not found")
+	ErrRelationshipExists      = errors.New("relationship already exists")
+	ErrInvalidRelationship     = errors.New("invalid relationship")
+	ErrUnknownRelationshipType = errors.New("unknown relationship type")
+)
+
+var ErrInvalidSearch = errors.New("invalid search request")
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var ErrInvalidLanguage = errors.New("unsupported language")
+
+var (
+	ErrNotInTrash         = errors.New("memory is not in the trash")
+	ErrInvalidTrashReason = errors.New("invalid trash reason")
+)
+
+var ErrInvalidRetentionRule = errors.New("invalid retention rule")
+
+var (
+	ErrInvalidReviewAction = errors.New("invalid review action")
+	ErrNotExpiring         = errors.New("memory is long-term and does not expire")
+)
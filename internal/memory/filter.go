@@ -0,0 +1,498 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A search filter is a space-separated list of terms, all of which must hold.
+// A term is key:value; a leading "-" negates it. Values may be double-quoted
+// to contain spaces.
+//
+//	tag:a,b                 has any of the tags
+//	tag:a+b                 has all of the tags
+//	-tag:a,b                has none of the tags
+//	type:fix,solution       type (also scope, project, agent, lang) is one of
+//	created:>=2024-01-01    created_at (also updated) compared to a date
+//	updated:7d              updated within the last 7 days (h, d, w units)
+//	created:2024-01..2024-03-15  range, either end may be left open
+//	access:>=3              access_count compared to a number (also importance)
+//	has:relationship        linked to another memory in either direction
+//	has:relationship:SOLVES linked by one of the given relationship types
+//	include:expired         also match expired memories (replaced, all)
+//	is:expired              only expired memories (is:replaced likewise)
+//
+// Comparisons are =, >, >=, < and <=. A date without a time covers the whole
+// day, so created:<=2024-01-31 includes January 31st.
+
+// Filter is a parsed search filter.
+type Filter struct {
+	terms           []filterTerm
+	includeExpired  bool
+	includeReplaced bool
+}
+
+type filterTerm struct {
+	negate bool
+	clause filterClause
+}
+
+// filterClause compiles one term to a SQL condition on the memories table,
+// aliased m, appending its parameters to b.
+type filterClause interface {
+	sql(b *filterArgs) string
+}
+
+// filterArgs numbers the parameters of a compiled filter, continuing from
+// the caller's last placeholder.
+type filterArgs struct {
+	args []any
+	idx  int
+}
+
+func (b *filterArgs) add(v any) string {
+	b.args = append(b.args, v)
+	b.idx++
+	return fmt.Sprintf("$%d", b.idx-1)
+}
+
+// ParseFilter parses a filter expression, resolving relative dates against
+// now. An empty expression returns nil.
+func ParseFilter(expr string, now time.Time) (*Filter, error) {
+	tokens, err := splitFilterTerms(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	f := &Filter{}
+	for _, tok := range tokens {
+		negate := false
+		if strings.HasPrefix(tok, "-") {
+			negate = true
+			tok = tok[1:]
+		}
+		key, value, ok := strings.Cut(tok, ":")
+		key = strings.ToLower(key)
+		if !ok || key == "" || value == "" {
+			return nil, filterError("term %q is not key:value", tok)
+		}
+
+		var clause filterClause
+		switch key {
+		case "tag", "tags":
+			clause, err = parseTagClause(value)
+		case "type", "scope", "project", "agent", "lang":
+			clause, err = parseColumnClause(key, value)
+		case "created", "updated":
+			clause, err = parseTimeClause(key, value, now)
+		case "access", "importance":
+			clause, err = parseNumberClause(key, value)
+		case "has":
+			clause, err = parseHasClause(value)
+		case "include":
+			if negate {
+				return nil, filterError("include: cannot be negated")
+			}
+			err = f.parseInclude(value)
+		case "is":
+			clause, err = f.parseIs(value, negate)
+		default:
+			return nil, filterError("unknown filter key %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if clause != nil {
+			f.terms = append(f.terms, filterTerm{negate: negate, clause: clause})
+		}
+	}
+	return f, nil
+}
+
+// IncludeExpired reports whether the filter lifts the default exclusion of
+// expired memories.
+func (f *Filter) IncludeExpired() bool { return f != nil && f.includeExpired }
+
+// IncludeReplaced reports whether the filter lifts the default exclusion of
+// replaced memories.
+func (f *Filter) IncludeReplaced() bool { return f != nil && f.includeReplaced }
+
+// Compile returns the filter's conditions with their parameters, numbered
+// from $argIdx.
+func (f *Filter) Compile(argIdx int) ([]string, []any) {
+	if f == nil {
+		return nil, nil
+	}
+	b := &filterArgs{idx: argIdx}
+	conds := make([]string, 0, len(f.terms))
+	for _, t := range f.terms {
+		cond := t.clause.sql(b)
+		if t.negate {
+			// NULL columns count as not matching, so they satisfy the negation.
+			cond = "NOT COALESCE((" + cond + "), false)"
+		}
+		conds = append(conds, cond)
+	}
+	return conds, b.args
+}
+
+// mapProjects rewrites the values of project terms, e.g. to normalize them.
+func (f *Filter) mapProjects(fn func(string) string) {
+	if f == nil {
+		return
+	}
+	for _, t := range f.terms {
+		if c, ok := t.clause.(*columnClause); ok && c.column == "m.project_id" {
+			for i, v := range c.values {
+				c.values[i] = fn(v)
+			}
+		}
+	}
+}
+
+func (f *Filter) parseInclude(value string) error {
+	switch strings.ToLower(value) {
+	case "expired":
+		f.includeExpired = true
+	case "replaced":
+		f.includeReplaced = true
+	case "all":
+		f.includeExpired, f.includeReplaced = true, true
+	default:
+		return filterError("include: wants expired, replaced or all, got %q", value)
+	}
+	return nil
+}
+
+// parseIs handles is:expired and is:replaced. The positive form selects
+// memories the default filters exclude, so it also lifts that exclusion; the
+// negated form restates the default and adds nothing.
+func (f *Filter) parseIs(value string, negate bool) (filterClause, error) {
+	var cond string
+	switch strings.ToLower(value) {
+	case "expired":
+		cond = "m.expires_at IS NOT NULL AND m.expires_at <= NOW()"
+		if !negate {
+			f.includeExpired = true
+		}
+	case "replaced":
+		cond = "m.replaced_by IS NOT NULL"
+		if !negate {
+			f.includeReplaced = true
+		}
+	default:
+		return nil, filterError("is: wants expired or replaced, got %q", value)
+	}
+	return rawClause(cond), nil
+}
+
+type rawClause string
+
+func (c rawClause) sql(*filterArgs) string { return string(c) }
+
+type tagClause struct {
+	tags []string
+	all  bool
+}
+
+func parseTagClause(value string) (filterClause, error) {
+	sep, all := ",", false
+	if strings.Contains(value, "+") {
+		if strings.Contains(value, ",") {
+			return nil, filterError("tag: mixes , (any) and + (all) in %q", value)
+		}
+		sep, all = "+", true
+	}
+	tags := splitValues(value, sep)
+	if len(tags) == 0 {
+		return nil, filterError("tag: needs at least one tag")
+	}
+	return &tagClause{tags: tags, all: all}, nil
+}
+
+func (c *tagClause) sql(b *filterArgs) string {
+	op := "&&"
+	if c.all {
+		op = "@>"
+	}
+	return fmt.Sprintf("m.tags %s %s::text[]", op, b.add(c.tags))
+}
+
+var filterColumns = map[string]string{
+	"type":    "m.type",
+	"scope":   "m.scope",
+	"project": "m.project_id",
+	"agent":   "m.agent_source",
+	"lang":    "m.language::text",
+}
+
+type columnClause struct {
+	column string
+	values []string
+}
+
+func parseColumnClause(key, value string) (filterClause, error) {
+	values := splitValues(value, ",")
+	if len(values) == 0 {
+		return nil, filterError("%s: needs at least one value", key)
+	}
+	return &columnClause{column: filterColumns[key], values: values}, nil
+}
+
+func (c *columnClause) sql(b *filterArgs) string {
+	return fmt.Sprintf("%s = ANY(%s::text[])", c.column, b.add(c.values))
+}
+
+type hasRelationshipClause struct {
+	types []string
+}
+
+func parseHasClause(value string) (filterClause, error) {
+	what, types, _ := strings.Cut(value, ":")
+	if strings.ToLower(what) != "relationship" {
+		return nil, filterError("has: wants relationship, got %q", what)
+	}
+	return &hasRelationshipClause{types: canonicalRelationshipFilter(splitValues(types, ","))}, nil
+}
+
+func (c *hasRelationshipClause) sql(b *filterArgs) string {
+	cond := "EXISTS (SELECT 1 FROM memory_relationships r WHERE (r.from_memory_id = m.id OR r.to_memory_id = m.id)"
+	if len(c.types) > 0 {
+		cond += fmt.Sprintf(" AND r.relationship = ANY(%s::text[])", b.add(c.types))
+	}
+	return cond + ")"
+}
+
+// rangeClause compares a column with a lower and/or upper bound.
+type rangeClause struct {
+	column string
+	cast   string
+	lo, hi any
+	loOp   string // ">" or ">="
+	hiOp   string // "<" or "<="
+	hasLo  bool
+	hasHi  bool
+}
+
+func (c *rangeClause) sql(b *filterArgs) string {
+	var parts []string
+	if c.hasLo {
+		parts = append(parts, fmt.Sprintf("%s %s %s%s", c.column, c.loOp, b.add(c.lo), c.cast))
+	}
+	if c.hasHi {
+		parts = append(parts, fmt.Sprintf("%s %s %s%s", c.column, c.hiOp, b.add(c.hi), c.cast))
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// splitComparison splits a value into its comparison operator and operand.
+// A value without an operator compares for equality.
+func splitComparison(value string) (op, operand string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, op) {
+			return op, value[len(op):]
+		}
+	}
+	return "=", value
+}
+
+func parseNumberClause(key, value string) (filterClause, error) {
+	c := &rangeClause{column: "m.access_count", cast: "::int"}
+	parse := func(s string) (any, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, filterError("%s: %q is not a whole number", key, s)
+		}
+		return n, nil
+	}
+	if key == "importance" {
+		c.column, c.cast = "m.importance", "::real"
+		parse = func(s string) (any, error) {
+			n, err := strconv.ParseFloat(s, 32)
+			if err != nil {
+				return nil, filterError("%s: %q is not a number", key, s)
+			}
+			return float32(n), nil
+		}
+	}
+
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		var err error
+		if lo != "" {
+			if c.lo, err = parse(lo); err != nil {
+				return nil, err
+			}
+			c.hasLo, c.loOp = true, ">="
+		}
+		if hi != "" {
+			if c.hi, err = parse(hi); err != nil {
+				return nil, err
+			}
+			c.hasHi, c.hiOp = true, "<="
+		}
+		if !c.hasLo && !c.hasHi {
+			return nil, filterError("%s: empty range", key)
+		}
+		return c, nil
+	}
+
+	op, operand := splitComparison(value)
+	n, err := parse(operand)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case ">", ">=":
+		c.lo, c.loOp, c.hasLo = n, op, true
+	case "<", "<=":
+		c.hi, c.hiOp, c.hasHi = n, op, true
+	default:
+		c.lo, c.loOp, c.hasLo = n, ">=", true
+		c.hi, c.hiOp, c.hasHi = n, "<=", true
+	}
+	return c, nil
+}
+
+func parseTimeClause(key, value string, now time.Time) (filterClause, error) {
+	c := &rangeClause{column: "m.created_at", cast: "::timestamptz"}
+	if key == "updated" {
+		c.column = "m.updated_at"
+	}
+
+	// A bare duration means "within the last ...".
+	if d, ok := parseRelative(value); ok {
+		c.lo, c.loOp, c.hasLo = now.Add(-d), ">=", true
+		return c, nil
+	}
+
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		if lo != "" {
+			start, _, err := parseFilterTime(key, lo, now)
+			if err != nil {
+				return nil, err
+			}
+			c.lo, c.loOp, c.hasLo = start, ">=", true
+		}
+		if hi != "" {
+			_, end, err := parseFilterTime(key, hi, now)
+			if err != nil {
+				return nil, err
+			}
+			c.hi, c.hiOp, c.hasHi = end, "<", true
+		}
+		if !c.hasLo && !c.hasHi {
+			return nil, filterError("%s: empty range", key)
+		}
+		return c, nil
+	}
+
+	op, operand := splitComparison(value)
+	start, end, err := parseFilterTime(key, operand, now)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case ">":
+		c.lo, c.loOp, c.hasLo = end, ">=", true
+	case ">=":
+		c.lo, c.loOp, c.hasLo = start, ">=", true
+	case "<":
+		c.hi, c.hiOp, c.hasHi = start, "<", true
+	case "<=":
+		c.hi, c.hiOp, c.hasHi = end, "<", true
+	default:
+		c.lo, c.loOp, c.hasLo = start, ">=", true
+		c.hi, c.hiOp, c.hasHi = end, "<", true
+	}
+	return c, nil
+}
+
+// parseFilterTime parses a date, month, RFC 3339 time or relative duration
+// and returns the span it covers. A point in time covers only itself.
+func parseFilterTime(key, s string, now time.Time) (start, end time.Time, err error) {
+	if d, ok := parseRelative(s); ok {
+		t := now.Add(-d)
+		return t, t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, t.AddDate(0, 0, 1), nil
+	}
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return t, t.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, filterError("%s: %q is not a date (YYYY-MM-DD, YYYY-MM, RFC 3339 or a duration like 7d)", key, s)
+}
+
+// parseRelative parses durations like 12h, 7d and 2w.
+func parseRelative(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	switch s[len(s)-1] {
+	case 'h':
+		return time.Duration(n) * time.Hour, true
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, true
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
+
+// splitFilterTerms splits expr on whitespace outside double quotes and
+// removes the quotes.
+func splitFilterTerms(expr string) ([]string, error) {
+	var terms []string
+	var cur strings.Builder
+	inQuotes, started := false, false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			started = true
+		case unicode.IsSpace(r) && !inQuotes:
+			if started {
+				terms = append(terms, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if inQuotes {
+		return nil, filterError("unterminated quote")
+	}
+	if started {
+		terms = append(terms, cur.String())
+	}
+	return terms, nil
+}
+
+func splitValues(value, sep string) []string {
+	var out []string
+	for _, v := range strings.Split(value, sep) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func filterError(format string, args ...any) error {
+	return fmt.Errorf("%w: filter: %s", ErrInvalidSearch, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFilter_Compile(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	f, err := ParseFilter(`tag:redis,postgres -tag:wip+draft type:fix created:2024-01..2024-03-15 updated:7d access:>=3 -has:relationship:solves project:"my app"`, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conds, args := f.Compile(5)
+
+	wantConds := []string{
+		"m.tags && $5::text[]",
+		"NOT COALESCE((m.tags @> $6::text[]), false)",
+		"m.type = ANY($7::text[])",
+		"m.created_at >= $8::timestamptz AND m.created_at < $9::timestamptz",
+		"m.updated_at >= $10::timestamptz",
+		"m.access_count >= $11::int",
+		"NOT COALESCE((EXISTS (SELECT 1 FROM memory_relationships r WHERE (r.from_memory_id = m.id OR r.to_memory_id = m.id) AND r.relationship = ANY($12::text[]))), false)",
+		"m.project_id = ANY($13::text[])",
+	}
+	if !reflect.DeepEqual(conds, wantConds) {
+		t.Fatalf("conditions:\n got %q\nwant %q", conds, wantConds)
+	}
+	wantArgs := []any{
+		[]string{"redis", "postgres"},
+		[]string{"wip", "draft"},
+		[]string{"fix"},
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC),
+		now.Add(-7 * 24 * time.Hour),
+		3,
+		[]string{"SOLVES"},
+		[]string{"my app"},
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args:\n got %v\nwant %v", args, wantArgs)
+	}
+	if f.IncludeExpired() || f.IncludeReplaced() {
+		t.Fatalf("expired and replaced memories should stay excluded")
+	}
+}
+
+func TestParseFilter_DateComparisonsCoverWholeDays(t *testing.T) {
+	day := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	next := day.AddDate(0, 0, 1)
+	cases := map[string]struct {
+		cond string
+		arg  time.Time
+	}{
+		"created:>2024-01-31":  {"m.created_at >= $1::timestamptz", next},
+		"created:>=2024-01-31": {"m.created_at >= $1::timestamptz", day},
+		"created:<2024-01-31":  {"m.created_at < $1::timestamptz", day},
+		"created:<=2024-01-31": {"m.created_at < $1::timestamptz", next},
+	}
+	for expr, want := range cases {
+		f, err := ParseFilter(expr, time.Now())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", expr, err)
+		}
+		conds, args := f.Compile(1)
+		if len(conds) != 1 || conds[0] != want.cond || args[0] != want.arg {
+			t.Errorf("%s: got %q %v, want %q %v", expr, conds, args, want.cond, want.arg)
+		}
+	}
+}
+
+func TestParseFilter_Inclusion(t *testing.T) {
+	f, err := ParseFilter("include:expired", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conds, _ := f.Compile(1); len(conds) != 0 || !f.IncludeExpired() || f.IncludeReplaced() {
+		t.Fatalf("include:expired should only lift the expiry exclusion: %q", conds)
+	}
+
+	f, err = ParseFilter("is:replaced", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conds, _ := f.Compile(1); !f.IncludeReplaced() || len(conds) != 1 || conds[0] != "m.replaced_by IS NOT NULL" {
+		t.Fatalf("is:replaced should select only replaced memories: %q", conds)
+	}
+
+	f, err = ParseFilter("-is:expired", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.IncludeExpired() {
+		t.Fatalf("-is:expired must not include expired memories")
+	}
+
+	if f, err := ParseFilter("  ", time.Now()); err != nil || f != nil {
+		t.Fatalf("blank filter should parse to nil, got %v, %v", f, err)
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"redis",
+		"color:blue",
+		"tag:a,b+c",
+		"access:many",
+		"created:yesterday",
+		"created:..",
+		"has:summary",
+		"-include:expired",
+		"is:deleted",
+		`tag:"unterminated`,
+	} {
+		_, err := ParseFilter(expr, time.Now())
+		if !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("%s: expected ErrInvalidSearch, got %v", expr, err)
+		} else if !strings.Contains(err.Error(), "filter") {
+			t.Errorf("%s: error should mention the filter: %v", expr, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Traversals walk breadth-first with one query per level, so depth and node
+// count are capped to bound the queries and the response.
+const (
+	defaultGraphDepth = 2
+	maxGraphDepth     = 5
+	defaultGraphLimit = 100
+	maxGraphLimit     = 500
+)
+
+// normalizeGraphRequest fills defaults and clamps bounds.
+func normalizeGraphRequest(req GraphRequest) GraphRequest {
+	if req.Depth <= 0 {
+		req.Depth = defaultGraphDepth
+	}
+	if req.Depth > maxGraphDepth {
+		req.Depth = maxGraphDepth
+	}
+	if req.Direction == "" {
+		req.Direction = DirectionBoth
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultGraphLimit
+	}
+	if req.Limit > maxGraphLimit {
+		req.Limit = maxGraphLimit
+	}
+	if req.MinStrength < 0 {
+		req.MinStrength = 0
+	}
+	req.RelationshipTypes = canonicalRelationshipFilter(req.RelationshipTypes)
+	return req
+}
+
+// TraverseGraph returns the subgraph reachable from rootID. The root itself
+// is the first node, at depth 0.
+func (s *Service) TraverseGraph(ctx context.Context, rootID uuid.UUID, req GraphRequest) (*Graph, error) {
+	req = normalizeGraphRequest(req)
+	if !ValidDirections[req.Direction] {
+		return nil, fmt.Errorf("unknown direction %q", req.Direction)
+	}
+	if err := s.requireMemory(ctx, rootID); err != nil {
+		return nil, err
+	}
+	return s.repo.TraverseGraph(ctx, rootID, req)
+}
+
+// ShortestPath returns a path with the fewest hops from fromID to toID that
+// follows req's filters. Depth defaults to defaultGraphDepth; ErrNoGraphPath
+// is returned when no path exists within it.
+func (s *Service) ShortestPath(ctx context.Context, fromID, toID uuid.UUID, req GraphRequest) (*GraphPath, error) {
+	req = normalizeGraphRequest(req)
+	if !ValidDirections[req.Direction] {
+		return nil, fmt.Errorf("unknown direction %q", req.Direction)
+	}
+	if err := s.requireMemory(ctx, fromID); err != nil {
+		return nil, err
+	}
+	if err := s.requireMemory(ctx, toID); err != nil {
+		return nil, err
+	}
+	if fromID == toID {
+		mem, err := s.repo.Get(ctx, fromID)
+		if err != nil {
+			return nil, err
+		}
+		mem.Embedding = nil
+		return &GraphPath{Nodes: []Memory{*mem}, Edges: []Relationship{}}, nil
+	}
+
+	path, err := s.repo.ShortestPath(ctx, fromID, toID, req)
+	if err != nil {
+		return nil, err
+	}
+	if path == nil {
+		return nil, fmt.Errorf("%w: %s to %s within %d hops", ErrNoGraphPath, fromID, toID, req.Depth)
+	}
+	return path, nil
+}
+
+// DOT renders the graph in Graphviz format, labelling nodes with titles and
+// edges with relationship type and strength.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph memories {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		attrs := ""
+		if n.Memory.ID == g.Root {
+			attrs = ", style=bold"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q%s];\n", n.Memory.ID.String(), dotLabel(n.Memory), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n",
+			e.FromMemoryID.String(), e.ToMemoryID.String(),
+			fmt.Sprintf("%s (%.2f)", e.Relationship, e.Strength))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotLabel(m Memory) string {
+	title := m.Title
+	if r := []rune(title); len(r) > 60 {
+		title = string(r[:57]) + "..."
+	}
+	return fmt.Sprintf("%s\n[%s]", title, m.Type)
+}
@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// graphLevelQuery expands one breadth-first level: the memories one hop
+// from the frontier $1 within workspace $2 that were not visited yet ($6),
+// each with the frontier memory and relationship it was first reached by.
+// $3 is the direction, $4 the minimum strength and $5 the relationship types
+// (NULL for any). Trashed memories are not entered. Up to $7 memories are
+// returned (NULL for all), most important first.
+//
+// The walk is driven from Go rather than by one recursive CTE. A recursive
+// CTE cannot see the rows of earlier iterations, only the previous one, so
+// it cannot skip memories reached on an earlier level and enumerates every
+// path instead, which grows exponentially on dense graphs. Passing the
+// visited set into each level keeps every memory to a single visit at the
+// cost of one query per hop, at most the maximum depth of 5.
+const graphLevelQuery = `
+	SELECT next, prev, edge FROM (
+		SELECT DISTINCT ON (n.next) n.next, f.id AS prev, r.id AS edge, m.importance
+		FROM unnest($1::uuid[]) AS f(id)
+		JOIN memory_relationships r
+		  ON r.workspace_id = $2
+		 AND ((r.from_memory_id = f.id AND $3 <> 'incoming') OR (r.to_memory_id = f.id AND $3 <> 'outgoing'))
+		CROSS JOIN LATERAL (
+			SELECT CASE WHEN r.from_memory_id = f.id THEN r.to_memory_id ELSE r.from_memory_id END AS next
+		) n
+		JOIN memories m ON m.id = n.next AND m.workspace_id = $2 AND m.deleted_at IS NULL
+		WHERE r.strength >= $4
+		  AND ($5::text[] IS NULL OR r.relationship = ANY($5))
+		  AND NOT n.next = ANY($6)
+		ORDER BY n.next, r.strength DESC, r.id
+	) level
+	ORDER BY importance DESC, next
+	LIMIT $7`
+
+// graphStep is a memory reached by the walk, with the memory and
+// relationship it was reached from.
+type graphStep struct {
+	ID, Prev, Edge uuid.UUID
+}
+
+// graphLevel returns the unvisited neighbours of frontier that pass req's
+// filters, at most limit of them when limit is not nil.
+func (r *Repository) graphLevel(ctx context.Context, frontier, visited []uuid.UUID, req GraphRequest, limit *int) ([]graphStep, error) {
+	var types []string
+	if len(req.RelationshipTypes) > 0 {
+		types = req.RelationshipTypes
+	}
+	rows, err := r.pool.Query(ctx, graphLevelQuery,
+		frontier, WorkspaceFromContext(ctx), string(req.Direction), req.MinStrength, types, visited, limit)
+	if err != nil {
+		return nil, fmt.Errorf("expand graph level: %w", err)
+	}
+	defer rows.Close()
+
+	var steps []graphStep
+	for rows.Next() {
+		var s graphStep
+		if err := rows.Scan(&s.ID, &s.Prev, &s.Edge); err != nil {
+			return nil, fmt.Errorf("scan graph step: %w", err)
+		}
+		steps = append(steps, s)
+	}
+	return steps, rows.Err()
+}
+
+const graphMemoryColumns = `m.id, m.title, m.content, m.summary, m.type, m.scope, m.project_id,
+	m.agent_source, m.language::text, m.tags, m.importance, m.ttl_seconds, m.access_count,
+	m.created_at, m.updated_at, m.expires_at, m.version, m.replaced_by`
+
+func scanGraphMemory(row pgx.Row, m *Memory, extra ...any) error {
+	dest := []any{
+		&m.ID, &m.Title, &m.Content, &m.Summary, &m.Type, &m.Scope, &m.ProjectID,
+		&m.AgentSource, &m.Language, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
+		&m.CreatedAt, &m.UpdatedAt, &m.ExpiresAt, &m.Version, &m.ReplacedBy,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// TraverseGraph returns the memories within req.Depth hops of rootID, closest
+// first, and the relationships among them that pass the filters. The walk is
+// breadth-first, one query per level, and visits each memory once; it stops
+// once it has found more than req.Limit memories.
+func (r *Repository) TraverseGraph(ctx context.Context, rootID uuid.UUID, req GraphRequest) (*Graph, error) {
+	visited := []uuid.UUID{rootID}
+	depths := []int32{0}
+	frontier := []uuid.UUID{rootID}
+	for depth := 1; depth <= req.Depth && len(frontier) > 0 && len(visited) <= req.Limit; depth++ {
+		remaining := req.Limit + 1 - len(visited)
+		steps, err := r.graphLevel(ctx, frontier, visited, req, &remaining)
+		if err != nil {
+			return nil, fmt.Errorf("traverse graph: %w", err)
+		}
+		frontier = frontier[:0]
+		for _, s := range steps {
+			visited = append(visited, s.ID)
+			depths = append(depths, int32(depth))
+			frontier = append(frontier, s.ID)
+		}
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+graphMemoryColumns+`, d.depth
+		FROM unnest($1::uuid[], $2::int[]) AS d(id, depth)
+		JOIN memories m ON m.id = d.id AND m.workspace_id = $3
+		ORDER BY d.depth, m.importance DESC, m.id
+		LIMIT $4
+	`, visited, depths, WorkspaceFromContext(ctx), req.Limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("traverse graph: %w", err)
+	}
+	defer rows.Close()
+
+	g := &Graph{Root: rootID, Nodes: []GraphNode{}, Edges: []Relationship{}}
+	for rows.Next() {
+		var n GraphNode
+		if err := scanGraphMemory(rows, &n.Memory, &n.Depth); err != nil {
+			return nil, fmt.Errorf("scan graph node: %w", err)
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("traverse graph: %w", err)
+	}
+	if len(g.Nodes) > req.Limit {
+		g.Nodes = g.Nodes[:req.Limit]
+		g.Truncated = true
+	}
+
+	ids := make([]uuid.UUID, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[i] = n.Memory.ID
+	}
+	g.Edges, err = r.relationshipsAmong(ctx, ids, req)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// relationshipsAmong returns the relationships with both ends in ids that
+// pass req's strength and type filters.
+func (r *Repository) relationshipsAmong(ctx context.Context, ids []uuid.UUID, req GraphRequest) ([]Relationship, error) {
+	var types []string
+	if len(req.RelationshipTypes) > 0 {
+		types = req.RelationshipTypes
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, from_memory_id, to_memory_id, relationship, strength, context, created_at
+		FROM memory_relationships
+		WHERE workspace_id = $1 AND from_memory_id = ANY($2) AND to_memory_id = ANY($2)
+		  AND strength >= $3 AND ($4::text[] IS NULL OR relationship = ANY($4))
+		ORDER BY strength DESC, id
+	`, WorkspaceFromContext(ctx), ids, req.MinStrength, types)
+	if err != nil {
+		return nil, fmt.Errorf("get graph edges: %w", err)
+	}
+	defer rows.Close()
+
+	edges := []Relationship{}
+	for rows.Next() {
+		var rel Relationship
+		if err := rows.Scan(&rel.ID, &rel.FromMemoryID, &rel.ToMemoryID, &rel.Relationship, &rel.Strength, &rel.Context, &rel.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan graph edge: %w", err)
+		}
+		edges = append(edges, rel)
+	}
+	return edges, rows.Err()
+}
+
+// ShortestPath returns a fewest-hops path from fromID to toID, or nil if there
+// is none within req.Depth hops. It walks breadth-first from fromID, visiting
+// each memory once, and stops at the level that reaches toID.
+func (r *Repository) ShortestPath(ctx context.Context, fromID, toID uuid.UUID, req GraphRequest) (*GraphPath, error) {
+	reachedBy := map[uuid.UUID]graphStep{}
+	visited := []uuid.UUID{fromID}
+	frontier := []uuid.UUID{fromID}
+	found := false
+	for depth := 1; depth <= req.Depth && len(frontier) > 0 && !found; depth++ {
+		steps, err := r.graphLevel(ctx, frontier, visited, req, nil)
+		if err != nil {
+			return nil, fmt.Errorf("shortest path: %w", err)
+		}
+		frontier = frontier[:0]
+		for _, s := range steps {
+			reachedBy[s.ID] = s
+			visited = append(visited, s.ID)
+			frontier = append(frontier, s.ID)
+			found = found || s.ID == toID
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// Follow the steps back from toID.
+	nodeIDs := []uuid.UUID{toID}
+	var edgeIDs []uuid.UUID
+	for id := toID; id != fromID; {
+		s := reachedBy[id]
+		nodeIDs = append(nodeIDs, s.Prev)
+		edgeIDs = append(edgeIDs, s.Edge)
+		id = s.Prev
+	}
+	slices.Reverse(nodeIDs)
+	slices.Reverse(edgeIDs)
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+graphMemoryColumns+`
+		FROM memories m
+		WHERE m.id = ANY($1) AND m.workspace_id = $2
+	`, nodeIDs, WorkspaceFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("get path memories: %w", err)
+	}
+	byID := make(map[uuid.UUID]Memory, len(nodeIDs))
+	for rows.Next() {
+		var m Memory
+		if err := scanGraphMemory(rows, &m); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan path memory: %w", err)
+		}
+		byID[m.ID] = m
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get path memories: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, from_memory_id, to_memory_id, relationship, strength, context, created_at
+		FROM memory_relationships
+		WHERE id = ANY($1)
+	`, edgeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get path edges: %w", err)
+	}
+	edgeByID := make(map[uuid.UUID]Relationship, len(edgeIDs))
+	for rows.Next() {
+		var rel Relationship
+		if err := rows.Scan(&rel.ID, &rel.FromMemoryID, &rel.ToMemoryID, &rel.Relationship, &rel.Strength, &rel.Context, &rel.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan path edge: %w", err)
+		}
+		edgeByID[rel.ID] = rel
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get path edges: %w", err)
+	}
+
+	path := &GraphPath{Hops: len(edgeIDs)}
+	for _, id := range nodeIDs {
+		m, ok := byID[id]
+		if !ok {
+			// Deleted between the walk and the lookup.
+			return nil, nil
+		}
+		path.Nodes = append(path.Nodes, m)
+	}
+	for _, id := range edgeIDs {
+		e, ok := edgeByID[id]
+		if !ok {
+			return nil, nil
+		}
+		path.Edges = append(path.Edges, e)
+	}
+	return path, nil
+}
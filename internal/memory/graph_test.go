@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeGraphRequest(t *testing.T) {
+	req := normalizeGraphRequest(GraphRequest{})
+	if req.Depth != defaultGraphDepth || req.Direction != DirectionBoth || req.Limit != defaultGraphLimit {
+		t.Fatalf("unexpected defaults: %+v", req)
+	}
+
+	req = normalizeGraphRequest(GraphRequest{Depth: 50, Limit: 10000, MinStrength: -1, Direction: DirectionIncoming})
+	if req.Depth != maxGraphDepth || req.Limit != maxGraphLimit || req.MinStrength != 0 {
+		t.Fatalf("bounds not clamped: %+v", req)
+	}
+	if req.Direction != DirectionIncoming {
+		t.Fatalf("explicit direction overwritten: %+v", req)
+	}
+}
+
+func TestGraphDOT(t *testing.T) {
+	errID, fixID := uuid.New(), uuid.New()
+	g := &Graph{
+		Root: errID,
+		Nodes: []GraphNode{
+			{Memory: Memory{ID: errID, Title: `Timeout "dial tcp"`, Type: TypeError}},
+			{Memory: Memory{ID: fixID, Title: "Raise pool size", Type: TypeFix}, Depth: 1},
+		},
+		Edges: []Relationship{{FromMemoryID: fixID, ToMemoryID: errID, Relationship: "SOLVES", Strength: 0.9}},
+	}
+
+	dot := g.DOT()
+	if !strings.HasPrefix(dot, "digraph memories {") || !strings.HasSuffix(dot, "}\n") {
+		t.Fatalf("not a digraph:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"`+fixID.String()+`" -> "`+errID.String()+`" [label="SOLVES (0.90)"]`) {
+		t.Fatalf("missing edge:\n%s", dot)
+	}
+	if !strings.Contains(dot, `Timeout \"dial tcp\"`) {
+		t.Fatalf("title quotes not escaped:\n%s", dot)
+	}
+	if !strings.Contains(dot, "style=bold") {
+		t.Fatalf("root not highlighted:\n%s", dot)
+	}
+}
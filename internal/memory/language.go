@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// LanguageSimple is the text search configuration for code and text in no
+// particular language: words are lowercased but neither stemmed nor dropped
+// as stopwords, so identifiers and flags match exactly.
+const LanguageSimple = "simple"
+
+// languages are the PostgreSQL text search configurations a memory can use.
+var languages = map[string]bool{
+	LanguageSimple: true,
+	"arabic":       true, "danish": true, "dutch": true, "english": true,
+	"finnish": true, "french": true, "german": true, "greek": true,
+	"hungarian": true, "indonesian": true, "irish": true, "italian": true,
+	"lithuanian": true, "nepali": true, "norwegian": true, "portuguese": true,
+	"romanian": true, "russian": true, "spanish": true, "swedish": true,
+	"tamil": true, "turkish": true,
+}
+
+// ValidLanguage reports whether lang names a supported text search
+// configuration.
+func ValidLanguage(lang string) bool {
+	return languages[lang]
+}
+
+// Languages returns the supported text search configurations, sorted.
+func Languages() []string {
+	out := make([]string, 0, len(languages))
+	for l := range languages {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// stopwords are frequent short words that mark a language. A word listed for
+// several languages splits its vote between them.
+var stopwords = map[string][]string{
+	"english":    {"the", "and", "is", "are", "was", "with", "this", "that", "for", "not", "have", "from", "when", "should", "be", "it", "of", "to"},
+	"german":     {"der", "die", "das", "und", "ist", "nicht", "mit", "ein", "eine", "auf", "für", "sich", "auch", "wird", "werden", "wenn", "nach", "bei"},
+	"turkish":    {"ve", "bir", "bu", "için", "ile", "olarak", "değil", "daha", "çok", "gibi", "ama", "olan", "şu", "ya", "da", "de", "ki", "mi"},
+	"french":     {"le", "les", "et", "est", "une", "des", "pour", "dans", "pas", "qui", "sur", "avec", "sont", "du", "au", "ce", "il"},
+	"spanish":    {"el", "los", "las", "y", "es", "una", "para", "por", "con", "del", "que", "se", "no", "lo", "como", "pero", "está"},
+	"italian":    {"il", "gli", "e", "è", "della", "per", "che", "non", "sono", "con", "del", "una", "lo", "anche", "questo", "come"},
+	"portuguese": {"o", "os", "as", "e", "é", "um", "uma", "para", "com", "não", "do", "da", "em", "que", "mais", "são"},
+	"dutch":      {"de", "het", "een", "en", "is", "van", "niet", "met", "voor", "op", "dat", "zijn", "ook", "wordt", "bij"},
+}
+
+// stopwordLanguages maps each stopword to the languages that list it.
+var stopwordLanguages = func() map[string][]string {
+	m := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			m[w] = append(m[w], lang)
+		}
+	}
+	return m
+}()
+
+// letterHints are letters used by one language among those in stopwords.
+var letterHints = map[rune]string{
+	'ğ': "turkish", 'ş': "turkish", 'ı': "turkish", 'İ': "turkish",
+	'ß': "german",
+	'ñ': "spanish", '¿': "spanish", '¡': "spanish",
+	'ã': "portuguese", 'õ': "portuguese",
+}
+
+// textLanguage picks the text search configuration for a memory's text:
+// search.language.default, or a detected language when detection is on.
+func (s *Service) textLanguage(title, content string) string {
+	lang := s.searchCfg.Language.Default
+	if s.searchCfg.Language.Detect {
+		lang = detectLanguage(title+"\n"+content, lang)
+	}
+	return lang
+}
+
+// detectLanguage guesses the text search configuration for text. Code and
+// other symbol-heavy text gets LanguageSimple; text in a non-Latin script gets
+// that script's language; Latin text is decided by stopwords and distinctive
+// letters. It returns fallback when nothing stands out.
+func detectLanguage(text, fallback string) string {
+	if looksLikeCode(text) {
+		return LanguageSimple
+	}
+
+	var letters, cyrillic, greek, arabic, tamil, devanagari int
+	votes := make(map[string]float64)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Greek, r):
+			greek++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Tamil, r):
+			tamil++
+		case unicode.Is(unicode.Devanagari, r):
+			devanagari++
+		}
+		if lang, ok := letterHints[r]; ok {
+			votes[lang] += 0.5
+		}
+	}
+	if letters == 0 {
+		return fallback
+	}
+	for _, s := range []struct {
+		n    int
+		lang string
+	}{{cyrillic, "russian"}, {greek, "greek"}, {arabic, "arabic"}, {tamil, "tamil"}, {devanagari, "nepali"}} {
+		if s.n*2 > letters {
+			return s.lang
+		}
+	}
+
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		langs := stopwordLanguages[w]
+		for _, l := range langs {
+			votes[l] += 1 / float64(len(langs))
+		}
+	}
+
+	best, bestVotes, runnerUp := fallback, 0.0, 0.0
+	for _, lang := range Languages() {
+		v := votes[lang]
+		if v > bestVotes {
+			best, bestVotes, runnerUp = lang, v, bestVotes
+		} else if v > runnerUp {
+			runnerUp = v
+		}
+	}
+	// Require a few clear signals before moving away from the fallback.
+	if bestVotes < 2 || bestVotes < runnerUp*1.5 {
+		return fallback
+	}
+	return best
+}
+
+// looksLikeCode reports whether text is mostly code: many of its lines end
+// or start like statements, or symbols make up a large share of it.
+func looksLikeCode(text string) bool {
+	var symbols, visible int
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		visible++
+		if strings.ContainsRune("{}()[];=<>_/\\|&*$#:`\"", r) {
+			symbols++
+		}
+	}
+	if visible == 0 {
+		return false
+	}
+	if float64(symbols)/float64(visible) > 0.12 {
+		return true
+	}
+
+	var lines, codeLines int
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines++
+		if strings.HasSuffix(line, ";") || strings.HasSuffix(line, "{") || strings.HasSuffix(line, "}") ||
+			strings.HasPrefix(line, "$ ") || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#!") {
+			codeLines++
+		}
+	}
+	return lines >= 3 && codeLines*2 >= lines
+}
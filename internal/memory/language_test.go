@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	cases := map[string]struct {
+		text string
+		want string
+	}{
+		"english": {
+			text: "The cache is invalidated when the config file changes, so restart is not needed.",
+			want: "english",
+		},
+		"turkish": {
+			text: "Bu hata için önbelleği temizlemek gerekiyor, ama yeniden başlatmak değil. Yapılandırma dosyası ile ilgili bir sorun.",
+			want: "turkish",
+		},
+		"german": {
+			text: "Der Cache wird nicht automatisch geleert, wenn sich die Konfiguration ändert. Das ist auch bei Neustarts so.",
+			want: "german",
+		},
+		"russian": {
+			text: "Кэш не очищается автоматически при изменении конфигурации.",
+			want: "russian",
+		},
+		"code": {
+			text: "func (s *Server) Close() error {\n\ts.mu.Lock()\n\tdefer s.mu.Unlock()\n\treturn s.ln.Close()\n}",
+			want: LanguageSimple,
+		},
+		"shell": {
+			text: "$ docker compose up -d\n$ go test ./...\n$ make migrate",
+			want: LanguageSimple,
+		},
+		"too little to tell": {
+			text: "Redis timeout",
+			want: "english",
+		},
+	}
+	for name, tc := range cases {
+		if got := detectLanguage(tc.text, "english"); got != tc.want {
+			t.Errorf("%s: detectLanguage = %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestDetectLanguage_ResultsAreSupported(t *testing.T) {
+	for _, text := range []string{"Ελληνικό κείμενο για δοκιμή", "نص عربي للتجربة", "x := 1;", "plain words"} {
+		if lang := detectLanguage(text, "english"); !ValidLanguage(lang) {
+			t.Errorf("detectLanguage(%q) = %q, not a supported language", text, lang)
+		}
+	}
+}
+
+func TestParseFilter_Language(t *testing.T) {
+	f, err := ParseFilter("lang:turkish,simple", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conds, _ := f.Compile(1)
+	if len(conds) != 1 || conds[0] != "m.language::text = ANY($1::text[])" {
+		t.Fatalf("unexpected conditions: %q", conds)
+	}
+}
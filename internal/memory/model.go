@@ -54,25 +54,29 @@ const (
 )
 
 type Memory struct {
-	ID          uuid.UUID        `json:"id"`
-	Title       string           `json:"title"`
-	Content     string           `json:"content"`
-	Summary     *string          `json:"summary,omitempty"`
-	Embedding   *pgvector.Vector `json:"-"`
-	Type        MemoryType       `json:"type"`
-	Scope       MemoryScope      `json:"scope"`
-	ProjectID   *string          `json:"project_id,omitempty"`
-	AgentSource *string          `json:"agent_source,omitempty"`
-	Tags        []string         `json:"tags"`
-	Importance  float32          `json:"importance"`
-	TTLSeconds  *int             `json:"ttl_seconds,omitempty"`
-	AccessCount int              `json:"access_count"`
-	CreatedAt   time.Time        `json:"created_at"`
-	UpdatedAt   time.Time        `json:"updated_at"`
-	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
-	Version     int              `json:"version"`
-	MergedFrom  []uuid.UUID      `json:"merged_from,omitempty"`
-	ReplacedBy  *uuid.UUID       `json:"replaced_by,omitempty"`
+	ID             uuid.UUID        `json:"id"`
+	Title          string           `json:"title"`
+	Content        string           `json:"content"`
+	Summary        *string          `json:"summary,omitempty"`
+	Embedding      *pgvector.Vector `json:"-"`
+	EmbeddingModel *string          `json:"embedding_model,omitempty"`
+	Type           MemoryType       `json:"type"`
+	Scope          MemoryScope      `json:"scope"`
+	ProjectID      *string          `json:"project_id,omitempty"`
+	AgentSource    *string          `json:"agent_source,omitempty"`
+	// Language is the text search configuration keyword search uses for
+	// the memory, e.g. "english", or "simple" for code.
+	Language    string      `json:"language,omitempty"`
+	Tags        []string    `json:"tags"`
+	Importance  float32     `json:"importance"`
+	TTLSeconds  *int        `json:"ttl_seconds,omitempty"`
+	AccessCount int         `json:"access_count"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	Version     int         `json:"version"`
+	MergedFrom  []uuid.UUID `json:"merged_from,omitempty"`
+	ReplacedBy  *uuid.UUID  `json:"replaced_by,omitempty"`
 }
 
 type Relationship struct {
@@ -96,6 +100,13 @@ type StoreRequest struct {
 	Tags        []string    `json:"tags"`
 	Importance  float32     `json:"importance"`
 	TTLSeconds  *int        `json:"ttl_seconds,omitempty"`
+	// Language is a text search configuration (see Languages); empty
+	// detects it from the title and content.
+	Language string `json:"language,omitempty"`
+
+	// imported is set by Import: Store then skips telemetry and, unless the
+	// memory is merged, inserts it with its archived ID and timestamps.
+	imported *Memory
 }
 
 type UpdateRequest struct {
@@ -105,6 +116,9 @@ type UpdateRequest struct {
 	Type       *MemoryType `json:"type,omitempty"`
 	Tags       []string    `json:"tags,omitempty"`
 	Importance *float32    `json:"importance,omitempty"`
+	// Language sets the text search configuration. When it is unset the
+	// memory keeps its language.
+	Language *string `json:"language,omitempty"`
 }
 
 type SearchRequest struct {
@@ -117,12 +131,43 @@ type SearchRequest struct {
 	MinImportance *float32     `json:"min_importance,omitempty"`
 	Limit         int          `json:"limit"`
 	Offset        int          `json:"offset"`
+	// Ranking selects the scoring strategy; empty uses search.ranking.
+	Ranking RankingStrategy `json:"ranking,omitempty"`
+	// Modifiers overrides search.modifiers for this request.
+	Modifiers *ScoreModifiers `json:"modifiers,omitempty"`
+	// Explain attaches a SearchExplanation to each result.
+	Explain bool `json:"explain,omitempty"`
+	// Rerank turns model reranking on or off; nil uses search.rerank.enabled.
+	Rerank *bool `json:"rerank,omitempty"`
+	// Filter is a filter expression (see ParseFilter) applied on top of the
+	// other filters.
+	Filter string `json:"filter,omitempty"`
+
+	// Cursor continues a paginated search from a previous page's NextCursor.
+	Cursor string `json:"cursor,omitempty"`
+	// Paginate asks for a SearchPage with a NextCursor instead of a bare
+	// result list. Implied by Cursor.
+	Paginate bool `json:"paginate,omitempty"`
+	// IncludeTotal adds a TotalEstimate, a lower bound, to a SearchPage.
+	IncludeTotal bool `json:"include_total,omitempty"`
+
+	filter  *Filter      // parsed Filter, set by Service.Search
+	symbols *symbolQuery // set by Service.Search for a "symbol:" query
 }
 
 type SearchResult struct {
 	Memory    Memory  `json:"memory"`
 	Score     float64 `json:"score"`
 	MatchType string  `json:"match_type"` // "semantic", "keyword", "hybrid"
+	// Factors is set when score modifiers are active.
+	Factors *ScoreFactors `json:"score_factors,omitempty"`
+	// Explanation is set when the request asked to explain results.
+	Explanation *SearchExplanation `json:"explanation,omitempty"`
+	// RerankScore is the reranking model's relevance for results it rescored.
+	RerankScore *float64 `json:"rerank_score,omitempty"`
+	// Chunk is the passage of a long memory that matched the query, when it
+	// matched better than the memory as a whole.
+	Chunk *ChunkMatch `json:"chunk,omitempty"`
 }
 
 type TelemetryEventType string
@@ -147,6 +192,7 @@ type TelemetryEvent struct {
 	HitCount    *int               `json:"hit_count,omitempty"`
 	LatencyMs   *int               `json:"latency_ms,omitempty"`
 	Metadata    map[string]any     `json:"metadata,omitempty"`
+	WorkspaceID string             `json:"workspace_id,omitempty"`
 	CreatedAt   time.Time          `json:"created_at"`
 }
 
@@ -158,6 +204,29 @@ type RelationshipRequest struct {
 	Context      *string   `json:"context,omitempty"`
 }
 
+// RelationshipUpdate changes an existing relationship. Nil fields are left
+// as they are; an inverse type name swaps the two ends.
+type RelationshipUpdate struct {
+	Relationship *string  `json:"relationship,omitempty"`
+	Strength     *float32 `json:"strength,omitempty"`
+	Context      *string  `json:"context,omitempty"`
+}
+
+// RelationshipFilter selects relationships for listing. ProjectID and
+// MemoryID match either end.
+type RelationshipFilter struct {
+	ProjectID *string    `json:"project_id,omitempty"`
+	MemoryID  *uuid.UUID `json:"memory_id,omitempty"`
+	Types     []string   `json:"types,omitempty"`
+	Limit     int        `json:"limit"`
+	Offset    int        `json:"offset"`
+}
+
+type RelationshipList struct {
+	Relationships []Relationship `json:"relationships"`
+	Total         int            `json:"total"`
+}
+
 type Stats struct {
 	TotalMemories      int            `json:"total_memories"`
 	ByType             map[string]int `json:"by_type"`
@@ -167,6 +236,7 @@ type Stats struct {
 	ShortTermCount     int            `json:"short_term_count"`
 	ExpiringCount      int            `json:"expiring_count"`
 	PendingSuggestions int            `json:"pending_suggestions"`
+	TrashedCount       int            `json:"trashed_count"`
 }
 
 type AnalyticsData struct {
@@ -254,6 +324,8 @@ type ConsolidationLog struct {
 	ContentAfter    string      `json:"content_after"`
 	PerformedBy     string      `json:"performed_by"`
 	CreatedAt       time.Time   `json:"created_at"`
+	RevertedAt      *time.Time  `json:"reverted_at,omitempty"`
+	RevertsID       *uuid.UUID  `json:"reverts_id,omitempty"` // set on "revert" entries
 }
 
 // ConsolidationSuggestion represents a pair of memories that may be duplicates.
@@ -293,3 +365,124 @@ type BatchMergeOp struct {
 type SuggestionStatusUpdate struct {
 	Status string `json:"status"` // "accepted", "dismissed"
 }
+
+// EmbeddingState identifies the model whose vectors currently serve search.
+type EmbeddingState struct {
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	Dimensions int       `json:"dimensions"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// EmbeddingMigration tracks a background re-embed from one model to another.
+type EmbeddingMigration struct {
+	ID               uuid.UUID  `json:"id"`
+	SourceModel      string     `json:"source_model"`
+	SourceDimensions int        `json:"source_dimensions"`
+	TargetProvider   string     `json:"target_provider"`
+	TargetModel      string     `json:"target_model"`
+	TargetDimensions int        `json:"target_dimensions"`
+	Status           string     `json:"status"` // "running", "paused", "completed", "failed", "cancelled"
+	Total            int        `json:"total"`
+	Processed        int        `json:"processed"`
+	Failed           int        `json:"failed"`
+	CursorID         *uuid.UUID `json:"cursor_id,omitempty"`
+	LastError        *string    `json:"last_error,omitempty"`
+	StartedAt        time.Time  `json:"started_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+}
+
+// ReembedStatus is the progress view returned by the API and CLI.
+type ReembedStatus struct {
+	Active      EmbeddingState      `json:"active"`
+	Configured  EmbeddingState      `json:"configured"`
+	InSync      bool                `json:"in_sync"`
+	Total       int                 `json:"total"`
+	Covered     int                 `json:"covered"`
+	Pending     int                 `json:"pending"`
+	CoveragePct float64             `json:"coverage_pct"`
+	Migration   *EmbeddingMigration `json:"migration,omitempty"`
+	AutoStart   bool                `json:"auto_start"`
+}
+
+// ReembedItem is one memory's embedding input for a re-embed batch.
+type ReembedItem struct {
+	ID      uuid.UUID
+	Title   string
+	Content string
+}
+
+// MemoryVersion is a snapshot of a memory's content after one mutation.
+type MemoryVersion struct {
+	ID           uuid.UUID   `json:"id"`
+	MemoryID     uuid.UUID   `json:"memory_id"`
+	VersionNo    int         `json:"version_no"`
+	Title        string      `json:"title"`
+	Content      string      `json:"content"`
+	Summary      *string     `json:"summary,omitempty"`
+	Type         MemoryType  `json:"type"`
+	Scope        MemoryScope `json:"scope"`
+	ProjectID    *string     `json:"project_id,omitempty"`
+	Tags         []string    `json:"tags"`
+	Importance   float32     `json:"importance"`
+	ChangeReason string      `json:"change_reason"` // "create", "update", "merge", "restore:vN", "import", "baseline"
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// VersionDiff is a unified diff between two versions of a memory.
+type VersionDiff struct {
+	MemoryID    uuid.UUID `json:"memory_id"`
+	FromVersion int       `json:"from_version"`
+	ToVersion   int       `json:"to_version"`
+	Diff        string    `json:"diff"`
+}
+
+// GraphDirection selects which relationships a traversal follows out of a
+// memory: its from_memory_id side (outgoing), to_memory_id side (incoming)
+// or both.
+type GraphDirection string
+
+const (
+	DirectionOutgoing GraphDirection = "outgoing"
+	DirectionIncoming GraphDirection = "incoming"
+	DirectionBoth     GraphDirection = "both"
+)
+
+// ValidDirections contains the accepted values for GraphRequest.Direction.
+var ValidDirections = map[GraphDirection]bool{
+	DirectionOutgoing: true, DirectionIncoming: true, DirectionBoth: true,
+}
+
+// GraphRequest bounds a traversal over memory_relationships.
+type GraphRequest struct {
+	Depth             int            `json:"depth,omitempty"` // max hops
+	RelationshipTypes []string       `json:"relationship_types,omitempty"`
+	Direction         GraphDirection `json:"direction,omitempty"`
+	MinStrength       float32        `json:"min_strength,omitempty"`
+	Limit             int            `json:"limit,omitempty"` // max nodes
+}
+
+// GraphNode is a memory reached by a traversal, Depth hops from the root.
+type GraphNode struct {
+	Memory Memory `json:"memory"`
+	Depth  int    `json:"depth"`
+}
+
+// Graph is the subgraph around Root: the memories reachable within the
+// request's bounds and every relationship between them that passes its
+// filters. Truncated is set when the node limit cut the traversal short.
+type Graph struct {
+	Root      uuid.UUID      `json:"root"`
+	Nodes     []GraphNode    `json:"nodes"`
+	Edges     []Relationship `json:"edges"`
+	Truncated bool           `json:"truncated"`
+}
+
+// GraphPath is a shortest path between two memories; Edges[i] links
+// Nodes[i] and Nodes[i+1].
+type GraphPath struct {
+	Nodes []Memory       `json:"nodes"`
+	Edges []Relationship `json:"edges"`
+	Hops  int            `json:"hops"`
+}
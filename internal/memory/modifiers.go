@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+// ScoreModifiers overrides the configured search.modifiers for one request.
+// Nil fields keep the configured value; the half-life is given in days.
+type ScoreModifiers struct {
+	RecencyHalfLifeDays *float64 `json:"recency_half_life_days,omitempty"`
+	RecencyWeight       *float64 `json:"recency_weight,omitempty"`
+	ImportanceWeight    *float64 `json:"importance_weight,omitempty"`
+	AccessWeight        *float64 `json:"access_weight,omitempty"`
+}
+
+// ScoreFactors explains how modifiers changed a result's score:
+// Score = BaseScore * Recency * Importance * Access.
+type ScoreFactors struct {
+	BaseScore  float64 `json:"base_score"`
+	Recency    float64 `json:"recency"`
+	Importance float64 `json:"importance"`
+	Access     float64 `json:"access"`
+}
+
+// scoreModifiers is the resolved modifier set for a search.
+type scoreModifiers struct {
+	halfLife         time.Duration
+	recencyWeight    float64
+	importanceWeight float64
+	accessWeight     float64
+}
+
+// resolveScoreModifiers applies a request's overrides to the configured
+// modifiers and validates the result.
+func resolveScoreModifiers(cfg config.ScoreModifiers, req *ScoreModifiers) (scoreModifiers, error) {
+	m := scoreModifiers{
+		halfLife:         cfg.RecencyHalfLife,
+		recencyWeight:    cfg.RecencyWeight,
+		importanceWeight: cfg.ImportanceWeight,
+		accessWeight:     cfg.AccessWeight,
+	}
+	if req != nil {
+		if req.RecencyHalfLifeDays != nil {
+			if *req.RecencyHalfLifeDays < 0 {
+				return m, fmt.Errorf("%w: recency_half_life_days must be >= 0", ErrInvalidSearch)
+			}
+			m.halfLife = time.Duration(*req.RecencyHalfLifeDays * float64(24*time.Hour))
+		}
+		if req.RecencyWeight != nil {
+			if *req.RecencyWeight < 0 || *req.RecencyWeight > 1 {
+				return m, fmt.Errorf("%w: recency_weight must be between 0 and 1", ErrInvalidSearch)
+			}
+			m.recencyWeight = *req.RecencyWeight
+		}
+		if req.ImportanceWeight != nil {
+			if *req.ImportanceWeight < 0 {
+				return m, fmt.Errorf("%w: importance_weight must be >= 0", ErrInvalidSearch)
+			}
+			m.importanceWeight = *req.ImportanceWeight
+		}
+		if req.AccessWeight != nil {
+			if *req.AccessWeight < 0 {
+				return m, fmt.Errorf("%w: access_weight must be >= 0", ErrInvalidSearch)
+			}
+			m.accessWeight = *req.AccessWeight
+		}
+	}
+	return m, nil
+}
+
+// enabled reports whether any modifier can change a score.
+func (m scoreModifiers) enabled() bool {
+	return (m.halfLife > 0 && m.recencyWeight > 0) || m.importanceWeight > 0 || m.accessWeight > 0
+}
+
+// factors returns the multipliers for mem as of now.
+func (m scoreModifiers) factors(mem *Memory, base float64, now time.Time) ScoreFactors {
+	f := ScoreFactors{BaseScore: base, Recency: 1, Importance: 1, Access: 1}
+	if m.halfLife > 0 && m.recencyWeight > 0 {
+		age := now.Sub(mem.UpdatedAt)
+		if age < 0 {
+			age = 0
+		}
+		decay := math.Pow(0.5, float64(age)/float64(m.halfLife))
+		f.Recency = 1 - m.recencyWeight + m.recencyWeight*decay
+	}
+	if m.importanceWeight > 0 {
+		f.Importance = 1 + m.importanceWeight*float64(mem.Importance)
+	}
+	if m.accessWeight > 0 {
+		f.Access = 1 + m.accessWeight*math.Log1p(float64(mem.AccessCount))
+	}
+	return f
+}
+
+func (f ScoreFactors) score() float64 {
+	return f.BaseScore * f.Recency * f.Importance * f.Access
+}
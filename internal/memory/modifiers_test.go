@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+func TestResolveScoreModifiers(t *testing.T) {
+	cfg := config.ScoreModifiers{RecencyHalfLife: 24 * time.Hour, RecencyWeight: 0.5, ImportanceWeight: 1}
+
+	m, err := resolveScoreModifiers(cfg, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.halfLife != 24*time.Hour || m.recencyWeight != 0.5 || m.importanceWeight != 1 || m.accessWeight != 0 {
+		t.Fatalf("config not applied: %+v", m)
+	}
+
+	days, zero := 7.0, 0.0
+	m, err = resolveScoreModifiers(cfg, &ScoreModifiers{RecencyHalfLifeDays: &days, ImportanceWeight: &zero})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.halfLife != 7*24*time.Hour || m.importanceWeight != 0 || m.recencyWeight != 0.5 {
+		t.Fatalf("overrides not applied: %+v", m)
+	}
+
+	bad := 1.5
+	if _, err := resolveScoreModifiers(cfg, &ScoreModifiers{RecencyWeight: &bad}); !errors.Is(err, ErrInvalidSearch) {
+		t.Fatalf("expected ErrInvalidSearch, got %v", err)
+	}
+	neg := -1.0
+	if _, err := resolveScoreModifiers(cfg, &ScoreModifiers{AccessWeight: &neg}); !errors.Is(err, ErrInvalidSearch) {
+		t.Fatalf("expected ErrInvalidSearch, got %v", err)
+	}
+}
+
+func TestScoreModifierFactors(t *testing.T) {
+	now := time.Now()
+	m := scoreModifiers{halfLife: 10 * 24 * time.Hour, recencyWeight: 0.5, importanceWeight: 1, accessWeight: 0.5}
+	mem := &Memory{UpdatedAt: now.Add(-10 * 24 * time.Hour), Importance: 0.5, AccessCount: 3}
+
+	f := m.factors(mem, 2, now)
+	// One half-life: half of the score decays by half.
+	if math.Abs(f.Recency-0.75) > 1e-9 {
+		t.Errorf("recency = %v, want 0.75", f.Recency)
+	}
+	if math.Abs(f.Importance-1.5) > 1e-9 {
+		t.Errorf("importance = %v, want 1.5", f.Importance)
+	}
+	if math.Abs(f.Access-(1+0.5*math.Log(4))) > 1e-9 {
+		t.Errorf("access = %v, want %v", f.Access, 1+0.5*math.Log(4))
+	}
+	if math.Abs(f.score()-2*f.Recency*f.Importance*f.Access) > 1e-9 {
+		t.Errorf("score = %v", f.score())
+	}
+
+	if (scoreModifiers{halfLife: time.Hour}).enabled() {
+		t.Errorf("a half-life without a recency weight should not enable modifiers")
+	}
+}
+
+func TestScoreCandidates_ModifiersReorder(t *testing.T) {
+	now := time.Now()
+	cands := []SearchCandidate{
+		{Memory: Memory{ID: uuid.New(), Title: "stale", UpdatedAt: now.Add(-90 * 24 * time.Hour), Importance: 0.2}, VectorScore: 0.80, VectorRank: 1},
+		{Memory: Memory{ID: uuid.New(), Title: "fresh", UpdatedAt: now, Importance: 0.9, AccessCount: 6}, VectorScore: 0.75, VectorRank: 2},
+	}
+	ranker, _ := NewRanker(RankingLinear, RankingWeights{Vector: 1})
+
+	plain := scoreCandidates(ranker, scoreModifiers{}, cands, now, false)
+	if plain[0].Memory.Title != "stale" || plain[0].Factors != nil {
+		t.Fatalf("without modifiers the stronger match should lead unexplained: %+v", plain[0])
+	}
+
+	mods := scoreModifiers{halfLife: 30 * 24 * time.Hour, recencyWeight: 0.3, importanceWeight: 0.5, accessWeight: 0.1}
+	boosted := scoreCandidates(ranker, mods, cands, now, false)
+	if boosted[0].Memory.Title != "fresh" {
+		t.Fatalf("expected the recent, important memory first, got %q", boosted[0].Memory.Title)
+	}
+	if boosted[0].Factors == nil || boosted[0].Factors.BaseScore != 0.75 {
+		t.Fatalf("expected score factors on results: %+v", boosted[0].Factors)
+	}
+}
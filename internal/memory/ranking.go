@@ -0,0 +1,252 @@
+package memory
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RankingStrategy selects how HybridSearch candidates are scored.
+type RankingStrategy string
+
+const (
+	// RankingLinear adds the weighted vector and keyword scores.
+	RankingLinear RankingStrategy = "linear"
+	// RankingRRF fuses the vector and keyword rank positions (reciprocal rank
+	// fusion), which is insensitive to the scale of either score.
+	RankingRRF RankingStrategy = "rrf"
+	// RankingMax takes the larger of the vector score and the keyword score
+	// normalized to the best keyword hit.
+	RankingMax RankingStrategy = "max"
+)
+
+// DefaultRRFK is the rank constant of reciprocal rank fusion. Larger values
+// flatten the difference between the top ranks.
+const DefaultRRFK = 60
+
+// SearchCandidate is a memory matched by the hybrid search query with the raw
+// signals a Ranker scores. VectorRank, KeywordRank and SymbolRank are 1-based
+// positions among all memories matching the filters; KeywordRank is 0 when
+// the memory does not match the query text, and SymbolRank when it contains
+// none of the query's symbols.
+type SearchCandidate struct {
+	Memory       Memory
+	VectorScore  float64
+	KeywordScore float64
+	VectorRank   int
+	KeywordRank  int
+	// SymbolScore is the share of the query's symbols (identifiers and file
+	// paths) that the memory contains, from 0 to 1.
+	SymbolScore float64
+	SymbolRank  int
+	// MatchedSymbols are the memory's symbols that matched the query.
+	MatchedSymbols []string
+	// Boost is the bonus for matching the request's project and tags,
+	// ProjectBoost + TagBoost.
+	Boost        float64
+	ProjectBoost float64
+	TagBoost     float64
+	// Chunk is the best-matching chunk when it scored above the whole
+	// memory; VectorScore is then the chunk's similarity.
+	Chunk *ChunkMatch
+}
+
+// Ranker scores a candidate set. It receives the whole set so that
+// strategies can normalize across it, and returns one score per candidate.
+type Ranker interface {
+	Strategy() RankingStrategy
+	Score(candidates []SearchCandidate) []float64
+}
+
+// RankingWeights are the weights given to the vector, keyword and symbol
+// signals.
+type RankingWeights struct {
+	Vector  float64
+	Keyword float64
+	Symbol  float64
+	RRFK    int
+}
+
+// ParseRankingStrategy validates name. An empty name returns "" so the
+// caller can fall back to its default.
+func ParseRankingStrategy(name string) (RankingStrategy, error) {
+	s := RankingStrategy(strings.ToLower(strings.TrimSpace(name)))
+	switch s {
+	case "", RankingLinear, RankingRRF, RankingMax:
+		return s, nil
+	}
+	return "", fmt.Errorf("%w: unknown ranking strategy %q (want linear, rrf or max)", ErrInvalidSearch, name)
+}
+
+// NewRanker returns the Ranker for strategy.
+func NewRanker(strategy RankingStrategy, w RankingWeights) (Ranker, error) {
+	switch strategy {
+	case RankingLinear, "":
+		return linearRanker{w}, nil
+	case RankingRRF:
+		if w.RRFK <= 0 {
+			w.RRFK = DefaultRRFK
+		}
+		return rrfRanker{w}, nil
+	case RankingMax:
+		return maxRanker{}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown ranking strategy %q", ErrInvalidSearch, strategy)
+}
+
+type linearRanker struct{ w RankingWeights }
+
+func (linearRanker) Strategy() RankingStrategy { return RankingLinear }
+
+func (r linearRanker) Score(candidates []SearchCandidate) []float64 {
+	scores := make([]float64, len(candidates))
+	for i, c := range candidates {
+		scores[i] = c.VectorScore*r.w.Vector + c.KeywordScore*r.w.Keyword + c.SymbolScore*r.w.Symbol + c.Boost
+	}
+	return scores
+}
+
+// rrfRanker sums weight/(k+rank) over the signals a candidate ranks in. The
+// fused score is on a much smaller scale than Boost, so the boost scales it
+// rather than being added.
+type rrfRanker struct{ w RankingWeights }
+
+func (rrfRanker) Strategy() RankingStrategy { return RankingRRF }
+
+func (r rrfRanker) Score(candidates []SearchCandidate) []float64 {
+	k := float64(r.w.RRFK)
+	scores := make([]float64, len(candidates))
+	for i, c := range candidates {
+		var s float64
+		if c.VectorRank > 0 {
+			s += r.w.Vector / (k + float64(c.VectorRank))
+		}
+		if c.KeywordRank > 0 {
+			s += r.w.Keyword / (k + float64(c.KeywordRank))
+		}
+		if c.SymbolRank > 0 {
+			s += r.w.Symbol / (k + float64(c.SymbolRank))
+		}
+		scores[i] = s * (1 + c.Boost)
+	}
+	return scores
+}
+
+// maxRanker scores each candidate by its strongest signal. ts_rank values are
+// far below cosine similarities, so keyword scores are divided by the best
+// keyword score in the set first. Symbol scores are already in [0, 1].
+type maxRanker struct{}
+
+func (maxRanker) Strategy() RankingStrategy { return RankingMax }
+
+func (maxRanker) Score(candidates []SearchCandidate) []float64 {
+	var best float64
+	for _, c := range candidates {
+		if c.KeywordScore > best {
+			best = c.KeywordScore
+		}
+	}
+	scores := make([]float64, len(candidates))
+	for i, c := range candidates {
+		s := c.VectorScore
+		if best > 0 && c.KeywordScore/best > s {
+			s = c.KeywordScore / best
+		}
+		if c.SymbolScore > s {
+			s = c.SymbolScore
+		}
+		scores[i] = s + c.Boost
+	}
+	return scores
+}
+
+// scoreCandidates scores candidates with r, applies the score modifiers as of
+// now and returns them as results, best first. Ties keep the most recently
+// updated memory first, then order by id. With explain set, each result
+// carries the candidate's signals.
+func scoreCandidates(r Ranker, mods scoreModifiers, candidates []SearchCandidate, now time.Time, explain bool) []SearchResult {
+	scores := r.Score(candidates)
+	results := make([]SearchResult, len(candidates))
+	for i, c := range candidates {
+		results[i] = SearchResult{Memory: c.Memory, Score: scores[i], MatchType: "hybrid", Chunk: c.Chunk}
+		if mods.enabled() {
+			f := mods.factors(&c.Memory, scores[i], now)
+			results[i].Score = f.score()
+			results[i].Factors = &f
+		}
+		if explain {
+			results[i].Explanation = explainCandidate(r.Strategy(), c, scores[i])
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Memory.UpdatedAt.Equal(results[j].Memory.UpdatedAt) {
+			return results[i].Memory.UpdatedAt.After(results[j].Memory.UpdatedAt)
+		}
+		return bytes.Compare(results[i].Memory.ID[:], results[j].Memory.ID[:]) < 0
+	})
+	return results
+}
+
+// pageResults returns the page of results selected by offset and limit.
+func pageResults(results []SearchResult, limit, offset int) []SearchResult {
+	if offset >= len(results) {
+		return []SearchResult{}
+	}
+	results = results[offset:]
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// SearchExplanation shows why a search result scored as it did.
+type SearchExplanation struct {
+	Strategy     RankingStrategy `json:"strategy"`
+	RankScore    float64         `json:"rank_score"`
+	VectorScore  float64         `json:"vector_score"`
+	VectorRank   int             `json:"vector_rank"`
+	KeywordScore float64         `json:"keyword_score"`
+	// KeywordRank is 0 when the memory does not match the query text.
+	KeywordRank int     `json:"keyword_rank"`
+	SymbolScore float64 `json:"symbol_score"`
+	// SymbolRank is 0 when the memory contains none of the query's symbols.
+	SymbolRank int `json:"symbol_rank"`
+	// MatchedSymbols are the memory's identifiers and paths that matched.
+	MatchedSymbols []string           `json:"matched_symbols,omitempty"`
+	Boosts         map[string]float64 `json:"boosts,omitempty"`
+	// MatchedLexemes are the normalized query terms found in the memory.
+	MatchedLexemes []string `json:"matched_lexemes"`
+	// Highlights are content snippets with matches wrapped in <mark>.
+	Highlights []string `json:"highlights"`
+}
+
+func explainCandidate(strategy RankingStrategy, c SearchCandidate, rankScore float64) *SearchExplanation {
+	e := &SearchExplanation{
+		Strategy:       strategy,
+		RankScore:      rankScore,
+		VectorScore:    c.VectorScore,
+		VectorRank:     c.VectorRank,
+		KeywordScore:   c.KeywordScore,
+		KeywordRank:    c.KeywordRank,
+		SymbolScore:    c.SymbolScore,
+		SymbolRank:     c.SymbolRank,
+		MatchedSymbols: c.MatchedSymbols,
+		MatchedLexemes: []string{},
+		Highlights:     []string{},
+	}
+	if c.ProjectBoost != 0 || c.TagBoost != 0 {
+		e.Boosts = map[string]float64{}
+		if c.ProjectBoost != 0 {
+			e.Boosts["project"] = c.ProjectBoost
+		}
+		if c.TagBoost != 0 {
+			e.Boosts["tags"] = c.TagBoost
+		}
+	}
+	return e
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseRankingStrategy(t *testing.T) {
+	cases := map[string]RankingStrategy{
+		"":        "",
+		"linear":  RankingLinear,
+		" RRF ":   RankingRRF,
+		"Max":     RankingMax,
+		"rrf\t\n": RankingRRF,
+	}
+	for in, want := range cases {
+		got, err := ParseRankingStrategy(in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+
+	if _, err := ParseRankingStrategy("bm25"); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("expected ErrInvalidSearch, got %v", err)
+	}
+}
+
+// keywordHeavyCandidates has a memory that only matches semantically and one
+// that is the best keyword hit but semantically weaker.
+func keywordHeavyCandidates() []SearchCandidate {
+	now := time.Now()
+	return []SearchCandidate{
+		{Memory: Memory{ID: uuid.New(), Title: "semantic", UpdatedAt: now}, VectorScore: 0.80, VectorRank: 1},
+		{Memory: Memory{ID: uuid.New(), Title: "keyword", UpdatedAt: now}, VectorScore: 0.70, KeywordScore: 0.09, VectorRank: 2, KeywordRank: 1},
+		{Memory: Memory{ID: uuid.New(), Title: "weak", UpdatedAt: now}, VectorScore: 0.40, KeywordScore: 0.01, VectorRank: 3, KeywordRank: 2},
+	}
+}
+
+func TestScoreCandidates_Strategies(t *testing.T) {
+	w := RankingWeights{Vector: 0.7, Keyword: 0.3, RRFK: DefaultRRFK}
+	cases := []struct {
+		strategy RankingStrategy
+		top      string
+	}{
+		// ts_rank is too small to overcome the vector gap linearly.
+		{RankingLinear, "semantic"},
+		// Ranking first in both lists beats ranking first in one.
+		{RankingRRF, "keyword"},
+		// The best keyword hit normalizes to 1.
+		{RankingMax, "keyword"},
+	}
+	for _, c := range cases {
+		ranker, err := NewRanker(c.strategy, w)
+		if err != nil {
+			t.Fatalf("%s: %v", c.strategy, err)
+		}
+		if ranker.Strategy() != c.strategy {
+			t.Errorf("%s: ranker reports %s", c.strategy, ranker.Strategy())
+		}
+		results := scoreCandidates(ranker, scoreModifiers{}, keywordHeavyCandidates(), time.Now(), false)
+		if len(results) != 3 {
+			t.Fatalf("%s: got %d results, want 3", c.strategy, len(results))
+		}
+		if results[0].Memory.Title != c.top {
+			t.Errorf("%s: top result %q, want %q", c.strategy, results[0].Memory.Title, c.top)
+		}
+	}
+}
+
+func TestScoreCandidates_Paginates(t *testing.T) {
+	ranker, _ := NewRanker(RankingLinear, RankingWeights{Vector: 1})
+	cands := keywordHeavyCandidates()
+
+	page := pageResults(scoreCandidates(ranker, scoreModifiers{}, cands, time.Now(), false), 1, 1)
+	if len(page) != 1 || page[0].Memory.Title != "keyword" {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+	if page := pageResults(scoreCandidates(ranker, scoreModifiers{}, cands, time.Now(), false), 10, 5); len(page) != 0 {
+		t.Fatalf("expected empty page past the end, got %d results", len(page))
+	}
+}
+
+func TestRRFRanker_BoostScales(t *testing.T) {
+	ranker, _ := NewRanker(RankingRRF, RankingWeights{Vector: 1, Keyword: 1})
+	cands := []SearchCandidate{
+		{VectorRank: 1},
+		{VectorRank: 1, Boost: 0.05},
+	}
+	scores := ranker.Score(cands)
+	if scores[1] <= scores[0] {
+		t.Fatalf("boosted candidate should score higher: %v", scores)
+	}
+	if scores[1] > scores[0]*1.06 {
+		t.Fatalf("boost should scale the fused score, not add to it: %v", scores)
+	}
+}
+
+func TestScoreCandidates_Explain(t *testing.T) {
+	ranker, _ := NewRanker(RankingRRF, RankingWeights{Vector: 0.7, Keyword: 0.3})
+	cands := keywordHeavyCandidates()
+	cands[1].ProjectBoost, cands[1].TagBoost = 0.05, 0.03
+	cands[1].Boost = 0.08
+
+	results := scoreCandidates(ranker, scoreModifiers{}, cands, time.Now(), true)
+	top := results[0]
+	if top.Explanation == nil {
+		t.Fatalf("expected an explanation")
+	}
+	e := top.Explanation
+	if e.Strategy != RankingRRF || e.RankScore != top.Score || e.KeywordRank != 1 || e.VectorRank != 2 {
+		t.Fatalf("unexpected explanation: %+v", e)
+	}
+	if e.Boosts["project"] != 0.05 || e.Boosts["tags"] != 0.03 {
+		t.Fatalf("unexpected boosts: %v", e.Boosts)
+	}
+	if results[1].Explanation.Boosts != nil {
+		t.Fatalf("unboosted result should have no boosts: %v", results[1].Explanation.Boosts)
+	}
+
+	for _, r := range scoreCandidates(ranker, scoreModifiers{}, cands, time.Now(), false) {
+		if r.Explanation != nil {
+			t.Fatalf("explanation without explain")
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+
+	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/embedding"
+)
+
+// Re-embedding keeps search on the model that produced the live vectors while
+// a background job writes the configured model's vectors into a shadow
+// column. Once every memory is covered the shadow column is promoted in one
+// transaction and the service switches its query embedder over.
+
+const embeddingRepairBackoff = 5 * time.Minute
+
+// EmbedderFactory builds an embedder for a stored provider/model/dimensions
+// triple so the previously active model can keep serving queries during a
+// re-embed.
+type EmbedderFactory func(provider, model string, dimensions int) (embedding.Embedder, error)
+
+// embed returns the active embedder's vector for text together with the
+// model name to record next to it.
+func (s *Service) embed(ctx context.Context, text string) (pgvector.Vector, string, error) {
+	e := s.activeEmbedder()
+	vec, err := e.Embed(ctx, text)
+	if err != nil {
+		return pgvector.Vector{}, "", err
+	}
+	return pgvector.NewVector(vec), e.Model(), nil
+}
+
+func (s *Service) activeEmbedder() embedding.Embedder {
+	s.embMu.RLock()
+	defer s.embMu.RUnlock()
+	return s.embedder
+}
+
+func (s *Service) setActiveEmbedder(e embedding.Embedder) {
+	s.embMu.Lock()
+	s.embedder = e
+	s.embMu.Unlock()
+}
+
+func (s *Service) configuredState() EmbeddingState {
+	return EmbeddingState{Provider: s.targetProvider, Model: s.target.Model(), Dimensions: s.target.Dimensions()}
+}
+
+// InitEmbeddings reconciles the configured embedder with the model recorded
+// in the database. If they differ, the recorded model keeps serving search
+// and, when auto-start is enabled, a migration to the configured model begins.
+func (s *Service) InitEmbeddings(ctx context.Context, provider string, cfg config.ReembedConfig, factory EmbedderFactory) error {
+	s.targetProvider = provider
+	s.reembedCfg = cfg
+
+	state, err := s.repo.GetEmbeddingState(ctx)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		// First boot with model tracking: existing vectors came from the configured model.
+		return s.repo.InitEmbeddingState(ctx, provider, s.target.Model(), s.target.Dimensions())
+	}
+
+	if active, err := s.repo.GetActiveEmbeddingMigration(ctx); err != nil {
+		return err
+	} else if active != nil && (active.TargetModel != s.target.Model() || active.TargetDimensions != s.target.Dimensions()) {
+		msg := fmt.Sprintf("configured embedding model changed to %s", s.target.Model())
+		if err := s.repo.SetEmbeddingMigrationStatus(ctx, active.ID, "cancelled", &msg); err != nil {
+			return err
+		}
+		slog.Warn("cancelled embedding migration", "id", active.ID, "target_model", active.TargetModel, "reason", msg)
+	}
+
+	if state.Model == s.target.Model() && state.Dimensions == s.target.Dimensions() {
+		return nil
+	}
+
+	prev, err := factory(state.Provider, state.Model, state.Dimensions)
+	if err != nil {
+		return fmt.Errorf("build embedder for active model %s: %w", state.Model, err)
+	}
+	s.setActiveEmbedder(prev)
+	slog.Warn("stored embeddings use a different model; search stays on it until re-embedding completes",
+		"active_model", state.Model, "active_dimensions", state.Dimensions,
+		"configured_model", s.target.Model(), "configured_dimensions", s.target.Dimensions(),
+		"auto_start", cfg.Auto,
+	)
+
+	if !cfg.Auto {
+		return nil
+	}
+	m, err := s.StartReembed(ctx)
+	if err != nil {
+		return err
+	}
+	slog.Info("embedding migration running", "id", m.ID, "target_model", m.TargetModel, "total", m.Total)
+	return nil
+}
+
+// StartReembed starts a migration to the configured model, or resumes a
+// paused one.
+func (s *Service) StartReembed(ctx context.Context) (*EmbeddingMigration, error) {
+	active, err := s.repo.GetActiveEmbeddingMigration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		if active.Status == "running" {
+			return nil, ErrReembedInProgress
+		}
+		if err := s.repo.SetEmbeddingMigrationStatus(ctx, active.ID, "running", nil); err != nil {
+			return nil, err
+		}
+		active.Status = "running"
+		return active, nil
+	}
+
+	state, err := s.repo.GetEmbeddingState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		cur := s.configuredState()
+		state = &cur
+	}
+	if state.Model == s.target.Model() && state.Dimensions == s.target.Dimensions() {
+		return nil, ErrReembedNotNeeded
+	}
+	return s.repo.CreateEmbeddingMigration(ctx, state, s.targetProvider, s.target.Model(), s.target.Dimensions())
+}
+
+// PauseReembed pauses the running migration. Progress is kept.
+func (s *Service) PauseReembed(ctx context.Context) (*EmbeddingMigration, error) {
+	active, err := s.repo.GetActiveEmbeddingMigration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil || active.Status != "running" {
+		return nil, ErrReembedNotActive
+	}
+	if err := s.repo.SetEmbeddingMigrationStatus(ctx, active.ID, "paused", nil); err != nil {
+		return nil, err
+	}
+	active.Status = "paused"
+	return active, nil
+}
+
+// ReembedStatus reports coverage of the configured model and the current or
+// most recent migration.
+func (s *Service) ReembedStatus(ctx context.Context) (*ReembedStatus, error) {
+	configured := s.configuredState()
+	state, err := s.repo.GetEmbeddingState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = &configured
+	}
+
+	migration, err := s.repo.GetActiveEmbeddingMigration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if migration == nil {
+		if migration, err = s.repo.GetLatestEmbeddingMigration(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	total, live, shadow, err := s.repo.EmbeddingCoverage(ctx, configured.Model)
+	if err != nil {
+		return nil, err
+	}
+	covered := live + shadow
+	pct := 100.0
+	if total > 0 {
+		pct = float64(covered) / float64(total) * 100
+	}
+
+	return &ReembedStatus{
+		Active:      *state,
+		Configured:  configured,
+		InSync:      state.Model == configured.Model && state.Dimensions == configured.Dimensions && covered == total,
+		Total:       total,
+		Covered:     covered,
+		Pending:     total - covered,
+		CoveragePct: pct,
+		Migration:   migration,
+		AutoStart:   s.reembedCfg.Auto,
+	}, nil
+}
+
+// ReembedStep runs one batch of the active migration, or performs the
+// cutover once coverage is complete. With no migration running it repairs
+// live vectors that were not produced by the active model. Returns the number
+// of memories written.
+func (s *Service) ReembedStep(ctx context.Context) (int, error) {
+	m, err := s.repo.GetActiveEmbeddingMigration(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if m == nil {
+		if err := s.SyncActiveEmbedder(ctx); err != nil {
+			return 0, err
+		}
+		return s.repairEmbeddings(ctx)
+	}
+	if m.Status != "running" {
+		return 0, nil
+	}
+
+	batchSize := s.reembedCfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 32
+	}
+
+	items, err := s.repo.ListPendingReembed(ctx, m.TargetModel, m.CursorID, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		if m.CursorID != nil {
+			// End of a pass; the next one catches rows written behind the cursor.
+			return 0, s.repo.ResetEmbeddingMigrationCursor(ctx, m.ID)
+		}
+		ok, err := s.repo.CutoverEmbeddings(ctx, m)
+		if err != nil || !ok {
+			return 0, err
+		}
+		s.setActiveEmbedder(s.target)
+		s.invalidateAllSearchCaches(ctx)
+		slog.Info("embedding migration completed; search switched to new model",
+			"id", m.ID, "model", m.TargetModel, "dimensions", m.TargetDimensions)
+		return 0, nil
+	}
+
+	vectors, err := s.embedItems(ctx, s.target, items, m.TargetDimensions)
+	if err != nil {
+		msg := err.Error()
+		var dimErr *dimensionMismatchError
+		if errors.As(err, &dimErr) {
+			// Retrying cannot help; the configured dimensions are wrong.
+			_ = s.repo.SetEmbeddingMigrationStatus(ctx, m.ID, "failed", &msg)
+			return 0, err
+		}
+		_ = s.repo.RecordEmbeddingMigrationError(ctx, m.ID, msg, len(items))
+		return 0, err
+	}
+	return s.repo.StoreReembedBatch(ctx, m.ID, m.TargetModel, items, vectors)
+}
+
+// SyncActiveEmbedder switches to the configured model once another instance
+// has completed the cutover. The cutover runs on the scheduler leader only,
+// so every instance calls this on its own.
+func (s *Service) SyncActiveEmbedder(ctx context.Context) error {
+	if s.activeEmbedder().Model() == s.target.Model() {
+		return nil
+	}
+	state, err := s.repo.GetEmbeddingState(ctx)
+	if err != nil {
+		return err
+	}
+	s.adoptEmbeddingState(ctx, state)
+	return nil
+}
+
+// adoptEmbeddingState switches to the configured model when state records
+// it as the live one.
+func (s *Service) adoptEmbeddingState(ctx context.Context, state *EmbeddingState) {
+	if state == nil || state.Model != s.target.Model() || state.Dimensions != s.target.Dimensions() {
+		return
+	}
+	s.setActiveEmbedder(s.target)
+	s.invalidateAllSearchCaches(ctx)
+	slog.Info("search switched to re-embedded model", "model", state.Model)
+}
+
+// repairEmbeddings re-embeds rows whose live vector is missing or came from
+// another model, e.g. a write that raced the cutover. It backs off once a
+// sweep finds nothing.
+func (s *Service) repairEmbeddings(ctx context.Context) (int, error) {
+	active := s.activeEmbedder()
+	if active.Model() != s.target.Model() {
+		return 0, nil
+	}
+
+	s.embMu.Lock()
+	if time.Now().Before(s.nextEmbeddingRepair) {
+		s.embMu.Unlock()
+		return 0, nil
+	}
+	s.embMu.Unlock()
+
+	batchSize := s.reembedCfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 32
+	}
+	items, err := s.repo.ListStaleEmbeddings(ctx, active.Model(), batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		s.embMu.Lock()
+		s.nextEmbeddingRepair = time.Now().Add(embeddingRepairBackoff)
+		s.embMu.Unlock()
+		return 0, nil
+	}
+
+	vectors, err := s.embedItems(ctx, active, items, active.Dimensions())
+	if err != nil {
+		return 0, err
+	}
+	n, err := s.repo.RepairEmbeddings(ctx, active.Model(), items, vectors)
+	if err == nil && n > 0 {
+		s.invalidateAllSearchCaches(ctx)
+	}
+	return n, err
+}
+
+type dimensionMismatchError struct {
+	model     string
+	got, want int
+}
+
+func (e *dimensionMismatchError) Error() string {
+	return fmt.Sprintf("embedding model %s returned %d dimensions, expected %d", e.model, e.got, e.want)
+}
+
+func (s *Service) embedItems(ctx context.Context, e embedding.Embedder, items []ReembedItem, dims int) ([]pgvector.Vector, error) {
+	texts := make([]string, len(items))
+	for i, it := range items {
+		texts[i] = it.Title + " " + it.Content
+	}
+	raw, err := e.EmbedBatch(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed batch: %w", err)
+	}
+	if len(raw) != len(items) {
+		return nil, fmt.Errorf("embed batch returned %d vectors for %d inputs", len(raw), len(items))
+	}
+	vectors := make([]pgvector.Vector, len(raw))
+	for i, v := range raw {
+		if len(v) != dims {
+			return nil, &dimensionMismatchError{model: e.Model(), got: len(v), want: dims}
+		}
+		vectors[i] = pgvector.NewVector(v)
+	}
+	return vectors, nil
+}
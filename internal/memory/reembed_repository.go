@@ -0,0 +1,403 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+const embeddingMigrationColumns = `
+	id, source_model, source_dimensions, target_provider, target_model, target_dimensions,
+	status, total, processed, failed, cursor_id, last_error, started_at, updated_at, completed_at`
+
+func scanEmbeddingMigration(row pgx.Row) (*EmbeddingMigration, error) {
+	m := &EmbeddingMigration{}
+	err := row.Scan(
+		&m.ID, &m.SourceModel, &m.SourceDimensions, &m.TargetProvider, &m.TargetModel, &m.TargetDimensions,
+		&m.Status, &m.Total, &m.Processed, &m.Failed, &m.CursorID, &m.LastError,
+		&m.StartedAt, &m.UpdatedAt, &m.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetEmbeddingState returns the model currently serving search, or nil if
+// it has never been recorded.
+func (r *Repository) GetEmbeddingState(ctx context.Context) (*EmbeddingState, error) {
+	st := &EmbeddingState{}
+	err := r.pool.QueryRow(ctx, `SELECT provider, model, dimensions, updated_at FROM embedding_state`).Scan(
+		&st.Provider, &st.Model, &st.Dimensions, &st.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get embedding state: %w", err)
+	}
+	return st, nil
+}
+
+// InitEmbeddingState records the active model for the first time and stamps
+// vectors written before model tracking existed with it.
+func (r *Repository) InitEmbeddingState(ctx context.Context, provider, model string, dimensions int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin init embedding state: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO embedding_state (id, provider, model, dimensions)
+		VALUES (TRUE, $1, $2, $3)
+		ON CONFLICT (id) DO NOTHING
+	`, provider, model, dimensions)
+	if err != nil {
+		return fmt.Errorf("insert embedding state: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return tx.Commit(ctx)
+	}
+
+	if _, err := tx.Exec(ctx, "SET LOCAL contextify.skip_updated_at = 'on'"); err != nil {
+		return fmt.Errorf("set skip_updated_at: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE memories SET embedding_model = $1
+		WHERE embedding IS NOT NULL AND embedding_model IS NULL
+	`, model); err != nil {
+		return fmt.Errorf("stamp legacy embeddings: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// GetActiveEmbeddingMigration returns the running or paused migration, if any.
+func (r *Repository) GetActiveEmbeddingMigration(ctx context.Context) (*EmbeddingMigration, error) {
+	m, err := scanEmbeddingMigration(r.pool.QueryRow(ctx, `
+		SELECT `+embeddingMigrationColumns+`
+		FROM embedding_migrations
+		WHERE status IN ('running', 'paused')
+	`))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get active embedding migration: %w", err)
+	}
+	return m, nil
+}
+
+// GetLatestEmbeddingMigration returns the most recently started migration.
+func (r *Repository) GetLatestEmbeddingMigration(ctx context.Context) (*EmbeddingMigration, error) {
+	m, err := scanEmbeddingMigration(r.pool.QueryRow(ctx, `
+		SELECT `+embeddingMigrationColumns+`
+		FROM embedding_migrations
+		ORDER BY started_at DESC
+		LIMIT 1
+	`))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest embedding migration: %w", err)
+	}
+	return m, nil
+}
+
+// CreateEmbeddingMigration starts a migration from the active model to the
+// target. It fails if another migration is already running or paused.
+func (r *Repository) CreateEmbeddingMigration(ctx context.Context, source *EmbeddingState, provider, model string, dimensions int) (*EmbeddingMigration, error) {
+	m, err := scanEmbeddingMigration(r.pool.QueryRow(ctx, `
+		INSERT INTO embedding_migrations (source_model, source_dimensions, target_provider, target_model, target_dimensions, total)
+		VALUES ($1, $2, $3, $4, $5, (SELECT COUNT(*) FROM memories))
+		RETURNING `+embeddingMigrationColumns,
+		source.Model, source.Dimensions, provider, model, dimensions,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create embedding migration: %w", err)
+	}
+	return m, nil
+}
+
+// SetEmbeddingMigrationStatus changes the status of a migration.
+func (r *Repository) SetEmbeddingMigrationStatus(ctx context.Context, id uuid.UUID, status string, lastError *string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE embedding_migrations
+		SET status = $2, last_error = COALESCE($3, last_error),
+		    completed_at = CASE WHEN $2 IN ('completed', 'failed', 'cancelled') THEN NOW() ELSE completed_at END
+		WHERE id = $1
+	`, id, status, lastError)
+	if err != nil {
+		return fmt.Errorf("set embedding migration status: %w", err)
+	}
+	return nil
+}
+
+// RecordEmbeddingMigrationError stores the last error without changing status
+// so the next tick retries the same batch.
+func (r *Repository) RecordEmbeddingMigrationError(ctx context.Context, id uuid.UUID, msg string, failed int) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE embedding_migrations SET last_error = $2, failed = failed + $3 WHERE id = $1
+	`, id, msg, failed)
+	if err != nil {
+		return fmt.Errorf("record embedding migration error: %w", err)
+	}
+	return nil
+}
+
+// ListPendingReembed returns memories after cursor (by id) whose live vector
+// and shadow vector were both produced by a model other than target.
+func (r *Repository) ListPendingReembed(ctx context.Context, target string, cursor *uuid.UUID, limit int) ([]ReembedItem, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, title, content
+		FROM memories
+		WHERE embedding_model IS DISTINCT FROM $1
+		  AND embedding_next_model IS DISTINCT FROM $1
+		  AND ($2::uuid IS NULL OR id > $2)
+		ORDER BY id
+		LIMIT $3
+	`, target, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list pending reembed: %w", err)
+	}
+	defer rows.Close()
+
+	var items []ReembedItem
+	for rows.Next() {
+		var it ReembedItem
+		if err := rows.Scan(&it.ID, &it.Title, &it.Content); err != nil {
+			return nil, fmt.Errorf("scan pending reembed: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// CountPendingReembed counts memories not yet covered by the target model.
+func (r *Repository) CountPendingReembed(ctx context.Context, target string) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM memories
+		WHERE embedding_model IS DISTINCT FROM $1
+		  AND embedding_next_model IS DISTINCT FROM $1
+	`, target).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count pending reembed: %w", err)
+	}
+	return n, nil
+}
+
+// StoreReembedBatch writes shadow vectors for a batch and advances the
+// migration cursor in one transaction. Rows whose title or content changed
+// since they were read are skipped and picked up on the next pass.
+func (r *Repository) StoreReembedBatch(ctx context.Context, migrationID uuid.UUID, model string, items []ReembedItem, vectors []pgvector.Vector) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin reembed batch: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SET LOCAL contextify.skip_updated_at = 'on'"); err != nil {
+		return 0, fmt.Errorf("set skip_updated_at: %w", err)
+	}
+
+	written := 0
+	for i, it := range items {
+		tag, err := tx.Exec(ctx, `
+			UPDATE memories SET embedding_next = $2, embedding_next_model = $3
+			WHERE id = $1 AND title = $4 AND content = $5
+		`, it.ID, vectors[i], model, it.Title, it.Content)
+		if err != nil {
+			return 0, fmt.Errorf("store reembed vector: %w", err)
+		}
+		written += int(tag.RowsAffected())
+	}
+
+	lastID := items[len(items)-1].ID
+	if _, err := tx.Exec(ctx, `
+		UPDATE embedding_migrations
+		SET processed = processed + $2, cursor_id = $3, last_error = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, migrationID, written, lastID); err != nil {
+		return 0, fmt.Errorf("advance reembed cursor: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit reembed batch: %w", err)
+	}
+	return written, nil
+}
+
+// ResetEmbeddingMigrationCursor starts a new pass over the table so rows
+// written behind the cursor during the previous pass are covered.
+func (r *Repository) ResetEmbeddingMigrationCursor(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `UPDATE embedding_migrations SET cursor_id = NULL WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("reset embedding migration cursor: %w", err)
+	}
+	return nil
+}
+
+// CutoverEmbeddings promotes the shadow vectors to the live column once every
+// memory is covered. Writes are blocked for the duration; if a row slipped in
+// uncovered the transaction is abandoned and ok is false. When dimensions
+// change the column type is rewritten, which rebuilds the HNSW index.
+func (r *Repository) CutoverEmbeddings(ctx context.Context, m *EmbeddingMigration) (ok bool, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin embedding cutover: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "LOCK TABLE memories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return false, fmt.Errorf("lock memories: %w", err)
+	}
+
+	var pending int
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM memories
+		WHERE embedding_model IS DISTINCT FROM $1
+		  AND embedding_next_model IS DISTINCT FROM $1
+	`, m.TargetModel).Scan(&pending); err != nil {
+		return false, fmt.Errorf("count uncovered memories: %w", err)
+	}
+	if pending > 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, "SET LOCAL contextify.skip_updated_at = 'on'"); err != nil {
+		return false, fmt.Errorf("set skip_updated_at: %w", err)
+	}
+
+	var columnDims int
+	if err := tx.QueryRow(ctx, `
+		SELECT atttypmod FROM pg_attribute
+		WHERE attrelid = 'memories'::regclass AND attname = 'embedding'
+	`).Scan(&columnDims); err != nil {
+		return false, fmt.Errorf("read embedding column type: %w", err)
+	}
+
+	if columnDims != m.TargetDimensions {
+		// Every row has a shadow vector at this point (none can be live on a
+		// model of another size), so the rewrite takes embedding_next wholesale.
+		// USING may reference other columns, keeping the copy to one step.
+		stmt := fmt.Sprintf(`ALTER TABLE memories ALTER COLUMN embedding TYPE vector(%d) USING embedding_next::vector(%d)`,
+			m.TargetDimensions, m.TargetDimensions)
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return false, fmt.Errorf("alter embedding column: %w", err)
+		}
+	} else {
+		if _, err := tx.Exec(ctx, `
+			UPDATE memories SET embedding = embedding_next
+			WHERE embedding_next_model = $1
+		`, m.TargetModel); err != nil {
+			return false, fmt.Errorf("copy shadow embeddings: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE memories
+		SET embedding_model = $1, embedding_next = NULL, embedding_next_model = NULL
+		WHERE embedding_next_model = $1
+	`, m.TargetModel); err != nil {
+		return false, fmt.Errorf("stamp embedding model: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO embedding_state (id, provider, model, dimensions, updated_at)
+		VALUES (TRUE, $1, $2, $3, NOW())
+		ON CONFLICT (id) DO UPDATE
+		SET provider = EXCLUDED.provider, model = EXCLUDED.model,
+		    dimensions = EXCLUDED.dimensions, updated_at = NOW()
+	`, m.TargetProvider, m.TargetModel, m.TargetDimensions); err != nil {
+		return false, fmt.Errorf("update embedding state: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE embedding_migrations
+		SET status = 'completed', completed_at = NOW(), cursor_id = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, m.ID); err != nil {
+		return false, fmt.Errorf("complete embedding migration: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit embedding cutover: %w", err)
+	}
+	return true, nil
+}
+
+// ListStaleEmbeddings returns memories whose live vector was not produced by
+// model, e.g. writes that raced a cutover or rows that never got a vector.
+func (r *Repository) ListStaleEmbeddings(ctx context.Context, model string, limit int) ([]ReembedItem, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, title, content
+		FROM memories
+		WHERE embedding_model IS DISTINCT FROM $1
+		ORDER BY id
+		LIMIT $2
+	`, model, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list stale embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var items []ReembedItem
+	for rows.Next() {
+		var it ReembedItem
+		if err := rows.Scan(&it.ID, &it.Title, &it.Content); err != nil {
+			return nil, fmt.Errorf("scan stale embedding: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// RepairEmbeddings writes live vectors for stale rows, skipping rows edited
+// since they were read.
+func (r *Repository) RepairEmbeddings(ctx context.Context, model string, items []ReembedItem, vectors []pgvector.Vector) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin repair embeddings: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SET LOCAL contextify.skip_updated_at = 'on'"); err != nil {
+		return 0, fmt.Errorf("set skip_updated_at: %w", err)
+	}
+
+	written := 0
+	for i, it := range items {
+		tag, err := tx.Exec(ctx, `
+			UPDATE memories SET embedding = $2, embedding_model = $3
+			WHERE id = $1 AND title = $4 AND content = $5
+		`, it.ID, vectors[i], model, it.Title, it.Content)
+		if err != nil {
+			return 0, fmt.Errorf("repair embedding: %w", err)
+		}
+		written += int(tag.RowsAffected())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit repair embeddings: %w", err)
+	}
+	return written, nil
+}
+
+// EmbeddingCoverage counts memories by the model behind their live and shadow vectors.
+func (r *Repository) EmbeddingCoverage(ctx context.Context, target string) (total, live, shadow int, err error) {
+	err = r.pool.QueryRow(ctx, `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE embedding_model = $1),
+		       COUNT(*) FILTER (WHERE embedding_model IS DISTINCT FROM $1 AND embedding_next_model = $1)
+		FROM memories
+	`, target).Scan(&total, &live, &shadow)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("embedding coverage: %w", err)
+	}
+	return total, live, shadow, nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/atakanatali/contextify/internal/embedding"
+)
+
+func TestEmbedItems_ReturnsVectorsInOrder(t *testing.T) {
+	s := &Service{}
+	e := embedding.NewHashEmbedder(16)
+	items := []ReembedItem{{Title: "a", Content: "first"}, {Title: "b", Content: "second"}}
+
+	vecs, err := s.embedItems(context.Background(), e, items, 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vecs) != 2 {
+		t.Fatalf("expected 2 vectors, got %d", len(vecs))
+	}
+	want, _ := e.Embed(context.Background(), "b second")
+	got := vecs[1].Slice()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("vector 1 does not match embedding of item 1")
+		}
+	}
+}
+
+func TestEmbedItems_RejectsDimensionMismatch(t *testing.T) {
+	s := &Service{}
+	items := []ReembedItem{{Title: "a", Content: "first"}}
+
+	_, err := s.embedItems(context.Background(), embedding.NewHashEmbedder(8), items, 16)
+	var dimErr *dimensionMismatchError
+	if !errors.As(err, &dimErr) {
+		t.Fatalf("expected dimension mismatch error, got %v", err)
+	}
+	if dimErr.got != 8 || dimErr.want != 16 {
+		t.Fatalf("unexpected mismatch: %+v", dimErr)
+	}
+}
+
+func TestAdoptEmbeddingState_FollowsCutover(t *testing.T) {
+	// An instance that is not the scheduler leader still serves the old
+	// model until it sees the leader's cutover recorded.
+	old, target := embedding.NewHashEmbedder(8), embedding.NewHashEmbedder(16)
+	s := &Service{embedder: old, target: target}
+
+	s.adoptEmbeddingState(context.Background(), &EmbeddingState{Model: old.Model(), Dimensions: 8})
+	if s.activeEmbedder() != old {
+		t.Fatalf("switched before the cutover")
+	}
+
+	s.adoptEmbeddingState(context.Background(), &EmbeddingState{Model: target.Model(), Dimensions: 16})
+	if s.activeEmbedder() != target {
+		t.Fatalf("expected the configured model after the cutover, got %s", s.activeEmbedder().Model())
+	}
+	if err := s.SyncActiveEmbedder(context.Background()); err != nil {
+		t.Fatalf("an instance on the configured model should not look the state up: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const relationshipColumns = `r.id, r.from_memory_id, r.to_memory_id, r.relationship, r.strength, r.context, r.created_at`
+
+func scanRelationship(row pgx.Row, rel *Relationship) error {
+	return row.Scan(&rel.ID, &rel.FromMemoryID, &rel.ToMemoryID, &rel.Relationship, &rel.Strength, &rel.Context, &rel.CreatedAt)
+}
+
+// GetRelationship returns a relationship by ID, or nil if it does not exist
+// in the caller's workspace.
+func (r *Repository) GetRelationship(ctx context.Context, id uuid.UUID) (*Relationship, error) {
+	var rel Relationship
+	err := scanRelationship(r.pool.QueryRow(ctx, `
+		SELECT `+relationshipColumns+`
+		FROM memory_relationships r
+		WHERE r.id = $1 AND r.workspace_id = $2
+	`, id, WorkspaceFromContext(ctx)), &rel)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get relationship: %w", err)
+	}
+	return &rel, nil
+}
+
+// ListRelationships returns the relationships matching f and their total count.
+func (r *Repository) ListRelationships(ctx context.Context, f RelationshipFilter) (*RelationshipList, error) {
+	conditions := []string{
+		"r.workspace_id = $1",
+		"NOT EXISTS (SELECT 1 FROM memories t WHERE t.id IN (r.from_memory_id, r.to_memory_id) AND t.deleted_at IS NOT NULL)",
+	}
+	args := []any{WorkspaceFromContext(ctx)}
+	argIdx := 2
+
+	if f.ProjectID != nil {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM memories m
+			WHERE m.id IN (r.from_memory_id, r.to_memory_id) AND m.project_id = $%d
+		)`, argIdx))
+		args = append(args, *f.ProjectID)
+		argIdx++
+	}
+	if f.MemoryID != nil {
+		conditions = append(conditions, fmt.Sprintf("(r.from_memory_id = $%d OR r.to_memory_id = $%d)", argIdx, argIdx))
+		args = append(args, *f.MemoryID)
+		argIdx++
+	}
+	if len(f.Types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("r.relationship = ANY($%d)", argIdx))
+		args = append(args, f.Types)
+		argIdx++
+	}
+
+	where := strings.Join(conditions, " AND ")
+
+	list := &RelationshipList{Relationships: []Relationship{}}
+	err := r.pool.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) FROM memory_relationships r WHERE %s", where), args...).Scan(&list.Total)
+	if err != nil {
+		return nil, fmt.Errorf("count relationships: %w", err)
+	}
+
+	args = append(args, f.Limit, f.Offset)
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+		SELECT `+relationshipColumns+`
+		FROM memory_relationships r
+		WHERE %s
+		ORDER BY r.strength DESC, r.created_at DESC, r.id
+		LIMIT $%d OFFSET $%d
+	`, where, argIdx, argIdx+1), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list relationships: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rel Relationship
+		if err := scanRelationship(rows, &rel); err != nil {
+			return nil, fmt.Errorf("scan relationship: %w", err)
+		}
+		list.Relationships = append(list.Relationships, rel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list relationships: %w", err)
+	}
+	return list, nil
+}
+
+// UpdateRelationship writes rel's ends, type, strength and context.
+func (r *Repository) UpdateRelationship(ctx context.Context, rel *Relationship) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE memory_relationships
+		SET from_memory_id = $2, to_memory_id = $3, relationship = $4, strength = $5, context = $6
+		WHERE id = $1 AND workspace_id = $7
+	`, rel.ID, rel.FromMemoryID, rel.ToMemoryID, rel.Relationship, rel.Strength, rel.Context, WorkspaceFromContext(ctx))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: %s -[%s]-> %s", ErrRelationshipExists, rel.FromMemoryID, rel.Relationship, rel.ToMemoryID)
+		}
+		return fmt.Errorf("update relationship: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrRelationshipNotFound, rel.ID)
+	}
+	return nil
+}
+
+// DeleteRelationship removes a relationship from the caller's workspace.
+func (r *Repository) DeleteRelationship(ctx context.Context, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, "DELETE FROM memory_relationships WHERE id = $1 AND workspace_id = $2", id, WorkspaceFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("delete relationship: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrRelationshipNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RelationshipType is a known kind of edge between memories. Name reads from
+// the edge's source to its target; Inverse reads the other way and equals
+// Name for symmetric types.
+type RelationshipType struct {
+	Name        string `json:"name"`
+	Inverse     string `json:"inverse"`
+	Description string `json:"description"`
+}
+
+const (
+	RelSolves      = "SOLVES"
+	RelCauses      = "CAUSES"
+	RelRelatedTo   = "RELATED_TO"
+	RelRequires    = "REQUIRES"
+	RelAddresses   = "ADDRESSES"
+	RelSupersedes  = "SUPERSEDES"
+	RelDerivedFrom = "DERIVED_FROM"
+)
+
+// RelationshipTypes is the registry of accepted relationship types, keyed by
+// canonical name. Edges are always stored under the canonical name.
+var RelationshipTypes = map[string]RelationshipType{
+	RelSolves:      {Name: RelSolves, Inverse: "SOLVED_BY", Description: "a fix or solution resolves a problem or error"},
+	RelCauses:      {Name: RelCauses, Inverse: "CAUSED_BY", Description: "one memory is the cause of another"},
+	RelRelatedTo:   {Name: RelRelatedTo, Inverse: RelRelatedTo, Description: "loosely related; symmetric"},
+	RelRequires:    {Name: RelRequires, Inverse: "REQUIRED_BY", Description: "one memory depends on another"},
+	RelAddresses:   {Name: RelAddresses, Inverse: "ADDRESSED_BY", Description: "a decision or task deals with a problem without fully solving it"},
+	RelSupersedes:  {Name: RelSupersedes, Inverse: "SUPERSEDED_BY", Description: "a newer memory replaces an older one"},
+	RelDerivedFrom: {Name: RelDerivedFrom, Inverse: "SOURCE_OF", Description: "a memory was derived from a source memory"},
+}
+
+// relationshipInverses maps each non-symmetric inverse name to its canonical type.
+var relationshipInverses = func() map[string]string {
+	m := make(map[string]string, len(RelationshipTypes))
+	for name, t := range RelationshipTypes {
+		if t.Inverse != name {
+			m[t.Inverse] = name
+		}
+	}
+	return m
+}()
+
+// normalizeRelationshipName upper-cases name and turns spaces and hyphens into
+// underscores, so "related to" and "related-to" both become RELATED_TO.
+func normalizeRelationshipName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
+
+// ResolveRelationshipType returns the canonical type for name, which may be a
+// canonical or an inverse name. inverted is true for an inverse name, meaning
+// the caller's ends must be swapped.
+func ResolveRelationshipType(name string) (canonical string, inverted bool, err error) {
+	n := normalizeRelationshipName(name)
+	if _, ok := RelationshipTypes[n]; ok {
+		return n, false, nil
+	}
+	if c, ok := relationshipInverses[n]; ok {
+		return c, true, nil
+	}
+	return "", false, fmt.Errorf("%w: %q", ErrUnknownRelationshipType, name)
+}
+
+// canonicalRelationshipFilter maps the type names in a filter to canonical
+// names. Both directions are matched by filters, so inverse names simply
+// select their canonical type; unknown names pass through normalized.
+func canonicalRelationshipFilter(types []string) []string {
+	if len(types) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		if c, _, err := ResolveRelationshipType(t); err == nil {
+			out = append(out, c)
+		} else {
+			out = append(out, normalizeRelationshipName(t))
+		}
+	}
+	return out
+}
+
+// ListRelationshipTypes returns the registry sorted by name.
+func ListRelationshipTypes() []RelationshipType {
+	types := make([]RelationshipType, 0, len(RelationshipTypes))
+	for _, t := range RelationshipTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+func validateStrength(strength float32) error {
+	if strength < 0 || strength > 1 {
+		return fmt.Errorf("%w: strength must be between 0 and 1", ErrInvalidRelationship)
+	}
+	return nil
+}
+
+// CreateRelationship links two memories. The type must be registered; an
+// inverse name such as SOLVED_BY is stored as its canonical type with the
+// ends swapped. Creating an edge that already exists updates its strength and
+// context.
+func (s *Service) CreateRelationship(ctx context.Context, req RelationshipRequest) (*Relationship, error) {
+	name, inverted, err := ResolveRelationshipType(req.Relationship)
+	if err != nil {
+		return nil, err
+	}
+	if req.FromMemoryID == req.ToMemoryID {
+		return nil, fmt.Errorf("%w: a memory cannot be related to itself", ErrInvalidRelationship)
+	}
+	if err := validateStrength(req.Strength); err != nil {
+		return nil, err
+	}
+
+	// Both ends must be visible in the caller's workspace.
+	for _, id := range []uuid.UUID{req.FromMemoryID, req.ToMemoryID} {
+		if err := s.requireMemory(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	rel := &Relationship{
+		ID:           uuid.New(),
+		FromMemoryID: req.FromMemoryID,
+		ToMemoryID:   req.ToMemoryID,
+		Relationship: name,
+		Strength:     req.Strength,
+		Context:      req.Context,
+		CreatedAt:    time.Now(),
+	}
+	if inverted {
+		rel.FromMemoryID, rel.ToMemoryID = rel.ToMemoryID, rel.FromMemoryID
+	}
+
+	if rel.Strength <= 0 {
+		rel.Strength = 0.5
+	}
+
+	if err := s.repo.StoreRelationship(ctx, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// GetRelationship returns a single relationship.
+func (s *Service) GetRelationship(ctx context.Context, id uuid.UUID) (*Relationship, error) {
+	rel, err := s.repo.GetRelationship(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rel == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRelationshipNotFound, id)
+	}
+	return rel, nil
+}
+
+// ListRelationships returns one page of relationships matching f, strongest
+// first, with the total number of matches.
+func (s *Service) ListRelationships(ctx context.Context, f RelationshipFilter) (*RelationshipList, error) {
+	if f.Limit <= 0 {
+		f.Limit = 50
+	}
+	if f.Limit > 500 {
+		f.Limit = 500
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	if f.ProjectID != nil {
+		p := s.normalizeProject(*f.ProjectID)
+		f.ProjectID = &p
+	}
+	f.Types = canonicalRelationshipFilter(f.Types)
+	return s.repo.ListRelationships(ctx, f)
+}
+
+// UpdateRelationship changes a relationship's type, strength or context.
+// Changing the type to one the ends are already linked by returns
+// ErrRelationshipExists.
+func (s *Service) UpdateRelationship(ctx context.Context, id uuid.UUID, req RelationshipUpdate) (*Relationship, error) {
+	rel, err := s.GetRelationship(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Relationship != nil {
+		name, inverted, err := ResolveRelationshipType(*req.Relationship)
+		if err != nil {
+			return nil, err
+		}
+		rel.Relationship = name
+		if inverted {
+			rel.FromMemoryID, rel.ToMemoryID = rel.ToMemoryID, rel.FromMemoryID
+		}
+	}
+	if req.Strength != nil {
+		if err := validateStrength(*req.Strength); err != nil {
+			return nil, err
+		}
+		rel.Strength = *req.Strength
+	}
+	if req.Context != nil {
+		rel.Context = req.Context
+		if *req.Context == "" {
+			rel.Context = nil
+		}
+	}
+
+	if err := s.repo.UpdateRelationship(ctx, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// DeleteRelationship removes a relationship. The memories are untouched.
+func (s *Service) DeleteRelationship(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteRelationship(ctx, id)
+}
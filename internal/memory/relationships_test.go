@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveRelationshipType(t *testing.T) {
+	cases := []struct {
+		in       string
+		want     string
+		inverted bool
+	}{
+		{"SOLVES", RelSolves, false},
+		{"solves", RelSolves, false},
+		{"related to", RelRelatedTo, false},
+		{"related-to", RelRelatedTo, false},
+		{" Related__To ", RelRelatedTo, false},
+		{"SOLVED_BY", RelSolves, true},
+		{"caused by", RelCauses, true},
+		{"superseded-by", RelSupersedes, true},
+		{"SOURCE_OF", RelDerivedFrom, true},
+	}
+	for _, c := range cases {
+		got, inverted, err := ResolveRelationshipType(c.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", c.in, err)
+		}
+		if got != c.want || inverted != c.inverted {
+			t.Errorf("%q: got (%s, %v), want (%s, %v)", c.in, got, inverted, c.want, c.inverted)
+		}
+	}
+
+	for _, in := range []string{"", "LIKES", "SOLVES_ALL"} {
+		if _, _, err := ResolveRelationshipType(in); !errors.Is(err, ErrUnknownRelationshipType) {
+			t.Errorf("%q: expected ErrUnknownRelationshipType, got %v", in, err)
+		}
+	}
+}
+
+func TestRelationshipRegistryInverses(t *testing.T) {
+	seen := map[string]string{}
+	for name, rt := range RelationshipTypes {
+		if rt.Name != name {
+			t.Errorf("registry key %s holds type %s", name, rt.Name)
+		}
+		if rt.Inverse == name {
+			continue
+		}
+		if _, ok := RelationshipTypes[rt.Inverse]; ok {
+			t.Errorf("inverse %s of %s is itself a canonical type", rt.Inverse, name)
+		}
+		if other, ok := seen[rt.Inverse]; ok {
+			t.Errorf("inverse %s shared by %s and %s", rt.Inverse, other, name)
+		}
+		seen[rt.Inverse] = name
+	}
+	if rt := RelationshipTypes[RelRelatedTo]; rt.Inverse != RelRelatedTo {
+		t.Errorf("RELATED_TO should be symmetric, inverse is %s", rt.Inverse)
+	}
+}
+
+func TestCanonicalRelationshipFilter(t *testing.T) {
+	if got := canonicalRelationshipFilter(nil); got != nil {
+		t.Fatalf("empty filter should stay nil, got %v", got)
+	}
+	got := canonicalRelationshipFilter([]string{"solved by", "causes", "custom-kind"})
+	want := []string{RelSolves, RelCauses, "CUSTOM_KIND"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
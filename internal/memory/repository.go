@@ -38,13 +38,18 @@ func (r *Repository) StoreTelemetryEvent(ctx context.Context, event *TelemetryEv
 	query := `
 		INSERT INTO memory_telemetry_events (
 			event_type, session_id, request_id, agent_source, project_id,
-			memory_id, query_text, action, hit_count, latency_ms, metadata
+			memory_id, query_text, action, hit_count, latency_ms, metadata, workspace_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb, $12)
 	`
+	workspace := event.WorkspaceID
+	if workspace == "" {
+		workspace = DefaultWorkspace
+	}
 	_, err = r.pool.Exec(ctx, query,
 		string(event.EventType), event.SessionID, event.RequestID, event.AgentSource, event.ProjectID,
 		event.MemoryID, event.QueryText, event.Action, event.HitCount, event.LatencyMs, string(metadataJSON),
+		workspace,
 	)
 	if err != nil {
 		return fmt.Errorf("store telemetry event: %w", err)
@@ -55,13 +60,14 @@ func (r *Repository) StoreTelemetryEvent(ctx context.Context, event *TelemetryEv
 
 func (r *Repository) Store(ctx context.Context, mem *Memory) error {
 	query := `
-		INSERT INTO memories (id, title, content, summary, embedding, type, scope, project_id, agent_source, tags, importance, ttl_seconds, access_count, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		INSERT INTO memories (id, title, content, summary, embedding, embedding_model, type, scope, project_id, agent_source, tags, importance, ttl_seconds, access_count, expires_at, workspace_id, language)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17::regconfig)
 	`
 	_, err := r.pool.Exec(ctx, query,
-		mem.ID, mem.Title, mem.Content, mem.Summary, mem.Embedding,
+		mem.ID, mem.Title, mem.Content, mem.Summary, mem.Embedding, mem.EmbeddingModel,
 		mem.Type, mem.Scope, mem.ProjectID, mem.AgentSource,
 		mem.Tags, mem.Importance, mem.TTLSeconds, mem.AccessCount, mem.ExpiresAt,
+		WorkspaceFromContext(ctx), mem.Language,
 	)
 	if err != nil {
 		return fmt.Errorf("store memory: %w", err)
@@ -71,15 +77,15 @@ func (r *Repository) Store(ctx context.Context, mem *Memory) error {
 
 func (r *Repository) Get(ctx context.Context, id uuid.UUID) (*Memory, error) {
 	query := `
-		SELECT id, title, content, summary, embedding, type, scope, project_id, agent_source,
-		       tags, importance, ttl_seconds, access_count, created_at, updated_at, expires_at,
+		SELECT id, title, content, summary, embedding, embedding_model, type, scope, project_id, agent_source,
+		       language::text, tags, importance, ttl_seconds, access_count, created_at, updated_at, expires_at,
 		       version, merged_from, replaced_by
-		FROM memories WHERE id = $1
+		FROM memories WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
 	`
 	mem := &Memory{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&mem.ID, &mem.Title, &mem.Content, &mem.Summary, &mem.Embedding,
-		&mem.Type, &mem.Scope, &mem.ProjectID, &mem.AgentSource,
+	err := r.pool.QueryRow(ctx, query, id, WorkspaceFromContext(ctx)).Scan(
+		&mem.ID, &mem.Title, &mem.Content, &mem.Summary, &mem.Embedding, &mem.EmbeddingModel,
+		&mem.Type, &mem.Scope, &mem.ProjectID, &mem.AgentSource, &mem.Language,
 		&mem.Tags, &mem.Importance, &mem.TTLSeconds, &mem.AccessCount,
 		&mem.CreatedAt, &mem.UpdatedAt, &mem.ExpiresAt,
 		&mem.Version, &mem.MergedFrom, &mem.ReplacedBy,
@@ -93,7 +99,7 @@ func (r *Repository) Get(ctx context.Context, id uuid.UUID) (*Memory, error) {
 	return mem, nil
 }
 
-func (r *Repository) Update(ctx context.Context, id uuid.UUID, req UpdateRequest, newEmbedding *pgvector.Vector) error {
+func (r *Repository) Update(ctx context.Context, id uuid.UUID, req UpdateRequest, newEmbedding *pgvector.Vector, embeddingModel string) error {
 	sets := []string{}
 	args := []any{}
 	argIdx := 1
@@ -128,18 +134,24 @@ func (r *Repository) Update(ctx context.Context, id uuid.UUID, req UpdateRequest
 		args = append(args, *req.Importance)
 		argIdx++
 	}
-	if newEmbedding != nil {
-		sets = append(sets, fmt.Sprintf("embedding = $%d", argIdx))
-		args = append(args, newEmbedding)
+	if req.Language != nil {
+		sets = append(sets, fmt.Sprintf("language = $%d::regconfig", argIdx))
+		args = append(args, *req.Language)
 		argIdx++
 	}
+	if newEmbedding != nil {
+		// A pending re-embed of the old content is stale; let the job pick the row up again.
+		sets = append(sets, fmt.Sprintf("embedding = $%d, embedding_model = $%d, embedding_next = NULL, embedding_next_model = NULL", argIdx, argIdx+1))
+		args = append(args, newEmbedding, embeddingModel)
+		argIdx += 2
+	}
 
 	if len(sets) == 0 {
 		return nil
 	}
 
-	args = append(args, id)
-	query := fmt.Sprintf("UPDATE memories SET %s WHERE id = $%d", strings.Join(sets, ", "), argIdx)
+	args = append(args, id, WorkspaceFromContext(ctx))
+	query := fmt.Sprintf("UPDATE memories SET %s WHERE id = $%d AND workspace_id = $%d AND deleted_at IS NULL", strings.Join(sets, ", "), argIdx, argIdx+1)
 
 	result, err := r.pool.Exec(ctx, query, args...)
 	if err != nil {
@@ -151,8 +163,12 @@ func (r *Repository) Update(ctx context.Context, id uuid.UUID, req UpdateRequest
 	return nil
 }
 
+// Delete moves a memory to the trash; PurgeTrash removes it for good.
 func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
-	result, err := r.pool.Exec(ctx, "DELETE FROM memories WHERE id = $1", id)
+	result, err := r.pool.Exec(ctx, `
+		UPDATE memories SET deleted_at = NOW(), deleted_reason = 'deleted'
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+	`, id, WorkspaceFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("delete memory: %w", err)
 	}
@@ -162,11 +178,17 @@ func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// HybridSearch performs combined vector + keyword search, excluding replaced memories.
-func (r *Repository) HybridSearch(ctx context.Context, queryEmbedding pgvector.Vector, req SearchRequest, vectorWeight, keywordWeight float64) ([]SearchResult, error) {
-	conditions := []string{}
-	args := []any{queryEmbedding}
-	argIdx := 2
+// HybridSearchCandidates returns the memories matching req's filters that rank
+// among the top candidates by vector similarity or by keyword match, with
+// their raw scores and rank positions. Trashed memories are excluded, and
+// replaced and expired ones unless the filter asks for them. Scoring and pagination are left to a Ranker.
+//
+// With chunkModel set, a memory's vector score is the better of its own and
+// its best chunk's similarity, among chunks embedded with chunkModel.
+func (r *Repository) HybridSearchCandidates(ctx context.Context, queryEmbedding pgvector.Vector, req SearchRequest, chunkModel string) ([]SearchCandidate, error) {
+	conditions := []string{"m.workspace_id = $2"}
+	args := []any{queryEmbedding, WorkspaceFromContext(ctx)}
+	argIdx := 3
 
 	if req.Type != nil {
 		conditions = append(conditions, fmt.Sprintf("m.type = $%d", argIdx))
@@ -199,10 +221,36 @@ func (r *Repository) HybridSearch(ctx context.Context, queryEmbedding pgvector.V
 		argIdx++
 	}
 
-	// Exclude expired and replaced memories
-	conditions = append(conditions, "(m.expires_at IS NULL OR m.expires_at > NOW())")
-	conditions = append(conditions, "m.replaced_by IS NULL")
-	conditions = append(conditions, "m.embedding IS NOT NULL")
+	filterConds, filterArgs := req.filter.Compile(argIdx)
+	conditions = append(conditions, filterConds...)
+	args = append(args, filterArgs...)
+	argIdx += len(filterArgs)
+
+	// Symbols are matched exactly or by prefix. A plain query's symbols are
+	// extracted by memory_symbols() like the memories' own, and only add to
+	// the ranking; a symbol search only returns memories that match.
+	var exactSymbols []string
+	prefixSymbols := []string{}
+	if req.symbols != nil {
+		exactSymbols, prefixSymbols = req.symbols.exact, req.symbols.prefixes
+	}
+	exactArgIdx, prefixArgIdx := argIdx, argIdx+1
+	args = append(args, exactSymbols, prefixSymbols)
+	argIdx += 2
+	if req.symbols != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"(m.symbols && $%d::text[] OR EXISTS (SELECT 1 FROM unnest(m.symbols) s, unnest($%d::text[]) p WHERE starts_with(s, p)))",
+			exactArgIdx, prefixArgIdx))
+	}
+
+	// Exclude expired and replaced memories unless the filter asks for them
+	if !req.filter.IncludeExpired() {
+		conditions = append(conditions, "(m.expires_at IS NULL OR m.expires_at > NOW())")
+	}
+	if !req.filter.IncludeReplaced() {
+		conditions = append(conditions, "m.replaced_by IS NULL")
+	}
+	conditions = append(conditions, "m.deleted_at IS NULL", "m.embedding IS NOT NULL")
 
 	whereClause := ""
 	if len(conditions) > 0 {
@@ -219,13 +267,22 @@ func (r *Repository) HybridSearch(ctx context.Context, queryEmbedding pgvector.V
 	}
 
 	queryText := strings.TrimSpace(req.Query)
-	isBroadQuery := queryText == "" || queryText == "*"
+	isBroadQuery := queryText == "" || queryText == "*" || req.symbols != nil
 
+	// Candidates are taken per signal: the top candidateLimit memories by
+	// vector score and the top candidateLimit by keyword score. A paginated
+	// search reaches past the results already seen and one more, which
+	// tells whether another page follows, up to maxSearchDepth.
 	candidateLimit := limit * 6
 	if candidateLimit < 80 {
 		candidateLimit = 80
 	}
-	if candidateLimit > 400 {
+	if candidateLimit < limit+offset {
+		candidateLimit = limit + offset
+	}
+	if req.Paginate {
+		candidateLimit = min(max(candidateLimit, limit+offset+1), maxSearchDepth+1)
+	} else if candidateLimit > 400 {
 		candidateLimit = 400
 	}
 
@@ -237,77 +294,111 @@ func (r *Repository) HybridSearch(ctx context.Context, queryEmbedding pgvector.V
 	args = append(args, isBroadQuery)
 	argIdx++
 
-	vwArgIdx := argIdx
-	args = append(args, vectorWeight)
-	argIdx++
-
-	kwArgIdx := argIdx
-	args = append(args, keywordWeight)
-	argIdx++
-
 	candidateLimitArgIdx := argIdx
 	args = append(args, candidateLimit)
 	argIdx++
 
-	boostClauses := []string{"0"}
+	projectBoostExpr := "0"
 	if req.ProjectID != nil {
-		boostClauses = append(boostClauses, fmt.Sprintf("CASE WHEN c.project_id = $%d THEN 0.05 ELSE 0 END", argIdx))
+		projectBoostExpr = fmt.Sprintf("CASE WHEN c.project_id = $%d THEN 0.05 ELSE 0 END", argIdx)
 		args = append(args, *req.ProjectID)
 		argIdx++
 	}
+	tagBoostExpr := "0"
 	if len(req.Tags) > 0 {
-		boostClauses = append(boostClauses, fmt.Sprintf("CASE WHEN c.tags && $%d THEN 0.03 ELSE 0 END", argIdx))
+		tagBoostExpr = fmt.Sprintf("CASE WHEN c.tags && $%d THEN 0.03 ELSE 0 END", argIdx)
 		args = append(args, req.Tags)
 		argIdx++
 	}
-	boostExpr := strings.Join(boostClauses, " + ")
 
-	limitArgIdx := argIdx
-	args = append(args, limit)
-	argIdx++
-
-	offsetArgIdx := argIdx
-	args = append(args, offset)
+	chunkJoin := "LEFT JOIN LATERAL (SELECT NULL::int AS chunk_index, NULL::int AS start_offset, NULL::int AS end_offset, NULL::float8 AS score) bc ON true"
+	if chunkModel != "" {
+		chunkJoin = fmt.Sprintf(`LEFT JOIN LATERAL (
+				SELECT ch.chunk_index, ch.start_offset, ch.end_offset, 1 - (ch.embedding <=> $1) AS score
+				FROM memory_chunks ch
+				WHERE ch.memory_id = m.id AND ch.embedding_model = $%d
+				ORDER BY ch.embedding <=> $1
+				LIMIT 1
+			) bc ON true`, argIdx)
+		args = append(args, chunkModel)
+		argIdx++
+	}
 
 	query := fmt.Sprintf(`
-		WITH base AS (
+		WITH query_symbols AS (
+			SELECT q.exact, q.prefixes,
+			       cardinality(q.exact) + cardinality(q.prefixes) AS n
+			FROM (
+				SELECT
+					COALESCE($%d::text[], CASE WHEN $%d THEN '{}'::text[] ELSE memory_symbols(NULL, $%d) END) AS exact,
+					$%d::text[] AS prefixes
+			) q
+		),
+		base AS (
 			SELECT
 				m.id, m.title, m.content, m.summary, m.type, m.scope, m.project_id,
-				m.agent_source, m.tags, m.importance, m.ttl_seconds, m.access_count,
+				m.agent_source, m.language::text AS language, m.tags, m.importance, m.ttl_seconds, m.access_count,
 				m.created_at, m.updated_at, m.expires_at,
-				1 - (m.embedding <=> $1) AS vector_score,
+				GREATEST(1 - (m.embedding <=> $1), bc.score) AS vector_score,
+				bc.chunk_index, bc.start_offset AS chunk_start, bc.end_offset AS chunk_end,
+				bc.score AS chunk_score,
 				CASE
 					WHEN $%d THEN 0::float8
-					ELSE ts_rank(
-						to_tsvector('english', COALESCE(m.title, '') || ' ' || COALESCE(m.content, '')),
-						plainto_tsquery('english', $%d)
-					)::float8
-				END AS keyword_score
+					ELSE ts_rank(m.search_vector, plainto_tsquery(m.language, $%d))::float8
+				END AS keyword_score,
+				CASE
+					WHEN qs.n = 0 OR (cardinality(qs.prefixes) = 0 AND NOT m.symbols && qs.exact) THEN '{}'::text[]
+					ELSE ARRAY(
+						SELECT s FROM unnest(m.symbols) s
+						WHERE s = ANY(qs.exact) OR EXISTS (SELECT 1 FROM unnest(qs.prefixes) p WHERE starts_with(s, p))
+						ORDER BY s
+					)
+				END AS matched_symbols,
+				CASE
+					WHEN qs.n = 0 OR (cardinality(qs.prefixes) = 0 AND NOT m.symbols && qs.exact) THEN 0::float8
+					ELSE (
+						cardinality(ARRAY(SELECT unnest(m.symbols) INTERSECT SELECT unnest(qs.exact)))
+						+ (SELECT count(*) FROM unnest(qs.prefixes) p WHERE EXISTS (SELECT 1 FROM unnest(m.symbols) s WHERE starts_with(s, p)))
+					)::float8 / qs.n
+				END AS symbol_score
 			FROM memories m
+			CROSS JOIN query_symbols qs
+			%s
 			%s
 		),
-		candidates AS (
-			SELECT *
-			FROM base
-			ORDER BY
-				CASE WHEN $%d THEN 0 ELSE keyword_score END DESC,
-				vector_score DESC,
-				updated_at DESC
-			LIMIT $%d
-		),
-		reranked AS (
-			SELECT c.*, (%s) AS relevance_boost
-			FROM candidates c
+		ranked AS (
+			SELECT
+				b.*,
+				ROW_NUMBER() OVER (ORDER BY b.vector_score DESC, b.updated_at DESC) AS vector_rank,
+				CASE
+					WHEN b.keyword_score > 0
+					THEN ROW_NUMBER() OVER (ORDER BY b.keyword_score DESC, b.updated_at DESC)
+					ELSE 0
+				END AS keyword_rank,
+				CASE
+					WHEN b.symbol_score > 0
+					THEN ROW_NUMBER() OVER (ORDER BY b.symbol_score DESC, b.updated_at DESC)
+					ELSE 0
+				END AS symbol_rank
+			FROM base b
 		)
 		SELECT
-			r.id, r.title, r.content, r.summary, r.type, r.scope, r.project_id,
-			r.agent_source, r.tags, r.importance, r.ttl_seconds, r.access_count,
-			r.created_at, r.updated_at, r.expires_at,
-			COALESCE(r.vector_score, 0) * $%d + COALESCE(r.keyword_score, 0) * $%d + r.relevance_boost AS combined_score
-		FROM reranked r
-		ORDER BY combined_score DESC, r.updated_at DESC
-		LIMIT $%d OFFSET $%d
-	`, isBroadArgIdx, queryArgIdx, whereClause, isBroadArgIdx, candidateLimitArgIdx, boostExpr, vwArgIdx, kwArgIdx, limitArgIdx, offsetArgIdx)
+			c.id, c.title, c.content, c.summary, c.type, c.scope, c.project_id,
+			c.agent_source, c.language, c.tags, c.importance, c.ttl_seconds, c.access_count,
+			c.created_at, c.updated_at, c.expires_at,
+			COALESCE(c.vector_score, 0), COALESCE(c.keyword_score, 0),
+			c.vector_rank, c.keyword_rank,
+			c.symbol_score, c.symbol_rank, c.matched_symbols,
+			c.chunk_index, c.chunk_start, c.chunk_end, c.chunk_score,
+			(%s)::float8 AS project_boost,
+			(%s)::float8 AS tag_boost
+		FROM ranked c
+		WHERE c.vector_rank <= $%d
+		   OR (c.keyword_rank > 0 AND c.keyword_rank <= $%d)
+		   OR (c.symbol_rank > 0 AND c.symbol_rank <= $%d)
+	`, exactArgIdx, isBroadArgIdx, queryArgIdx, prefixArgIdx,
+		isBroadArgIdx, queryArgIdx, chunkJoin, whereClause, projectBoostExpr, tagBoostExpr,
+		candidateLimitArgIdx, candidateLimitArgIdx, candidateLimitArgIdx)
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -315,39 +406,108 @@ func (r *Repository) HybridSearch(ctx context.Context, queryEmbedding pgvector.V
 	}
 	defer rows.Close()
 
-	var results []SearchResult
+	var candidates []SearchCandidate
 	for rows.Next() {
-		var sr SearchResult
+		var c SearchCandidate
+		var chunkIndex, chunkStart, chunkEnd *int
+		var chunkScore *float64
 		err := rows.Scan(
-			&sr.Memory.ID, &sr.Memory.Title, &sr.Memory.Content, &sr.Memory.Summary,
-			&sr.Memory.Type, &sr.Memory.Scope, &sr.Memory.ProjectID,
-			&sr.Memory.AgentSource, &sr.Memory.Tags, &sr.Memory.Importance,
-			&sr.Memory.TTLSeconds, &sr.Memory.AccessCount,
-			&sr.Memory.CreatedAt, &sr.Memory.UpdatedAt, &sr.Memory.ExpiresAt,
-			&sr.Score,
+			&c.Memory.ID, &c.Memory.Title, &c.Memory.Content, &c.Memory.Summary,
+			&c.Memory.Type, &c.Memory.Scope, &c.Memory.ProjectID,
+			&c.Memory.AgentSource, &c.Memory.Language, &c.Memory.Tags, &c.Memory.Importance,
+			&c.Memory.TTLSeconds, &c.Memory.AccessCount,
+			&c.Memory.CreatedAt, &c.Memory.UpdatedAt, &c.Memory.ExpiresAt,
+			&c.VectorScore, &c.KeywordScore,
+			&c.VectorRank, &c.KeywordRank,
+			&c.SymbolScore, &c.SymbolRank, &c.MatchedSymbols,
+			&chunkIndex, &chunkStart, &chunkEnd, &chunkScore,
+			&c.ProjectBoost, &c.TagBoost,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("scan search result: %w", err)
+			return nil, fmt.Errorf("scan search candidate: %w", err)
 		}
-		sr.MatchType = "hybrid"
-		results = append(results, sr)
+		// The chunk is only reported when it, not the whole memory, supplied
+		// the vector score.
+		if chunkScore != nil && *chunkScore >= c.VectorScore {
+			c.Chunk = &ChunkMatch{
+				Chunk: Chunk{Index: *chunkIndex, Start: *chunkStart, End: *chunkEnd},
+				Score: *chunkScore,
+			}
+		}
+		c.Boost = c.ProjectBoost + c.TagBoost
+		candidates = append(candidates, c)
 	}
 
-	return results, rows.Err()
+	return candidates, rows.Err()
 }
 
-func (r *Repository) IncrementAccess(ctx context.Context, id uuid.UUID, ttlExtendFactor float64) error {
+// headlineOptions configures ts_headline for search highlights. Fragments
+// are joined with an ASCII record separator so they can be split apart.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=24, MinWords=8, MaxFragments=3, FragmentDelimiter=\"\x1e\""
+
+// searchHighlight is the keyword evidence for one search result.
+type searchHighlight struct {
+	lexemes    []string
+	highlights []string
+}
+
+// SearchHighlights returns, for each of ids, the query lexemes found in the
+// memory's title or content and ts_headline snippets of the content around
+// them. Memories without a keyword match are omitted.
+func (r *Repository) SearchHighlights(ctx context.Context, ids []uuid.UUID, query string) (map[uuid.UUID]searchHighlight, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			m.id,
+			ARRAY(
+				SELECT l FROM (
+					SELECT unnest(tsvector_to_array(m.search_vector))
+					INTERSECT
+					SELECT unnest(tsvector_to_array(to_tsvector(m.language, $3)))
+				) matched(l)
+				ORDER BY l
+			) AS lexemes,
+			ts_headline(m.language, COALESCE(m.content, ''), plainto_tsquery(m.language, $3), $4) AS headline
+		FROM memories m
+		WHERE m.id = ANY($1) AND m.workspace_id = $2
+		  AND m.search_vector @@ plainto_tsquery(m.language, $3)
+	`, ids, WorkspaceFromContext(ctx), query, headlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("search highlights: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[uuid.UUID]searchHighlight, len(ids))
+	for rows.Next() {
+		var id uuid.UUID
+		var h searchHighlight
+		var headline string
+		if err := rows.Scan(&id, &h.lexemes, &headline); err != nil {
+			return nil, fmt.Errorf("scan search highlight: %w", err)
+		}
+		for _, frag := range strings.Split(headline, "\x1e") {
+			if frag = strings.TrimSpace(frag); frag != "" {
+				h.highlights = append(h.highlights, frag)
+			}
+		}
+		out[id] = h
+	}
+	return out, rows.Err()
+}
+
+// IncrementAccess counts a read of a memory and, for a short-term memory,
+// moves its expiry to expiresAt when that is not nil.
+func (r *Repository) IncrementAccess(ctx context.Context, id uuid.UUID, expiresAt *time.Time) error {
 	query := `
 		UPDATE memories
 		SET access_count = access_count + 1,
+		    last_accessed_at = NOW(),
 		    expires_at = CASE
-		        WHEN ttl_seconds IS NOT NULL AND expires_at IS NOT NULL
-		        THEN expires_at + (ttl_seconds * $2 || ' seconds')::INTERVAL
+		        WHEN ttl_seconds IS NOT NULL THEN COALESCE($2, expires_at)
 		        ELSE expires_at
 		    END
 		WHERE id = $1
 	`
-	_, err := r.pool.Exec(ctx, query, id, ttlExtendFactor)
+	_, err := r.pool.Exec(ctx, query, id, expiresAt)
 	if err != nil {
 		return fmt.Errorf("increment access: %w", err)
 	}
@@ -355,7 +515,7 @@ func (r *Repository) IncrementAccess(ctx context.Context, id uuid.UUID, ttlExten
 }
 
 func (r *Repository) PromoteToLongTerm(ctx context.Context, id uuid.UUID) error {
-	result, err := r.pool.Exec(ctx, "UPDATE memories SET ttl_seconds = NULL, expires_at = NULL WHERE id = $1", id)
+	result, err := r.pool.Exec(ctx, "UPDATE memories SET ttl_seconds = NULL, expires_at = NULL WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL", id, WorkspaceFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("promote to long-term: %w", err)
 	}
@@ -365,23 +525,15 @@ func (r *Repository) PromoteToLongTerm(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
-func (r *Repository) DeleteExpired(ctx context.Context) (int64, error) {
-	result, err := r.pool.Exec(ctx, "DELETE FROM memories WHERE expires_at IS NOT NULL AND expires_at < NOW()")
-	if err != nil {
-		return 0, fmt.Errorf("delete expired: %w", err)
-	}
-	return result.RowsAffected(), nil
-}
-
 // StoreRelationship creates a relationship between two memories.
 func (r *Repository) StoreRelationship(ctx context.Context, rel *Relationship) error {
 	query := `
-		INSERT INTO memory_relationships (id, from_memory_id, to_memory_id, relationship, strength, context)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO memory_relationships (id, from_memory_id, to_memory_id, relationship, strength, context, workspace_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (from_memory_id, to_memory_id, relationship) DO UPDATE
 		SET strength = $5, context = $6
 	`
-	_, err := r.pool.Exec(ctx, query, rel.ID, rel.FromMemoryID, rel.ToMemoryID, rel.Relationship, rel.Strength, rel.Context)
+	_, err := r.pool.Exec(ctx, query, rel.ID, rel.FromMemoryID, rel.ToMemoryID, rel.Relationship, rel.Strength, rel.Context, WorkspaceFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("store relationship: %w", err)
 	}
@@ -390,17 +542,17 @@ func (r *Repository) StoreRelationship(ctx context.Context, rel *Relationship) e
 
 // GetRelated returns memories related to the given memory ID.
 func (r *Repository) GetRelated(ctx context.Context, memoryID uuid.UUID, relationshipTypes []string) ([]Memory, []Relationship, error) {
-	conditions := "WHERE r.from_memory_id = $1 OR r.to_memory_id = $1"
-	args := []any{memoryID}
+	conditions := "WHERE (r.from_memory_id = $1 OR r.to_memory_id = $1) AND m.workspace_id = $2 AND m.deleted_at IS NULL"
+	args := []any{memoryID, WorkspaceFromContext(ctx)}
 
 	if len(relationshipTypes) > 0 {
-		conditions += " AND r.relationship = ANY($2)"
+		conditions += " AND r.relationship = ANY($3)"
 		args = append(args, relationshipTypes)
 	}
 
 	query := fmt.Sprintf(`
 		SELECT m.id, m.title, m.content, m.summary, m.type, m.scope, m.project_id,
-		       m.agent_source, m.tags, m.importance, m.ttl_seconds, m.access_count,
+		       m.agent_source, m.language::text, m.tags, m.importance, m.ttl_seconds, m.access_count,
 		       m.created_at, m.updated_at, m.expires_at,
 		       r.id, r.from_memory_id, r.to_memory_id, r.relationship, r.strength, r.context, r.created_at
 		FROM memory_relationships r
@@ -422,7 +574,7 @@ func (r *Repository) GetRelated(ctx context.Context, memoryID uuid.UUID, relatio
 		var rel Relationship
 		err := rows.Scan(
 			&m.ID, &m.Title, &m.Content, &m.Summary, &m.Type, &m.Scope, &m.ProjectID,
-			&m.AgentSource, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
+			&m.AgentSource, &m.Language, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
 			&m.CreatedAt, &m.UpdatedAt, &m.ExpiresAt,
 			&rel.ID, &rel.FromMemoryID, &rel.ToMemoryID, &rel.Relationship, &rel.Strength, &rel.Context, &rel.CreatedAt,
 		)
@@ -443,15 +595,16 @@ func (r *Repository) GetStats(ctx context.Context) (*Stats, error) {
 		ByScope: make(map[string]int),
 		ByAgent: make(map[string]int),
 	}
+	ws := WorkspaceFromContext(ctx)
 
 	// Total active count (exclude replaced)
-	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE replaced_by IS NULL").Scan(&stats.TotalMemories)
+	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE replaced_by IS NULL AND deleted_at IS NULL AND workspace_id = $1", ws).Scan(&stats.TotalMemories)
 	if err != nil {
 		return nil, fmt.Errorf("count memories: %w", err)
 	}
 
 	// By type
-	rows, err := r.pool.Query(ctx, "SELECT type, COUNT(*) FROM memories WHERE replaced_by IS NULL GROUP BY type")
+	rows, err := r.pool.Query(ctx, "SELECT type, COUNT(*) FROM memories WHERE replaced_by IS NULL AND deleted_at IS NULL AND workspace_id = $1 GROUP BY type", ws)
 	if err != nil {
 		return nil, fmt.Errorf("count by type: %w", err)
 	}
@@ -471,7 +624,7 @@ func (r *Repository) GetStats(ctx context.Context) (*Stats, error) {
 	rows.Close()
 
 	// By scope
-	rows, err = r.pool.Query(ctx, "SELECT scope, COUNT(*) FROM memories WHERE replaced_by IS NULL GROUP BY scope")
+	rows, err = r.pool.Query(ctx, "SELECT scope, COUNT(*) FROM memories WHERE replaced_by IS NULL AND deleted_at IS NULL AND workspace_id = $1 GROUP BY scope", ws)
 	if err != nil {
 		return nil, fmt.Errorf("count by scope: %w", err)
 	}
@@ -491,7 +644,7 @@ func (r *Repository) GetStats(ctx context.Context) (*Stats, error) {
 	rows.Close()
 
 	// By agent
-	rows, err = r.pool.Query(ctx, "SELECT COALESCE(agent_source, 'unknown'), COUNT(*) FROM memories WHERE replaced_by IS NULL GROUP BY agent_source")
+	rows, err = r.pool.Query(ctx, "SELECT COALESCE(agent_source, 'unknown'), COUNT(*) FROM memories WHERE replaced_by IS NULL AND deleted_at IS NULL AND workspace_id = $1 GROUP BY agent_source", ws)
 	if err != nil {
 		return nil, fmt.Errorf("count by agent: %w", err)
 	}
@@ -511,23 +664,28 @@ func (r *Repository) GetStats(ctx context.Context) (*Stats, error) {
 	rows.Close()
 
 	// Long-term vs short-term
-	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE ttl_seconds IS NULL AND replaced_by IS NULL").Scan(&stats.LongTermCount); err != nil {
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE ttl_seconds IS NULL AND replaced_by IS NULL AND deleted_at IS NULL AND workspace_id = $1", ws).Scan(&stats.LongTermCount); err != nil {
 		return nil, fmt.Errorf("count long-term memories: %w", err)
 	}
-	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE ttl_seconds IS NOT NULL AND replaced_by IS NULL").Scan(&stats.ShortTermCount); err != nil {
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE ttl_seconds IS NOT NULL AND replaced_by IS NULL AND deleted_at IS NULL AND workspace_id = $1", ws).Scan(&stats.ShortTermCount); err != nil {
 		return nil, fmt.Errorf("count short-term memories: %w", err)
 	}
 
 	// Expiring soon (next hour)
-	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE expires_at IS NOT NULL AND expires_at < NOW() + INTERVAL '1 hour' AND replaced_by IS NULL").Scan(&stats.ExpiringCount); err != nil {
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE expires_at IS NOT NULL AND expires_at < NOW() + INTERVAL '1 hour' AND replaced_by IS NULL AND deleted_at IS NULL AND workspace_id = $1", ws).Scan(&stats.ExpiringCount); err != nil {
 		return nil, fmt.Errorf("count expiring memories: %w", err)
 	}
 
 	// Pending consolidation suggestions
-	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM consolidation_suggestions WHERE status = 'pending'").Scan(&stats.PendingSuggestions); err != nil {
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM consolidation_suggestions WHERE status = 'pending' AND workspace_id = $1", ws).Scan(&stats.PendingSuggestions); err != nil {
 		return nil, fmt.Errorf("count pending suggestions: %w", err)
 	}
 
+	// In the trash
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories WHERE deleted_at IS NOT NULL AND workspace_id = $1", ws).Scan(&stats.TrashedCount); err != nil {
+		return nil, fmt.Errorf("count trashed memories: %w", err)
+	}
+
 	return stats, nil
 }
 
@@ -538,15 +696,17 @@ func (r *Repository) ListByProject(ctx context.Context, projectID string, limit
 	}
 	query := `
 		SELECT id, title, content, summary, type, scope, project_id, agent_source,
-		       tags, importance, ttl_seconds, access_count, created_at, updated_at, expires_at
+		       language::text, tags, importance, ttl_seconds, access_count, created_at, updated_at, expires_at
 		FROM memories
-		WHERE (project_id = $1 OR scope = 'global')
+		WHERE workspace_id = $3
+		  AND (project_id = $1 OR scope = 'global')
 		  AND (expires_at IS NULL OR expires_at > NOW())
 		  AND replaced_by IS NULL
+		  AND deleted_at IS NULL
 		ORDER BY importance DESC, updated_at DESC
 		LIMIT $2
 	`
-	rows, err := r.pool.Query(ctx, query, projectID, limit)
+	rows, err := r.pool.Query(ctx, query, projectID, limit, WorkspaceFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("list by project: %w", err)
 	}
@@ -557,7 +717,7 @@ func (r *Repository) ListByProject(ctx context.Context, projectID string, limit
 		var m Memory
 		err := rows.Scan(
 			&m.ID, &m.Title, &m.Content, &m.Summary, &m.Type, &m.Scope, &m.ProjectID,
-			&m.AgentSource, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
+			&m.AgentSource, &m.Language, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
 			&m.CreatedAt, &m.UpdatedAt, &m.ExpiresAt,
 		)
 		if err != nil {
@@ -574,13 +734,15 @@ func (r *Repository) ListByProject(ctx context.Context, projectID string, limit
 // FindSimilar finds memories with embedding similarity above a threshold.
 func (r *Repository) FindSimilar(ctx context.Context, embedding pgvector.Vector, projectID *string, threshold float64, limit int) ([]SimilarMemory, error) {
 	conditions := []string{
+		"m.workspace_id = $3",
 		"m.replaced_by IS NULL",
+		"m.deleted_at IS NULL",
 		"m.embedding IS NOT NULL",
 		"(m.expires_at IS NULL OR m.expires_at > NOW())",
 		fmt.Sprintf("1 - (m.embedding <=> $1) >= $%d", 2),
 	}
-	args := []any{embedding, threshold}
-	argIdx := 3
+	args := []any{embedding, threshold, WorkspaceFromContext(ctx)}
+	argIdx := 4
 
 	if projectID != nil {
 		conditions = append(conditions, fmt.Sprintf("(m.project_id = $%d OR m.scope = 'global')", argIdx))
@@ -595,7 +757,7 @@ func (r *Repository) FindSimilar(ctx context.Context, embedding pgvector.Vector,
 
 	query := fmt.Sprintf(`
 		SELECT m.id, m.title, m.content, m.summary, m.type, m.scope, m.project_id,
-		       m.agent_source, m.tags, m.importance, m.ttl_seconds, m.access_count,
+		       m.agent_source, m.language::text, m.tags, m.importance, m.ttl_seconds, m.access_count,
 		       m.created_at, m.updated_at, m.expires_at,
 		       1 - (m.embedding <=> $1) AS similarity
 		FROM memories m
@@ -616,7 +778,7 @@ func (r *Repository) FindSimilar(ctx context.Context, embedding pgvector.Vector,
 		err := rows.Scan(
 			&sm.Memory.ID, &sm.Memory.Title, &sm.Memory.Content, &sm.Memory.Summary,
 			&sm.Memory.Type, &sm.Memory.Scope, &sm.Memory.ProjectID,
-			&sm.Memory.AgentSource, &sm.Memory.Tags, &sm.Memory.Importance,
+			&sm.Memory.AgentSource, &sm.Memory.Language, &sm.Memory.Tags, &sm.Memory.Importance,
 			&sm.Memory.TTLSeconds, &sm.Memory.AccessCount,
 			&sm.Memory.CreatedAt, &sm.Memory.UpdatedAt, &sm.Memory.ExpiresAt,
 			&sm.Similarity,
@@ -643,14 +805,15 @@ func (r *Repository) MarkReplaced(ctx context.Context, sourceID, targetID uuid.U
 }
 
 // UpdateMergeFields updates version, merged_from, and re-embeds a merged memory.
-func (r *Repository) UpdateMergeFields(ctx context.Context, id uuid.UUID, title, content string, tags []string, importance float32, mergedFrom []uuid.UUID, newEmbedding *pgvector.Vector) error {
+func (r *Repository) UpdateMergeFields(ctx context.Context, id uuid.UUID, title, content string, tags []string, importance float32, mergedFrom []uuid.UUID, newEmbedding *pgvector.Vector, embeddingModel string) error {
 	query := `
 		UPDATE memories
 		SET title = $2, content = $3, tags = $4, importance = $5,
-		    merged_from = $6, version = version + 1, embedding = $7
+		    merged_from = $6, version = version + 1, embedding = $7, embedding_model = $8,
+		    embedding_next = NULL, embedding_next_model = NULL
 		WHERE id = $1
 	`
-	_, err := r.pool.Exec(ctx, query, id, title, content, tags, importance, mergedFrom, newEmbedding)
+	_, err := r.pool.Exec(ctx, query, id, title, content, tags, importance, mergedFrom, newEmbedding, embeddingModel)
 	if err != nil {
 		return fmt.Errorf("update merge fields: %w", err)
 	}
@@ -672,18 +835,18 @@ func (r *Repository) CleanupReplaced(ctx context.Context, retention time.Duratio
 }
 
 // StoreSuggestion inserts a consolidation suggestion, ignoring duplicates.
-func (r *Repository) StoreSuggestion(ctx context.Context, memAID, memBID uuid.UUID, similarity float64, projectID *string) error {
+func (r *Repository) StoreSuggestion(ctx context.Context, memAID, memBID uuid.UUID, similarity float64, projectID *string, workspaceID string) error {
 	// Normalize order to avoid duplicates (smaller UUID first)
 	a, b := memAID, memBID
 	if a.String() > b.String() {
 		a, b = b, a
 	}
 	query := `
-		INSERT INTO consolidation_suggestions (memory_a_id, memory_b_id, similarity, project_id)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO consolidation_suggestions (memory_a_id, memory_b_id, similarity, project_id, workspace_id)
+		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (memory_a_id, memory_b_id) DO UPDATE SET similarity = GREATEST(consolidation_suggestions.similarity, $3)
 	`
-	_, err := r.pool.Exec(ctx, query, a, b, similarity, projectID)
+	_, err := r.pool.Exec(ctx, query, a, b, similarity, projectID, workspaceID)
 	if err != nil {
 		return fmt.Errorf("store suggestion: %w", err)
 	}
@@ -691,7 +854,10 @@ func (r *Repository) StoreSuggestion(ctx context.Context, memAID, memBID uuid.UU
 }
 
 // GetSuggestions returns consolidation suggestions with their associated memories.
-func (r *Repository) GetSuggestions(ctx context.Context, projectID *string, status string, limit, offset int) ([]ConsolidationSuggestion, int, error) {
+// GetSuggestions lists suggestions by similarity, best first, with the total
+// matching count. With after set, the page starts after that position and
+// offset should be 0; the total still counts every match.
+func (r *Repository) GetSuggestions(ctx context.Context, projectID *string, status string, limit, offset int, after *SuggestionCursor) ([]ConsolidationSuggestion, int, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -699,9 +865,12 @@ func (r *Repository) GetSuggestions(ctx context.Context, projectID *string, stat
 		status = "pending"
 	}
 
-	conditions := []string{"s.status = $1"}
-	args := []any{status}
-	argIdx := 2
+	conditions := []string{
+		"s.status = $1", "s.workspace_id = $2",
+		"NOT EXISTS (SELECT 1 FROM memories t WHERE t.id IN (s.memory_a_id, s.memory_b_id) AND t.deleted_at IS NOT NULL)",
+	}
+	args := []any{status, WorkspaceFromContext(ctx)}
+	argIdx := 3
 
 	if projectID != nil {
 		conditions = append(conditions, fmt.Sprintf("s.project_id = $%d", argIdx))
@@ -720,6 +889,12 @@ func (r *Repository) GetSuggestions(ctx context.Context, projectID *string, stat
 		return nil, 0, fmt.Errorf("count suggestions: %w", err)
 	}
 
+	if after != nil {
+		where += fmt.Sprintf(" AND (s.similarity < $%d::real OR (s.similarity = $%d::real AND s.id > $%d))", argIdx, argIdx, argIdx+1)
+		args = append(args, after.Similarity, after.ID)
+		argIdx += 2
+	}
+
 	args = append(args, limit, offset)
 	query := fmt.Sprintf(`
 		SELECT s.id, s.memory_a_id, s.memory_b_id, s.similarity, s.status, s.project_id, s.created_at, s.resolved_at,
@@ -729,7 +904,7 @@ func (r *Repository) GetSuggestions(ctx context.Context, projectID *string, stat
 		JOIN memories a ON a.id = s.memory_a_id
 		JOIN memories b ON b.id = s.memory_b_id
 		WHERE %s
-		ORDER BY s.similarity DESC
+		ORDER BY s.similarity DESC, s.id
 		LIMIT $%d OFFSET $%d
 	`, where, argIdx, argIdx+1)
 
@@ -762,8 +937,8 @@ func (r *Repository) GetSuggestions(ctx context.Context, projectID *string, stat
 // UpdateSuggestionStatus updates a suggestion's status to accepted or dismissed.
 func (r *Repository) UpdateSuggestionStatus(ctx context.Context, id uuid.UUID, status string) error {
 	_, err := r.pool.Exec(ctx,
-		"UPDATE consolidation_suggestions SET status = $2, resolved_at = NOW() WHERE id = $1",
-		id, status,
+		"UPDATE consolidation_suggestions SET status = $2, resolved_at = NOW() WHERE id = $1 AND workspace_id = $3",
+		id, status, WorkspaceFromContext(ctx),
 	)
 	if err != nil {
 		return fmt.Errorf("update suggestion status: %w", err)
@@ -774,12 +949,13 @@ func (r *Repository) UpdateSuggestionStatus(ctx context.Context, id uuid.UUID, s
 // StoreConsolidationLog records a merge operation.
 func (r *Repository) StoreConsolidationLog(ctx context.Context, log *ConsolidationLog) error {
 	query := `
-		INSERT INTO consolidation_log (id, target_id, source_ids, merge_strategy, similarity_score, content_before, content_after, performed_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO consolidation_log (id, target_id, source_ids, merge_strategy, similarity_score, content_before, content_after, performed_by, reverts_id, workspace_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	_, err := r.pool.Exec(ctx, query,
 		log.ID, log.TargetID, log.SourceIDs, log.MergeStrategy,
-		log.SimilarityScore, log.ContentBefore, log.ContentAfter, log.PerformedBy,
+		log.SimilarityScore, log.ContentBefore, log.ContentAfter, log.PerformedBy, log.RevertsID,
+		WorkspaceFromContext(ctx),
 	)
 	if err != nil {
 		return fmt.Errorf("store consolidation log: %w", err)
@@ -793,9 +969,9 @@ func (r *Repository) GetConsolidationLog(ctx context.Context, targetID *uuid.UUI
 		limit = 20
 	}
 
-	conditions := []string{}
-	args := []any{}
-	argIdx := 1
+	conditions := []string{"workspace_id = $1"}
+	args := []any{WorkspaceFromContext(ctx)}
+	argIdx := 2
 
 	if targetID != nil {
 		conditions = append(conditions, fmt.Sprintf("target_id = $%d", argIdx))
@@ -803,14 +979,12 @@ func (r *Repository) GetConsolidationLog(ctx context.Context, targetID *uuid.UUI
 		argIdx++
 	}
 
-	where := ""
-	if len(conditions) > 0 {
-		where = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
 
 	args = append(args, limit, offset)
 	query := fmt.Sprintf(`
-		SELECT id, target_id, source_ids, merge_strategy, similarity_score, content_before, content_after, performed_by, created_at
+		SELECT id, target_id, source_ids, merge_strategy, similarity_score, content_before, content_after, performed_by, created_at,
+		       reverted_at, reverts_id
 		FROM consolidation_log
 		%s
 		ORDER BY created_at DESC
@@ -829,6 +1003,7 @@ func (r *Repository) GetConsolidationLog(ctx context.Context, targetID *uuid.UUI
 		err := rows.Scan(
 			&l.ID, &l.TargetID, &l.SourceIDs, &l.MergeStrategy,
 			&l.SimilarityScore, &l.ContentBefore, &l.ContentAfter, &l.PerformedBy, &l.CreatedAt,
+			&l.RevertedAt, &l.RevertsID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan consolidation log: %w", err)
@@ -840,23 +1015,27 @@ func (r *Repository) GetConsolidationLog(ctx context.Context, targetID *uuid.UUI
 }
 
 // ScanDuplicates finds memory pairs with high vector similarity for a project.
+// Pairs never cross workspaces.
 func (r *Repository) ScanDuplicates(ctx context.Context, threshold float64, batchSize int) ([]struct {
-	MemAID     uuid.UUID
-	MemBID     uuid.UUID
-	Similarity float64
-	ProjectID  *string
+	MemAID      uuid.UUID
+	MemBID      uuid.UUID
+	Similarity  float64
+	ProjectID   *string
+	WorkspaceID string
 }, error) {
 	if batchSize <= 0 {
 		batchSize = 100
 	}
 	query := `
-		SELECT m1.id, m2.id, 1 - (m1.embedding <=> m2.embedding) AS similarity, m1.project_id
+		SELECT m1.id, m2.id, 1 - (m1.embedding <=> m2.embedding) AS similarity, m1.project_id, m1.workspace_id
 		FROM memories m1
 		CROSS JOIN LATERAL (
 			SELECT m2.id, m2.embedding
 			FROM memories m2
 			WHERE m2.id > m1.id
+			  AND m2.workspace_id = m1.workspace_id
 			  AND m2.replaced_by IS NULL
+			  AND m2.deleted_at IS NULL
 			  AND m2.embedding IS NOT NULL
 			  AND (m2.expires_at IS NULL OR m2.expires_at > NOW())
 			  AND (m2.project_id = m1.project_id OR (m2.project_id IS NULL AND m1.project_id IS NULL))
@@ -865,6 +1044,7 @@ func (r *Repository) ScanDuplicates(ctx context.Context, threshold float64, batc
 			LIMIT 3
 		) m2
 		WHERE m1.replaced_by IS NULL
+		  AND m1.deleted_at IS NULL
 		  AND m1.embedding IS NOT NULL
 		  AND (m1.expires_at IS NULL OR m1.expires_at > NOW())
 		LIMIT $2
@@ -877,15 +1057,16 @@ func (r *Repository) ScanDuplicates(ctx context.Context, threshold float64, batc
 	defer rows.Close()
 
 	type pair struct {
-		MemAID     uuid.UUID
-		MemBID     uuid.UUID
-		Similarity float64
-		ProjectID  *string
+		MemAID      uuid.UUID
+		MemBID      uuid.UUID
+		Similarity  float64
+		ProjectID   *string
+		WorkspaceID string
 	}
 	var pairs []pair
 	for rows.Next() {
 		var p pair
-		if err := rows.Scan(&p.MemAID, &p.MemBID, &p.Similarity, &p.ProjectID); err != nil {
+		if err := rows.Scan(&p.MemAID, &p.MemBID, &p.Similarity, &p.ProjectID, &p.WorkspaceID); err != nil {
 			return nil, fmt.Errorf("scan duplicate pair: %w", err)
 		}
 		pairs = append(pairs, p)
@@ -893,17 +1074,19 @@ func (r *Repository) ScanDuplicates(ctx context.Context, threshold float64, batc
 
 	// Convert to return type
 	result := make([]struct {
-		MemAID     uuid.UUID
-		MemBID     uuid.UUID
-		Similarity float64
-		ProjectID  *string
+		MemAID      uuid.UUID
+		MemBID      uuid.UUID
+		Similarity  float64
+		ProjectID   *string
+		WorkspaceID string
 	}, len(pairs))
 	for i, p := range pairs {
 		result[i] = struct {
-			MemAID     uuid.UUID
-			MemBID     uuid.UUID
-			Similarity float64
-			ProjectID  *string
+			MemAID      uuid.UUID
+			MemBID      uuid.UUID
+			Similarity  float64
+			ProjectID   *string
+			WorkspaceID string
 		}(p)
 	}
 
@@ -956,6 +1139,7 @@ func (r *Repository) GetAnalytics(ctx context.Context) (*AnalyticsData, error) {
 	data := &AnalyticsData{
 		TokensByAgent: make(map[string]int64),
 	}
+	ws := WorkspaceFromContext(ctx)
 
 	// Token metrics + hit rate
 	err := r.pool.QueryRow(ctx, `
@@ -968,7 +1152,8 @@ func (r *Repository) GetAnalytics(ctx context.Context) (*AnalyticsData, error) {
 				ELSE 0
 			END
 		FROM memories
-	`).Scan(&data.TotalTokensStored, &data.TotalTokensSaved, &data.TotalHits, &data.HitRate)
+		WHERE workspace_id = $1 AND deleted_at IS NULL
+	`, ws).Scan(&data.TotalTokensStored, &data.TotalTokensSaved, &data.TotalHits, &data.HitRate)
 	if err != nil {
 		return nil, fmt.Errorf("analytics token metrics: %w", err)
 	}
@@ -977,10 +1162,10 @@ func (r *Repository) GetAnalytics(ctx context.Context) (*AnalyticsData, error) {
 	rows, err := r.pool.Query(ctx, `
 		SELECT id, title, type, access_count, LENGTH(content)/4, agent_source
 		FROM memories
-		WHERE access_count > 0
+		WHERE access_count > 0 AND workspace_id = $1 AND deleted_at IS NULL
 		ORDER BY access_count DESC
 		LIMIT 10
-	`)
+	`, ws)
 	if err != nil {
 		return nil, fmt.Errorf("analytics top accessed: %w", err)
 	}
@@ -1003,9 +1188,10 @@ func (r *Repository) GetAnalytics(ctx context.Context) (*AnalyticsData, error) {
 	rows, err = r.pool.Query(ctx, `
 		SELECT COALESCE(agent_source, 'unknown'), COALESCE(SUM(LENGTH(content) * access_count / 4), 0)
 		FROM memories
+		WHERE workspace_id = $1 AND deleted_at IS NULL
 		GROUP BY agent_source
 		HAVING SUM(LENGTH(content) * access_count / 4) > 0
-	`)
+	`, ws)
 	if err != nil {
 		return nil, fmt.Errorf("analytics tokens by agent: %w", err)
 	}
@@ -1023,10 +1209,10 @@ func (r *Repository) GetAnalytics(ctx context.Context) (*AnalyticsData, error) {
 			COALESCE(COUNT(m.id), 0),
 			COALESCE(SUM(m.access_count), 0)
 		FROM generate_series(NOW() - INTERVAL '29 days', NOW(), '1 day') d
-		LEFT JOIN memories m ON m.created_at::date = d::date
+		LEFT JOIN memories m ON m.created_at::date = d::date AND m.workspace_id = $1
 		GROUP BY d::date
 		ORDER BY d::date
-	`)
+	`, ws)
 	if err != nil {
 		return nil, fmt.Errorf("analytics timeline: %w", err)
 	}
@@ -1057,9 +1243,10 @@ func (r *Repository) GetFunnelAnalytics(ctx context.Context, req FunnelAnalytics
 	conditions := []string{
 		"e.created_at >= $1",
 		"e.created_at < $2",
+		"e.workspace_id = $3",
 	}
-	args := []any{req.From, req.To}
-	argIdx := 3
+	args := []any{req.From, req.To, WorkspaceFromContext(ctx)}
+	argIdx := 4
 
 	if req.AgentSource != nil && *req.AgentSource != "" {
 		conditions = append(conditions, fmt.Sprintf("e.agent_source = $%d", argIdx))
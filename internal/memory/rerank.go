@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/atakanatali/contextify/internal/steward/llm"
+)
+
+// rerankContentChars caps how much of each memory the reranker sees.
+const rerankContentChars = 800
+
+// RerankDocument is a search result handed to a Reranker.
+type RerankDocument struct {
+	Title   string
+	Content string
+}
+
+// Reranker rescores the top search results against the query. It returns a
+// relevance in [0,1] per document, or a negative value for a document it
+// could not score.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, docs []RerankDocument) ([]float64, error)
+}
+
+// LLMReranker reranks with a local Ollama chat model.
+type LLMReranker struct {
+	client *llm.Client
+}
+
+func NewLLMReranker(client *llm.Client) *LLMReranker {
+	return &LLMReranker{client: client}
+}
+
+func (r *LLMReranker) Rerank(ctx context.Context, query string, docs []RerankDocument) ([]float64, error) {
+	in := llm.RerankInput{Query: query, Documents: make([]llm.RerankDocument, len(docs))}
+	for i, d := range docs {
+		content := d.Content
+		if len(content) > rerankContentChars {
+			cut := rerankContentChars
+			for cut > 0 && !utf8.RuneStart(content[cut]) {
+				cut--
+			}
+			content = content[:cut]
+		}
+		in.Documents[i] = llm.RerankDocument{ID: strconv.Itoa(i + 1), Title: d.Title, Content: content}
+	}
+	scores, _, err := r.client.Rerank(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(docs))
+	for i := range docs {
+		out[i] = -1
+		if s, ok := scores[strconv.Itoa(i+1)]; ok {
+			out[i] = s
+		}
+	}
+	return out, nil
+}
+
+// SetReranker installs the second-stage reranker. Without one, requests
+// asking for reranking keep the first-stage order.
+func (s *Service) SetReranker(r Reranker) {
+	s.reranker = r
+}
+
+// rerankEnabled resolves a request's rerank flag against the configured
+// default.
+func (s *Service) rerankEnabled(req SearchRequest) bool {
+	if req.Rerank != nil {
+		return *req.Rerank
+	}
+	return s.searchCfg.Rerank.Enabled
+}
+
+// rerank reorders the top search.rerank.top_n results by model relevance,
+// within search.rerank.timeout. Results the model could not score keep their
+// first-stage order after the scored ones. On error the results are left as
+// they were.
+func (s *Service) rerank(ctx context.Context, query string, results []SearchResult) error {
+	if s.reranker == nil {
+		return fmt.Errorf("no reranker configured")
+	}
+	n := s.searchCfg.Rerank.TopN
+	if n <= 0 || n > len(results) {
+		n = len(results)
+	}
+	if n < 2 {
+		return nil
+	}
+	top := results[:n]
+
+	docs := make([]RerankDocument, n)
+	for i, r := range top {
+		docs[i] = RerankDocument{Title: r.Memory.Title, Content: r.Memory.Content}
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.searchCfg.Rerank.Timeout)
+	defer cancel()
+	scores, err := s.reranker.Rerank(ctx, query, docs)
+	if err != nil {
+		return err
+	}
+	if len(scores) != n {
+		return fmt.Errorf("reranker returned %d scores for %d results", len(scores), n)
+	}
+
+	for i := range top {
+		if scores[i] >= 0 {
+			score := scores[i]
+			top[i].RerankScore = &score
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		return rerankKey(top[i]) > rerankKey(top[j])
+	})
+	return nil
+}
+
+func rerankKey(r SearchResult) float64 {
+	if r.RerankScore == nil {
+		return -1
+	}
+	return *r.RerankScore
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+type fakeReranker struct {
+	scores []float64
+	delay  time.Duration
+	calls  int
+}
+
+func (f *fakeReranker) Rerank(ctx context.Context, query string, docs []RerankDocument) ([]float64, error) {
+	f.calls++
+	select {
+	case <-time.After(f.delay):
+		return f.scores[:len(docs)], nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func rerankResults(titles ...string) []SearchResult {
+	out := make([]SearchResult, len(titles))
+	for i, t := range titles {
+		out[i] = SearchResult{Memory: Memory{Title: t}, Score: float64(len(titles) - i)}
+	}
+	return out
+}
+
+func titles(results []SearchResult) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.Memory.Title
+	}
+	return out
+}
+
+func TestRerank_ReordersTopN(t *testing.T) {
+	s := &Service{searchCfg: config.SearchConfig{Rerank: config.SearchRerank{TopN: 3, Timeout: time.Second}}}
+	s.SetReranker(&fakeReranker{scores: []float64{0.1, -1, 0.9}})
+
+	results := rerankResults("a", "b", "c", "d")
+	if err := s.rerank(context.Background(), "q", results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// c scored highest, a scored, b unscored falls behind them; d was never
+	// reranked and stays last.
+	want := []string{"c", "a", "b", "d"}
+	for i, got := range titles(results) {
+		if got != want[i] {
+			t.Fatalf("order = %v, want %v", titles(results), want)
+		}
+	}
+	if results[0].RerankScore == nil || *results[0].RerankScore != 0.9 || results[2].RerankScore != nil {
+		t.Fatalf("unexpected rerank scores: %+v", results)
+	}
+}
+
+func TestRerank_TimeoutKeepsOrder(t *testing.T) {
+	s := &Service{searchCfg: config.SearchConfig{Rerank: config.SearchRerank{TopN: 3, Timeout: 10 * time.Millisecond}}}
+	s.SetReranker(&fakeReranker{scores: []float64{0.1, 0.5, 0.9}, delay: time.Second})
+
+	results := rerankResults("a", "b", "c")
+	if err := s.rerank(context.Background(), "q", results); err == nil {
+		t.Fatalf("expected a timeout error")
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if results[i].Memory.Title != want || results[i].RerankScore != nil {
+			t.Fatalf("first-stage order should be untouched: %v", titles(results))
+		}
+	}
+}
+
+func TestRerankEnabled(t *testing.T) {
+	s := &Service{searchCfg: config.SearchConfig{Rerank: config.SearchRerank{Enabled: true}}}
+	off := false
+	if !s.rerankEnabled(SearchRequest{}) || s.rerankEnabled(SearchRequest{Rerank: &off}) {
+		t.Fatalf("request flag should override the configured default")
+	}
+}
+
+func TestSearchPage_RerankEnabledPagesInScoreOrder(t *testing.T) {
+	s := &Service{searchCfg: config.SearchConfig{Rerank: config.SearchRerank{Enabled: true, TopN: 10, Timeout: time.Second}}}
+	// Applied, these scores would reverse the first-stage order.
+	reranker := &fakeReranker{scores: []float64{0.1, 0.2, 0.3, 0.4, 0.5}}
+	s.SetReranker(reranker)
+
+	now := time.Now()
+	var candidates []SearchCandidate
+	for i := 0; i < 5; i++ {
+		candidates = append(candidates, SearchCandidate{Memory: Memory{ID: uuid.New(), UpdatedAt: now}, VectorScore: 1 - float64(i)/10})
+	}
+	ranker, err := NewRanker(RankingLinear, RankingWeights{Vector: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := SearchRequest{Query: "deploy", Limit: 2, Paginate: true}
+	if s.searchReranks(req) {
+		t.Fatalf("a paginated search must not be reranked")
+	}
+	rerank := false
+	req.Rerank = &rerank
+
+	var got []uuid.UUID
+	var cursor *searchCursor
+	for pages := 1; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("paging did not end after 3 pages")
+		}
+		page, _ := s.rankPage(context.Background(), req, ranker, scoreModifiers{}, candidates, cursor, "q", now)
+		for _, r := range page.Results {
+			got = append(got, r.Memory.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = &searchCursor{}
+		if err := DecodeCursor(page.NextCursor, cursor); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	if len(got) != len(candidates) {
+		t.Fatalf("expected every candidate once across pages, got %d results", len(got))
+	}
+	for i, id := range got {
+		if id != candidates[i].Memory.ID {
+			t.Fatalf("result %d out of score order", i)
+		}
+	}
+	if reranker.calls != 0 {
+		t.Fatalf("reranker called %d times while paging", reranker.calls)
+	}
+
+	req.Paginate, req.Rerank = false, nil
+	if !s.searchReranks(req) {
+		t.Fatalf("an unpaginated search should follow the configured rerank")
+	}
+}
@@ -0,0 +1,345 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+// Duration is a time.Duration that reads and writes JSON as a Go duration
+// string such as "72h".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"72h\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// RetentionRule applies to memories matching all of its non-empty Type,
+// Project and AgentSource. Project is a glob over normalized project IDs in
+// which * matches any run of characters and ? one character. TTL and the
+// promotion thresholds come from the first matching rule that sets them;
+// MaxAge and MaxCount are limits that every matching rule enforces.
+type RetentionRule struct {
+	Type        MemoryType `json:"type,omitempty"`
+	Project     string     `json:"project,omitempty"`
+	AgentSource string     `json:"agent_source,omitempty"`
+
+	// TTL is the default TTL of new short-term memories.
+	TTL                Duration `json:"ttl,omitempty"`
+	PromoteAccessCount int      `json:"promote_access_count,omitempty"`
+	PromoteImportance  *float64 `json:"promote_importance,omitempty"`
+	// MaxAge trashes memories, long-term ones included, created longer ago.
+	MaxAge Duration `json:"max_age,omitempty"`
+	// MaxCount keeps at most this many memories per project, trashing
+	// short-term memories before long-term ones and the least recently used
+	// first.
+	MaxCount int `json:"max_count,omitempty"`
+}
+
+// RetentionRules are the rules that apply in a workspace: Rules, set through
+// the admin API, are checked before ConfigRules from config.yaml.
+type RetentionRules struct {
+	Rules       []RetentionRule `json:"rules"`
+	ConfigRules []RetentionRule `json:"config_rules"`
+}
+
+// matches reports whether r applies to a memory of typ in project, written
+// by agent.
+func (r RetentionRule) matches(typ MemoryType, project, agent *string) bool {
+	if r.Type != "" && r.Type != typ {
+		return false
+	}
+	if r.Project != "" && (project == nil || !matchGlob(r.Project, *project)) {
+		return false
+	}
+	if r.AgentSource != "" && (agent == nil || *agent != r.AgentSource) {
+		return false
+	}
+	return true
+}
+
+// validate checks a rule set through the admin API.
+func (r *RetentionRule) validate() error {
+	if r.Type != "" && !ValidTypes[r.Type] {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidRetentionRule, r.Type)
+	}
+	if r.TTL == 0 && r.PromoteAccessCount == 0 && r.PromoteImportance == nil && r.MaxAge == 0 && r.MaxCount == 0 {
+		return fmt.Errorf("%w: sets none of ttl, promote_access_count, promote_importance, max_age, max_count", ErrInvalidRetentionRule)
+	}
+	if r.TTL < 0 || (r.TTL > 0 && time.Duration(r.TTL) < time.Second) {
+		return fmt.Errorf("%w: ttl must be at least 1s", ErrInvalidRetentionRule)
+	}
+	if r.MaxAge < 0 || r.PromoteAccessCount < 0 || r.MaxCount < 0 {
+		return fmt.Errorf("%w: max_age, promote_access_count and max_count must not be negative", ErrInvalidRetentionRule)
+	}
+	if r.PromoteImportance != nil && (*r.PromoteImportance < 0 || *r.PromoteImportance > 1) {
+		return fmt.Errorf("%w: promote_importance must be within [0,1]", ErrInvalidRetentionRule)
+	}
+	return nil
+}
+
+// matchGlob matches s against a glob in which * matches any run of
+// characters, / included, and ? any one character.
+func matchGlob(glob, s string) bool {
+	g, t := []rune(glob), []rune(s)
+	gi, ti := 0, 0
+	star, mark := -1, 0
+	for ti < len(t) {
+		switch {
+		case gi < len(g) && (g[gi] == '?' || g[gi] == t[ti]):
+			gi++
+			ti++
+		case gi < len(g) && g[gi] == '*':
+			star, mark = gi, ti
+			gi++
+		case star >= 0:
+			// Let the last * absorb one more character and retry.
+			gi = star + 1
+			mark++
+			ti = mark
+		default:
+			return false
+		}
+	}
+	for gi < len(g) && g[gi] == '*' {
+		gi++
+	}
+	return gi == len(g)
+}
+
+// globToLike turns a glob into the equivalent LIKE pattern with \ as the
+// escape character.
+func globToLike(glob string) string {
+	var b strings.Builder
+	for _, c := range glob {
+		switch c {
+		case '*':
+			b.WriteString("%")
+		case '?':
+			b.WriteString("_")
+		case '%', '_', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// retentionSettings is what the rules matching a memory decide for it.
+type retentionSettings struct {
+	TTL                int // seconds
+	PromoteAccessCount int
+	PromoteImportance  float64
+}
+
+// resolveRetention takes each setting from the first rule matching the
+// memory that sets it, or from the memory config.
+func resolveRetention(rules []RetentionRule, cfg config.MemoryConfig, typ MemoryType, project, agent *string) retentionSettings {
+	set := retentionSettings{TTL: cfg.DefaultTTL, PromoteAccessCount: cfg.PromoteAccessCount, PromoteImportance: cfg.PromoteImportance}
+	var haveTTL, haveCount, haveImportance bool
+	for _, r := range rules {
+		if !r.matches(typ, project, agent) {
+			continue
+		}
+		if !haveTTL && r.TTL > 0 {
+			set.TTL, haveTTL = int(time.Duration(r.TTL).Seconds()), true
+		}
+		if !haveCount && r.PromoteAccessCount > 0 {
+			set.PromoteAccessCount, haveCount = r.PromoteAccessCount, true
+		}
+		if !haveImportance && r.PromoteImportance != nil {
+			set.PromoteImportance, haveImportance = *r.PromoteImportance, true
+		}
+	}
+	return set
+}
+
+// retentionRulesFromConfig converts the rules in config.yaml.
+func retentionRulesFromConfig(rules []config.RetentionRule) []RetentionRule {
+	out := make([]RetentionRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, RetentionRule{
+			Type:               MemoryType(strings.ToLower(strings.TrimSpace(r.Type))),
+			Project:            r.Project,
+			AgentSource:        r.AgentSource,
+			TTL:                Duration(r.TTL),
+			PromoteAccessCount: r.PromoteAccessCount,
+			PromoteImportance:  r.PromoteImportance,
+			MaxAge:             Duration(r.MaxAge),
+			MaxCount:           r.MaxCount,
+		})
+	}
+	return out
+}
+
+// retentionRefresh is how often an instance reloads the rules set through
+// the admin API, so changes made on another instance apply here too.
+const retentionRefresh = time.Minute
+
+// retentionCache holds the rules of every workspace that has any.
+type retentionCache struct {
+	mu          sync.RWMutex
+	byWorkspace map[string][]RetentionRule
+	loadedAt    time.Time
+}
+
+func (c *retentionCache) get(workspace string) []RetentionRule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.byWorkspace[workspace]
+}
+
+func (c *retentionCache) set(workspace string, rules []RetentionRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byWorkspace == nil {
+		c.byWorkspace = map[string][]RetentionRule{}
+	}
+	if len(rules) == 0 {
+		delete(c.byWorkspace, workspace)
+	} else {
+		c.byWorkspace[workspace] = rules
+	}
+}
+
+// claimRefresh reports whether the caller should reload the rules, and if
+// so marks them loaded so concurrent callers don't reload too.
+func (c *retentionCache) claimRefresh(force bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !force && time.Since(c.loadedAt) < retentionRefresh {
+		return false
+	}
+	c.loadedAt = time.Now()
+	return true
+}
+
+// refreshRetention reloads the rules set through the admin API when they
+// are older than retentionRefresh, or always with force. On failure the
+// previous rules stay in use.
+func (s *Service) refreshRetention(ctx context.Context, force bool) {
+	if !s.retention.claimRefresh(force) {
+		return
+	}
+	all, err := s.repo.ListAllRetentionRules(ctx)
+	if err != nil {
+		slog.Warn("failed to load retention rules", "error", err)
+		return
+	}
+	s.retention.mu.Lock()
+	s.retention.byWorkspace = all
+	s.retention.mu.Unlock()
+}
+
+// retentionRules returns the rules that apply in workspace, in order.
+func (s *Service) retentionRules(workspace string) []RetentionRule {
+	ws := s.retention.get(workspace)
+	if len(ws) == 0 {
+		return s.configRetention
+	}
+	return append(append([]RetentionRule{}, ws...), s.configRetention...)
+}
+
+// retentionFor resolves the settings of a memory in workspace.
+func (s *Service) retentionFor(workspace string, mem *Memory) retentionSettings {
+	return resolveRetention(s.retentionRules(workspace), s.cfg, mem.Type, mem.ProjectID, mem.AgentSource)
+}
+
+// RetentionRules returns the retention rules of the caller's workspace.
+func (s *Service) RetentionRules(ctx context.Context) (*RetentionRules, error) {
+	rules, err := s.repo.GetRetentionRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionRules{Rules: rules, ConfigRules: s.configRetention}, nil
+}
+
+// SetRetentionRules replaces the retention rules of the caller's workspace.
+// An empty list leaves only the rules from config.yaml. MaxAge and MaxCount
+// are enforced by the next cleanup.
+func (s *Service) SetRetentionRules(ctx context.Context, rules []RetentionRule) (*RetentionRules, error) {
+	if rules == nil {
+		rules = []RetentionRule{}
+	}
+	for i := range rules {
+		rules[i].Type = MemoryType(strings.ToLower(strings.TrimSpace(string(rules[i].Type))))
+		if err := rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	if err := s.repo.SetRetentionRules(ctx, rules); err != nil {
+		return nil, err
+	}
+	s.retention.set(WorkspaceFromContext(ctx), rules)
+	slog.Info("updated retention rules", "workspace", WorkspaceFromContext(ctx), "rules", len(rules))
+	return &RetentionRules{Rules: rules, ConfigRules: s.configRetention}, nil
+}
+
+// EnforceRetention trashes the memories that are past a matching rule's
+// MaxAge or beyond its MaxCount, in every workspace, and returns how many.
+func (s *Service) EnforceRetention(ctx context.Context) (int64, error) {
+	s.refreshRetention(ctx, true)
+
+	var total int64
+	apply := func(workspace string, rules []RetentionRule) error {
+		for _, r := range rules {
+			if r.MaxAge > 0 {
+				n, err := s.repo.TrashOlderThan(ctx, workspace, r, time.Duration(r.MaxAge))
+				if err != nil {
+					return err
+				}
+				total += n
+			}
+			if r.MaxCount > 0 {
+				n, err := s.repo.TrashOverCount(ctx, workspace, r, r.MaxCount)
+				if err != nil {
+					return err
+				}
+				total += n
+			}
+		}
+		return nil
+	}
+
+	if err := apply("", s.configRetention); err != nil {
+		return total, err
+	}
+	s.retention.mu.RLock()
+	byWorkspace := make(map[string][]RetentionRule, len(s.retention.byWorkspace))
+	for ws, rules := range s.retention.byWorkspace {
+		byWorkspace[ws] = rules
+	}
+	s.retention.mu.RUnlock()
+	for ws, rules := range byWorkspace {
+		if err := apply(ws, rules); err != nil {
+			return total, err
+		}
+	}
+
+	if total > 0 {
+		slog.Info("moved memories past retention limits to trash", "count", total)
+		s.invalidateAllSearchCaches(ctx)
+	}
+	return total, nil
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetRetentionRules returns the retention rules of the workspace, in order.
+func (r *Repository) GetRetentionRules(ctx context.Context) ([]RetentionRule, error) {
+	var raw []byte
+	err := r.pool.QueryRow(ctx, "SELECT rules FROM retention_rules WHERE workspace_id = $1", WorkspaceFromContext(ctx)).Scan(&raw)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return []RetentionRule{}, nil
+		}
+		return nil, fmt.Errorf("get retention rules: %w", err)
+	}
+	rules := []RetentionRule{}
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("decode retention rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SetRetentionRules replaces the retention rules of the workspace.
+func (r *Repository) SetRetentionRules(ctx context.Context, rules []RetentionRule) error {
+	ws := WorkspaceFromContext(ctx)
+	if len(rules) == 0 {
+		if _, err := r.pool.Exec(ctx, "DELETE FROM retention_rules WHERE workspace_id = $1", ws); err != nil {
+			return fmt.Errorf("clear retention rules: %w", err)
+		}
+		return nil
+	}
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("encode retention rules: %w", err)
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO retention_rules (workspace_id, rules, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (workspace_id) DO UPDATE SET rules = EXCLUDED.rules, updated_at = NOW()
+	`, ws, raw)
+	if err != nil {
+		return fmt.Errorf("set retention rules: %w", err)
+	}
+	return nil
+}
+
+// ListAllRetentionRules returns the retention rules of every workspace that
+// has any.
+func (r *Repository) ListAllRetentionRules(ctx context.Context) (map[string][]RetentionRule, error) {
+	rows, err := r.pool.Query(ctx, "SELECT workspace_id, rules FROM retention_rules")
+	if err != nil {
+		return nil, fmt.Errorf("list retention rules: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string][]RetentionRule{}
+	for rows.Next() {
+		var ws string
+		var raw []byte
+		if err := rows.Scan(&ws, &raw); err != nil {
+			return nil, fmt.Errorf("scan retention rules: %w", err)
+		}
+		var rules []RetentionRule
+		if err := json.Unmarshal(raw, &rules); err != nil {
+			return nil, fmt.Errorf("decode retention rules of %s: %w", ws, err)
+		}
+		if len(rules) > 0 {
+			out[ws] = rules
+		}
+	}
+	return out, rows.Err()
+}
+
+// retentionConditions selects the live memories rule applies to, in
+// workspace or, when it is empty, in every workspace.
+func retentionConditions(workspace string, rule RetentionRule) ([]string, []any) {
+	conditions := []string{"deleted_at IS NULL", "replaced_by IS NULL"}
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if workspace != "" {
+		add("workspace_id = $%d", workspace)
+	}
+	if rule.Type != "" {
+		add("type = $%d", string(rule.Type))
+	}
+	if rule.Project != "" {
+		add(`project_id LIKE $%d ESCAPE '\'`, globToLike(rule.Project))
+	}
+	if rule.AgentSource != "" {
+		add("agent_source = $%d", rule.AgentSource)
+	}
+	return conditions, args
+}
+
+// TrashOlderThan moves the memories rule applies to that were created more
+// than maxAge ago to the trash.
+func (r *Repository) TrashOlderThan(ctx context.Context, workspace string, rule RetentionRule, maxAge time.Duration) (int64, error) {
+	conditions, args := retentionConditions(workspace, rule)
+	args = append(args, time.Now().Add(-maxAge))
+	conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+
+	result, err := r.pool.Exec(ctx, `
+		UPDATE memories SET deleted_at = NOW(), deleted_reason = 'expired'
+		WHERE `+strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		return 0, fmt.Errorf("trash memories past max age: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// TrashOverCount keeps the maxCount memories rule applies to in each
+// project of each workspace and moves the rest to the trash: short-term
+// memories before long-term ones, least recently used first.
+func (r *Repository) TrashOverCount(ctx context.Context, workspace string, rule RetentionRule, maxCount int) (int64, error) {
+	conditions, args := retentionConditions(workspace, rule)
+	args = append(args, maxCount)
+
+	result, err := r.pool.Exec(ctx, fmt.Sprintf(`
+		WITH ranked AS (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY workspace_id, project_id
+				ORDER BY (ttl_seconds IS NULL) DESC, COALESCE(last_accessed_at, created_at) DESC, id
+			) AS rn
+			FROM memories
+			WHERE %s
+		)
+		UPDATE memories m SET deleted_at = NOW(), deleted_reason = 'expired'
+		FROM ranked
+		WHERE m.id = ranked.id AND ranked.rn > $%d
+	`, strings.Join(conditions, " AND "), len(args)), args...)
+	if err != nil {
+		return 0, fmt.Errorf("trash memories over max count: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
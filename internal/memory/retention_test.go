@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		glob, s string
+		want    bool
+	}{
+		{"github.com/acme/*", "github.com/acme/api", true},
+		{"github.com/acme/*", "github.com/acme/api/sub", true},
+		{"github.com/acme/*", "github.com/other/api", false},
+		{"*/api", "github.com/acme/api", true},
+		{"github.com/acme/ap?", "github.com/acme/api", true},
+		{"github.com/acme/ap?", "github.com/acme/apis", false},
+		{"exact", "exact", true},
+		{"*", "", true},
+	}
+	for _, c := range cases {
+		if got := matchGlob(c.glob, c.s); got != c.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", c.glob, c.s, got, c.want)
+		}
+	}
+	if got := globToLike(`acme/*_v?%`); got != `acme/%\_v_\%` {
+		t.Errorf("globToLike = %q", got)
+	}
+}
+
+func TestResolveRetention(t *testing.T) {
+	cfg := config.MemoryConfig{DefaultTTL: 86400, PromoteAccessCount: 5, PromoteImportance: 0.8}
+	half := 0.5
+	rules := []RetentionRule{
+		{Type: TypeConversation, TTL: Duration(time.Hour)},
+		{Project: "github.com/acme/*", TTL: Duration(7 * 24 * time.Hour), PromoteImportance: &half},
+		{AgentSource: "cursor", PromoteAccessCount: 2, TTL: Duration(time.Minute)},
+	}
+	acme, cursor := "github.com/acme/api", "cursor"
+
+	got := resolveRetention(rules, cfg, TypeConversation, &acme, &cursor)
+	want := retentionSettings{TTL: 3600, PromoteAccessCount: 2, PromoteImportance: 0.5}
+	if got != want {
+		t.Fatalf("each setting should come from the first rule that sets it: got %+v, want %+v", got, want)
+	}
+	if got := resolveRetention(rules, cfg, TypeDecision, nil, nil); got != (retentionSettings{TTL: 86400, PromoteAccessCount: 5, PromoteImportance: 0.8}) {
+		t.Fatalf("no rule matches, expected memory config, got %+v", got)
+	}
+}
+
+func TestRetentionRule_JSONAndValidate(t *testing.T) {
+	var r RetentionRule
+	if err := json.Unmarshal([]byte(`{"type":"conversation","ttl":"6h","max_age":"720h","max_count":50}`), &r); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if time.Duration(r.TTL) != 6*time.Hour || time.Duration(r.MaxAge) != 720*time.Hour || r.MaxCount != 50 {
+		t.Fatalf("unexpected rule %+v", r)
+	}
+	if err := r.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	out, _ := json.Marshal(r)
+	if string(out) != `{"type":"conversation","ttl":"6h0m0s","max_age":"720h0m0s","max_count":50}` {
+		t.Fatalf("marshal = %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"ttl":3600}`), &r); err == nil {
+		t.Fatalf("expected an error for a numeric duration")
+	}
+	for _, bad := range []RetentionRule{
+		{Type: "nonsense", MaxCount: 1},
+		{Type: TypeConversation},
+		{TTL: Duration(time.Millisecond)},
+		{MaxCount: -1},
+	} {
+		if err := bad.validate(); !errors.Is(err, ErrInvalidRetentionRule) {
+			t.Errorf("validate(%+v) = %v, want ErrInvalidRetentionRule", bad, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevertConsolidation undoes a logged merge: the target gets its pre-merge
+// content back and the sources become live again. Reverting is refused if a
+// memory involved has been purged, a source was re-merged elsewhere, or a
+// later merge into the same target is still in effect. Returns the revert's
+// own log entry.
+func (s *Service) RevertConsolidation(ctx context.Context, logID uuid.UUID, performedBy string) (*ConsolidationLog, error) {
+	if performedBy == "" {
+		performedBy = "system"
+	}
+
+	orig, err := s.repo.GetConsolidationLogEntry(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	if orig == nil {
+		return nil, fmt.Errorf("%w: %s", ErrConsolidationNotFound, logID)
+	}
+	if orig.MergeStrategy == "revert" {
+		return nil, fmt.Errorf("%w: entry %s is itself a revert", ErrConsolidationNotRevertible, logID)
+	}
+	if orig.RevertedAt != nil {
+		return nil, fmt.Errorf("%w: %s", ErrConsolidationAlreadyReverted, logID)
+	}
+
+	target, err := s.repo.Get(ctx, orig.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("get target: %w", err)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%w: target %s was purged", ErrConsolidationNotRevertible, orig.TargetID)
+	}
+	if target.ReplacedBy != nil {
+		return nil, fmt.Errorf("%w: target %s was merged into %s", ErrConsolidationNotRevertible, target.ID, *target.ReplacedBy)
+	}
+	later, err := s.repo.CountLaterConsolidations(ctx, target.ID, orig.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if later > 0 {
+		return nil, fmt.Errorf("%w: %d later merge(s) into %s must be reverted first", ErrConsolidationNotRevertible, later, target.ID)
+	}
+
+	var purged []uuid.UUID
+	sources := make([]*Memory, 0, len(orig.SourceIDs))
+	for _, sid := range orig.SourceIDs {
+		src, err := s.repo.Get(ctx, sid)
+		if err != nil {
+			return nil, fmt.Errorf("get source %s: %w", sid, err)
+		}
+		if src == nil {
+			purged = append(purged, sid)
+			continue
+		}
+		if src.ReplacedBy == nil || *src.ReplacedBy != target.ID {
+			return nil, fmt.Errorf("%w: source %s is no longer replaced by %s", ErrConsolidationNotRevertible, sid, target.ID)
+		}
+		sources = append(sources, src)
+	}
+	if len(purged) > 0 {
+		return nil, fmt.Errorf("%w: source memories already purged: %v", ErrConsolidationNotRevertible, purged)
+	}
+
+	// Prefer the pre-merge snapshot so title, tags and importance come back
+	// too; fall back to restoring content alone.
+	restored := *target
+	restored.Content = orig.ContentBefore
+	snap, err := s.repo.FindVersionBefore(ctx, target.ID, orig.ContentBefore, orig.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		restored.Title = snap.Title
+		restored.Summary = snap.Summary
+		restored.Type = snap.Type
+		restored.Tags = snap.Tags
+		restored.Importance = snap.Importance
+	}
+	restored.MergedFrom = slices.DeleteFunc(slices.Clone(target.MergedFrom), func(id uuid.UUID) bool {
+		return slices.Contains(orig.SourceIDs, id)
+	})
+
+	items := []ReembedItem{{ID: restored.ID, Title: restored.Title, Content: restored.Content}}
+	for _, src := range sources {
+		items = append(items, ReembedItem{ID: src.ID, Title: src.Title, Content: src.Content})
+	}
+	embedder := s.activeEmbedder()
+	vectors, err := s.embedItems(ctx, embedder, items, embedder.Dimensions())
+	if err != nil {
+		return nil, fmt.Errorf("re-embed: %w", err)
+	}
+
+	revertsID := orig.ID
+	entry := &ConsolidationLog{
+		ID:            uuid.New(),
+		TargetID:      target.ID,
+		SourceIDs:     orig.SourceIDs,
+		MergeStrategy: "revert",
+		ContentBefore: target.Content,
+		ContentAfter:  restored.Content,
+		PerformedBy:   performedBy,
+		CreatedAt:     time.Now(),
+		RevertsID:     &revertsID,
+	}
+	if err := s.repo.RevertConsolidation(ctx, orig, &restored, vectors[0], vectors[1:], embedder.Model(), entry); err != nil {
+		return nil, err
+	}
+
+	slog.Info("reverted consolidation",
+		"log_id", orig.ID,
+		"target", target.ID,
+		"sources", len(sources),
+		"performed_by", performedBy,
+	)
+	s.invalidateSearchCache(ctx, append([]*Memory{target}, sources...)...)
+	return entry, nil
+}
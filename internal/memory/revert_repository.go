@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// GetConsolidationLogEntry returns one consolidation log entry, or nil.
+func (r *Repository) GetConsolidationLogEntry(ctx context.Context, id uuid.UUID) (*ConsolidationLog, error) {
+	l := &ConsolidationLog{}
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, target_id, source_ids, merge_strategy, similarity_score, content_before, content_after, performed_by, created_at,
+		       reverted_at, reverts_id
+		FROM consolidation_log WHERE id = $1 AND workspace_id = $2
+	`, id, WorkspaceFromContext(ctx)).Scan(
+		&l.ID, &l.TargetID, &l.SourceIDs, &l.MergeStrategy,
+		&l.SimilarityScore, &l.ContentBefore, &l.ContentAfter, &l.PerformedBy, &l.CreatedAt,
+		&l.RevertedAt, &l.RevertsID,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get consolidation log entry: %w", err)
+	}
+	return l, nil
+}
+
+// CountLaterConsolidations counts merges into targetID made after the given
+// time that are still in effect.
+func (r *Repository) CountLaterConsolidations(ctx context.Context, targetID uuid.UUID, after time.Time) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM consolidation_log
+		WHERE target_id = $1 AND created_at > $2
+		  AND merge_strategy <> 'revert' AND reverted_at IS NULL
+	`, targetID, after).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count later consolidations: %w", err)
+	}
+	return n, nil
+}
+
+// FindVersionBefore returns the newest snapshot of memoryID taken at or
+// before the given time whose content matches, or nil.
+func (r *Repository) FindVersionBefore(ctx context.Context, memoryID uuid.UUID, content string, before time.Time) (*MemoryVersion, error) {
+	v, err := scanMemoryVersion(r.pool.QueryRow(ctx, `
+		SELECT `+memoryVersionColumns+`
+		FROM memory_versions
+		WHERE memory_id = $1 AND content = $2 AND created_at <= $3
+		ORDER BY version_no DESC
+		LIMIT 1
+	`, memoryID, content, before))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find version before: %w", err)
+	}
+	return v, nil
+}
+
+// RevertConsolidation applies a revert in one transaction: the target gets
+// its pre-merge fields back, the sources are un-replaced with fresh vectors,
+// the SUPERSEDES links are dropped, the original entry is stamped
+// reverted_at and the revert entry is logged.
+func (r *Repository) RevertConsolidation(ctx context.Context, orig *ConsolidationLog, target *Memory, targetEmbedding pgvector.Vector, sourceEmbeddings []pgvector.Vector, embeddingModel string, entry *ConsolidationLog) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin revert consolidation: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		"UPDATE consolidation_log SET reverted_at = NOW() WHERE id = $1 AND reverted_at IS NULL", orig.ID)
+	if err != nil {
+		return fmt.Errorf("mark consolidation reverted: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrConsolidationAlreadyReverted, orig.ID)
+	}
+
+	if _, err := tx.Exec(ctx, "SELECT set_config('contextify.change_reason', $1, true)", "revert:"+orig.ID.String()); err != nil {
+		return fmt.Errorf("set change_reason: %w", err)
+	}
+
+	tag, err = tx.Exec(ctx, `
+		UPDATE memories
+		SET title = $2, content = $3, summary = $4, type = $5, tags = $6, importance = $7,
+		    merged_from = $8, version = version + 1, embedding = $9, embedding_model = $10,
+		    embedding_next = NULL, embedding_next_model = NULL
+		WHERE id = $1
+	`, target.ID, target.Title, target.Content, target.Summary, target.Type, target.Tags, target.Importance,
+		target.MergedFrom, targetEmbedding, embeddingModel)
+	if err != nil {
+		return fmt.Errorf("restore consolidation target: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: target %s was purged", ErrConsolidationNotRevertible, target.ID)
+	}
+
+	for i, sid := range orig.SourceIDs {
+		tag, err := tx.Exec(ctx, `
+			UPDATE memories
+			SET replaced_by = NULL, embedding = $3, embedding_model = $4,
+			    embedding_next = NULL, embedding_next_model = NULL
+			WHERE id = $1 AND replaced_by = $2
+		`, sid, target.ID, sourceEmbeddings[i], embeddingModel)
+		if err != nil {
+			return fmt.Errorf("restore consolidation source: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: source %s is no longer replaced by %s", ErrConsolidationNotRevertible, sid, target.ID)
+		}
+	}
+
+	if len(orig.SourceIDs) > 0 {
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM memory_relationships
+			WHERE from_memory_id = $1 AND to_memory_id = ANY($2) AND relationship = 'SUPERSEDES'
+		`, target.ID, orig.SourceIDs); err != nil {
+			return fmt.Errorf("delete supersedes relationships: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO consolidation_log (id, target_id, source_ids, merge_strategy, content_before, content_after, performed_by, reverts_id, workspace_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, entry.ID, entry.TargetID, entry.SourceIDs, entry.MergeStrategy,
+		entry.ContentBefore, entry.ContentAfter, entry.PerformedBy, entry.RevertsID, WorkspaceFromContext(ctx)); err != nil {
+		return fmt.Errorf("store revert log: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
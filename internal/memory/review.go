@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions on a memory in the expiry review queue.
+const (
+	// ReviewKeep gives the memory another TTL, as if it had just been read.
+	ReviewKeep = "keep"
+	// ReviewPromote makes the memory long-term.
+	ReviewPromote = "promote"
+	// ReviewExpire moves the memory to the trash now instead of at expiry.
+	ReviewExpire = "expire"
+)
+
+// ExpiringMemory is a short-term memory in the expiry review queue.
+type ExpiringMemory struct {
+	Memory
+	// LastAccessedAt is nil for a memory that was never read.
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
+
+// ExpiringFilter narrows the expiry review queue.
+type ExpiringFilter struct {
+	ProjectID *string
+	// Within is how far ahead to look; zero uses memory.review_window.
+	Within time.Duration
+}
+
+// ExpiringCursor is the position after an expiring memory, ordered by
+// expiry, then id.
+type ExpiringCursor struct {
+	ExpiresAt time.Time `json:"e"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// ExpiringPage is one page of the expiry review queue.
+type ExpiringPage struct {
+	Memories   []ExpiringMemory `json:"memories"`
+	Total      int              `json:"total"`
+	Within     string           `json:"within"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// ExpiryReview is the outcome of reviewing an expiring memory.
+type ExpiryReview struct {
+	ID     uuid.UUID `json:"id"`
+	Action string    `json:"action"`
+	// ExpiresAt is the new expiry of a kept memory.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ReviewWindow is how far ahead the expiry review queue looks by default.
+func (s *Service) ReviewWindow() time.Duration {
+	return s.cfg.ReviewWindow
+}
+
+// ListExpiring lists the workspace's short-term memories that expire within
+// f.Within a page at a time, soonest first, continuing from cursor (empty for
+// the first page).
+func (s *Service) ListExpiring(ctx context.Context, f ExpiringFilter, limit int, cursor string) (*ExpiringPage, error) {
+	var after *ExpiringCursor
+	if cursor != "" {
+		after = &ExpiringCursor{}
+		if err := DecodeCursor(cursor, after); err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if f.Within <= 0 {
+		f.Within = s.cfg.ReviewWindow
+	}
+	if f.ProjectID != nil {
+		normalized := s.normalizeProject(*f.ProjectID)
+		f.ProjectID = &normalized
+	}
+
+	items, total, err := s.repo.ListExpiring(ctx, f, limit+1, after)
+	if err != nil {
+		return nil, err
+	}
+	page := &ExpiringPage{Memories: items, Total: total, Within: f.Within.String()}
+	if len(items) > limit {
+		last := items[limit-1]
+		page.Memories = items[:limit]
+		page.NextCursor = EncodeCursor(ExpiringCursor{ExpiresAt: *last.ExpiresAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// ReviewExpiring applies a review action to a short-term memory. Keeping it
+// counts as an access for its decay policy without adding to its access
+// count; should the policy then make it permanent, it is promoted instead.
+func (s *Service) ReviewExpiring(ctx context.Context, id uuid.UUID, action string) (*ExpiryReview, error) {
+	switch action {
+	case ReviewKeep, ReviewPromote, ReviewExpire:
+	default:
+		return nil, fmt.Errorf("%w: %q (use %s, %s or %s)", ErrInvalidReviewAction, action, ReviewKeep, ReviewPromote, ReviewExpire)
+	}
+	mem, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if mem == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMemoryNotFound, id)
+	}
+	if mem.TTLSeconds == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotExpiring, id)
+	}
+
+	review := &ExpiryReview{ID: id, Action: action}
+	if action == ReviewKeep {
+		s.refreshRetention(ctx, false)
+		state := s.decayState(WorkspaceFromContext(ctx), mem, time.Now())
+		policy := s.decay.For(mem.Type, mem.ProjectID)
+		if policy.Permanent(state) {
+			review.Action = ReviewPromote
+		} else {
+			at := policy.ExpiresAt(state)
+			review.ExpiresAt = &at
+		}
+	}
+
+	switch review.Action {
+	case ReviewKeep:
+		err = s.repo.KeepExpiring(ctx, id, *review.ExpiresAt)
+	case ReviewPromote:
+		err = s.repo.PromoteToLongTerm(ctx, id)
+	case ReviewExpire:
+		err = s.repo.ExpireNow(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("reviewed expiring memory", "id", id, "action", review.Action)
+	s.invalidateSearchCache(ctx, mem)
+	return review, nil
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListExpiring returns live short-term memories matching f that expire
+// within f.Within, soonest first, and how many match in total.
+func (r *Repository) ListExpiring(ctx context.Context, f ExpiringFilter, limit int, after *ExpiringCursor) ([]ExpiringMemory, int, error) {
+	conditions := []string{
+		"m.workspace_id = $1", "m.deleted_at IS NULL", "m.replaced_by IS NULL",
+		"m.ttl_seconds IS NOT NULL", "m.expires_at < $2",
+	}
+	args := []any{WorkspaceFromContext(ctx), time.Now().Add(f.Within)}
+	argIdx := 3
+
+	if f.ProjectID != nil {
+		conditions = append(conditions, fmt.Sprintf("m.project_id = $%d", argIdx))
+		args = append(args, *f.ProjectID)
+		argIdx++
+	}
+
+	var total int
+	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories m WHERE "+strings.Join(conditions, " AND "), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count expiring memories: %w", err)
+	}
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(m.expires_at > $%d OR (m.expires_at = $%d AND m.id > $%d))", argIdx, argIdx, argIdx+1))
+		args = append(args, after.ExpiresAt, after.ID)
+		argIdx += 2
+	}
+	args = append(args, limit)
+
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+		SELECT m.id, m.title, m.content, m.summary, m.type, m.scope, m.project_id,
+		       m.agent_source, m.language::text, m.tags, m.importance, m.ttl_seconds, m.access_count,
+		       m.created_at, m.updated_at, m.expires_at, m.version, m.last_accessed_at
+		FROM memories m
+		WHERE %s
+		ORDER BY m.expires_at, m.id
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), argIdx), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list expiring memories: %w", err)
+	}
+	defer rows.Close()
+
+	items := []ExpiringMemory{}
+	for rows.Next() {
+		var e ExpiringMemory
+		m := &e.Memory
+		if err := rows.Scan(
+			&m.ID, &m.Title, &m.Content, &m.Summary, &m.Type, &m.Scope, &m.ProjectID,
+			&m.AgentSource, &m.Language, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
+			&m.CreatedAt, &m.UpdatedAt, &m.ExpiresAt, &m.Version, &e.LastAccessedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan expiring memory: %w", err)
+		}
+		items = append(items, e)
+	}
+	return items, total, rows.Err()
+}
+
+// KeepExpiring moves a short-term memory's expiry to expiresAt and counts
+// now as its last access, so cleanup's decay evaluation agrees with it.
+func (r *Repository) KeepExpiring(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE memories SET last_accessed_at = NOW(), expires_at = $3
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL AND ttl_seconds IS NOT NULL
+	`, id, WorkspaceFromContext(ctx), expiresAt)
+	if err != nil {
+		return fmt.Errorf("keep expiring memory: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrMemoryNotFound, id)
+	}
+	return nil
+}
+
+// ExpireNow moves a short-term memory to the trash as expired.
+func (r *Repository) ExpireNow(ctx context.Context, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE memories SET deleted_at = NOW(), deleted_reason = 'expired'
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL AND ttl_seconds IS NOT NULL
+	`, id, WorkspaceFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("expire memory: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrMemoryNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestReviewExpiring_RejectsUnknownAction(t *testing.T) {
+	s := &Service{}
+	if _, err := s.ReviewExpiring(context.Background(), uuid.New(), "snooze"); !errors.Is(err, ErrInvalidReviewAction) {
+		t.Fatalf("expected ErrInvalidReviewAction, got %v", err)
+	}
+	if _, err := s.ListExpiring(context.Background(), ExpiringFilter{}, 10, "not-a-cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
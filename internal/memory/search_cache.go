@@ -1,7 +1,9 @@
 package memory
 
 import (
+	"container/list"
 	"encoding/json"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -13,6 +15,10 @@ import (
 type searchCacheEntry struct {
 	results   []SearchResult
 	expiresAt time.Time
+	// workspace and project name the memories the search can return; an
+	// empty project means any project in the workspace.
+	workspace string
+	project   string
 }
 
 type searchCache struct {
@@ -48,11 +54,11 @@ func (c *searchCache) Enabled() bool {
 	return c != nil && c.enabled
 }
 
-func (c *searchCache) Get(req SearchRequest) ([]SearchResult, bool) {
+func (c *searchCache) Get(workspace string, req SearchRequest) ([]SearchResult, bool) {
 	if !c.Enabled() {
 		return nil, false
 	}
-	key, err := cacheKey(req)
+	key, err := cacheKey(workspace, req)
 	if err != nil {
 		return nil, false
 	}
@@ -75,11 +81,11 @@ func (c *searchCache) Get(req SearchRequest) ([]SearchResult, bool) {
 	return cloneSearchResults(entry.results), true
 }
 
-func (c *searchCache) Set(req SearchRequest, results []SearchResult) {
+func (c *searchCache) Set(workspace string, req SearchRequest, results []SearchResult) {
 	if !c.Enabled() {
 		return
 	}
-	key, err := cacheKey(req)
+	key, err := cacheKey(workspace, req)
 	if err != nil {
 		return
 	}
@@ -91,10 +97,15 @@ func (c *searchCache) Set(req SearchRequest, results []SearchResult) {
 		c.order = append(c.order, key)
 	}
 
-	c.entries[key] = searchCacheEntry{
+	entry := searchCacheEntry{
 		results:   cloneSearchResults(results),
 		expiresAt: time.Now().Add(c.ttl),
+		workspace: workspace,
 	}
+	if req.ProjectID != nil {
+		entry.project = *req.ProjectID
+	}
+	c.entries[key] = entry
 
 	for len(c.entries) > c.max && len(c.order) > 0 {
 		oldest := c.order[0]
@@ -115,23 +126,103 @@ func (c *searchCache) InvalidateAll() {
 	c.order = c.order[:0]
 }
 
-func cacheKey(req SearchRequest) (string, error) {
+// Invalidate drops the cached searches that inv's writes could change.
+func (c *searchCache) Invalidate(inv cacheInvalidation) {
+	if c == nil {
+		return
+	}
+	if inv.Workspace == "" {
+		c.InvalidateAll()
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if inv.affects(entry) {
+			delete(c.entries, key)
+		}
+	}
+	c.order = slices.DeleteFunc(c.order, func(key string) bool {
+		_, ok := c.entries[key]
+		return !ok
+	})
+}
+
+// cacheInvalidation describes the memories a write touched, and so the
+// cached searches it makes stale. It is also the payload other instances
+// receive (see ListenForCacheInvalidations).
+type cacheInvalidation struct {
+	// Instance is the Service that made the write.
+	Instance string `json:"instance"`
+	// Workspace is empty when the write spans every workspace.
+	Workspace string `json:"workspace,omitempty"`
+	// Projects are the project_ids of the written memories, with "" for
+	// memories without one. It is empty when a global memory was written,
+	// which project searches return too, or when the write spans the whole
+	// workspace.
+	Projects []string `json:"projects,omitempty"`
+}
+
+// invalidationFor returns the invalidation for writing mems in workspace;
+// with no memories it covers the whole workspace.
+func invalidationFor(workspace string, mems []*Memory) cacheInvalidation {
+	inv := cacheInvalidation{Workspace: workspace}
+	for _, m := range mems {
+		if m == nil {
+			continue
+		}
+		if m.Scope == ScopeGlobal {
+			inv.Projects = nil
+			return inv
+		}
+		project := ""
+		if m.ProjectID != nil {
+			project = *m.ProjectID
+		}
+		if !slices.Contains(inv.Projects, project) {
+			inv.Projects = append(inv.Projects, project)
+		}
+	}
+	return inv
+}
+
+// affects reports whether the cached search entry could return a memory
+// the invalidation covers. A search without a project filter returns
+// memories of every project.
+func (inv cacheInvalidation) affects(entry searchCacheEntry) bool {
+	if inv.Workspace == "" {
+		return true
+	}
+	if entry.workspace != inv.Workspace {
+		return false
+	}
+	return len(inv.Projects) == 0 || entry.project == "" || slices.Contains(inv.Projects, entry.project)
+}
+
+func cacheKey(workspace string, req SearchRequest) (string, error) {
 	type keyPayload struct {
-		Query         string       `json:"query"`
-		Type          *MemoryType  `json:"type,omitempty"`
-		Scope         *MemoryScope `json:"scope,omitempty"`
-		ProjectID     *string      `json:"project_id,omitempty"`
-		AgentSource   *string      `json:"agent_source,omitempty"`
-		Tags          []string     `json:"tags,omitempty"`
-		MinImportance *float32     `json:"min_importance,omitempty"`
-		Limit         int          `json:"limit"`
-		Offset        int          `json:"offset"`
+		Workspace     string          `json:"workspace"`
+		Query         string          `json:"query"`
+		Type          *MemoryType     `json:"type,omitempty"`
+		Scope         *MemoryScope    `json:"scope,omitempty"`
+		ProjectID     *string         `json:"project_id,omitempty"`
+		AgentSource   *string         `json:"agent_source,omitempty"`
+		Tags          []string        `json:"tags,omitempty"`
+		MinImportance *float32        `json:"min_importance,omitempty"`
+		Limit         int             `json:"limit"`
+		Offset        int             `json:"offset"`
+		Ranking       RankingStrategy `json:"ranking,omitempty"`
+		Modifiers     *ScoreModifiers `json:"modifiers,omitempty"`
+		Explain       bool            `json:"explain,omitempty"`
+		Rerank        *bool           `json:"rerank,omitempty"`
+		Filter        string          `json:"filter,omitempty"`
 	}
 
 	tags := append([]string(nil), req.Tags...)
 	sort.Strings(tags)
 
 	payload := keyPayload{
+		Workspace:     workspace,
 		Query:         strings.ToLower(strings.TrimSpace(req.Query)),
 		Type:          req.Type,
 		Scope:         req.Scope,
@@ -141,6 +232,11 @@ func cacheKey(req SearchRequest) (string, error) {
 		MinImportance: req.MinImportance,
 		Limit:         req.Limit,
 		Offset:        req.Offset,
+		Ranking:       req.Ranking,
+		Modifiers:     req.Modifiers,
+		Explain:       req.Explain,
+		Rerank:        req.Rerank,
+		Filter:        strings.TrimSpace(req.Filter),
 	}
 
 	b, err := json.Marshal(payload)
@@ -155,3 +251,90 @@ func cloneSearchResults(in []SearchResult) []SearchResult {
 	copy(out, in)
 	return out
 }
+
+// queryEmbeddingCache remembers query embeddings so a repeated search skips
+// the embedding provider. Entries are keyed by model and normalized query
+// text and evicted least recently used. Embeddings depend on the text
+// alone, so writes never invalidate them.
+type queryEmbeddingCache struct {
+	ttl time.Duration
+	max int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+}
+
+type queryEmbeddingEntry struct {
+	key       string
+	vector    []float32
+	expiresAt time.Time
+}
+
+// newQueryEmbeddingCache returns nil, a disabled cache, when
+// search.embedding_cache_size is 0.
+func newQueryEmbeddingCache(cfg config.SearchConfig) *queryEmbeddingCache {
+	if cfg.EmbeddingCacheSize <= 0 {
+		return nil
+	}
+	ttl := cfg.EmbeddingCacheTTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	return &queryEmbeddingCache{
+		ttl:     ttl,
+		max:     cfg.EmbeddingCacheSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (c *queryEmbeddingCache) Get(model, query string) ([]float32, bool) {
+	if c == nil {
+		return nil, false
+	}
+	key := queryEmbeddingKey(model, query)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*queryEmbeddingEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry.vector, true
+}
+
+func (c *queryEmbeddingCache) Set(model, query string, vector []float32) {
+	if c == nil {
+		return
+	}
+	key := queryEmbeddingKey(model, query)
+	entry := &queryEmbeddingEntry{key: key, vector: vector, expiresAt: time.Now().Add(c.ttl)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.max {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*queryEmbeddingEntry).key)
+	}
+}
+
+// queryEmbeddingKey normalizes query the way cacheKey does, and also
+// collapses runs of whitespace.
+func queryEmbeddingKey(model, query string) string {
+	return model + "\x00" + strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// searchCacheChannel is the Postgres notification channel that carries
+// search cache invalidations between instances.
+const searchCacheChannel = "contextify_search_cache"
+
+// NotifySearchCache publishes a search cache invalidation to every
+// instance listening on the database.
+func (r *Repository) NotifySearchCache(ctx context.Context, payload string) error {
+	if _, err := r.pool.Exec(ctx, "SELECT pg_notify($1, $2)", searchCacheChannel, payload); err != nil {
+		return fmt.Errorf("notify search cache: %w", err)
+	}
+	return nil
+}
+
+// ListenSearchCache calls onListen once it listens for search cache
+// invalidations, then handle with each payload, until ctx is cancelled or
+// the connection fails. It holds a connection taken out of the pool for as
+// long as it runs.
+func (r *Repository) ListenSearchCache(ctx context.Context, onListen func(), handle func(payload string)) error {
+	pooled, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire listen conn: %w", err)
+	}
+	// A connection left in LISTEN must not go back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{searchCacheChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen search cache: %w", err)
+	}
+	onListen()
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for search cache notification: %w", err)
+		}
+		handle(n.Payload)
+	}
+}
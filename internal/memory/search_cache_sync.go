@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+// invalidateSearchCache drops the cached searches that could return mems,
+// here and, through Postgres, on every other instance. Searches in other
+// workspaces, or filtered to projects none of mems belong to, stay cached.
+// With no memories it drops every cached search in the workspace.
+func (s *Service) invalidateSearchCache(ctx context.Context, mems ...*Memory) {
+	s.applyInvalidation(ctx, invalidationFor(WorkspaceFromContext(ctx), mems))
+}
+
+// invalidateAllSearchCaches drops every cached search, for writes that span
+// workspaces such as background sweeps and an embedding model cutover.
+func (s *Service) invalidateAllSearchCaches(ctx context.Context) {
+	s.applyInvalidation(ctx, cacheInvalidation{})
+}
+
+func (s *Service) applyInvalidation(ctx context.Context, inv cacheInvalidation) {
+	if !s.cache.Enabled() {
+		return
+	}
+	s.cache.Invalidate(inv)
+	if !s.searchCfg.CacheNotify {
+		return
+	}
+
+	inv.Instance = s.instanceID
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		slog.Warn("failed to encode search cache invalidation", "error", err)
+		return
+	}
+	// The write has already happened; publish it even if the caller is gone.
+	notifyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+	if err := s.repo.NotifySearchCache(notifyCtx, string(payload)); err != nil {
+		slog.Warn("failed to publish search cache invalidation", "error", err)
+	}
+}
+
+// ListenForCacheInvalidations applies the search cache invalidations other
+// instances publish, until ctx is cancelled. It reconnects with backoff when
+// the connection drops, and clears the local cache each time it starts
+// listening since invalidations sent in between were missed. It returns at
+// once when the cache or search.cache_notify is off.
+func (s *Service) ListenForCacheInvalidations(ctx context.Context) {
+	if !s.cache.Enabled() || !s.searchCfg.CacheNotify {
+		return
+	}
+
+	backoff := time.Second
+	for {
+		err := s.repo.ListenSearchCache(ctx, func() {
+			s.cache.InvalidateAll()
+			backoff = time.Second
+		}, s.handleCacheInvalidation)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("search cache listener disconnected", "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (s *Service) handleCacheInvalidation(payload string) {
+	var inv cacheInvalidation
+	if err := json.Unmarshal([]byte(payload), &inv); err != nil {
+		slog.Warn("ignoring malformed search cache invalidation", "error", err)
+		return
+	}
+	if inv.Instance == s.instanceID {
+		return
+	}
+	s.cache.Invalidate(inv)
+}
+
+// embedQuery embeds search query text, reusing the embedding of an earlier
+// search for the same text with the same model.
+func (s *Service) embedQuery(ctx context.Context, text string) (pgvector.Vector, string, error) {
+	e := s.activeEmbedder()
+	model := fmt.Sprintf("%s/%d", e.Model(), e.Dimensions())
+	if vec, ok := s.queryEmbeddings.Get(model, text); ok {
+		return pgvector.NewVector(vec), e.Model(), nil
+	}
+	vec, err := e.Embed(ctx, text)
+	if err != nil {
+		return pgvector.Vector{}, "", err
+	}
+	s.queryEmbeddings.Set(model, text, vec)
+	return pgvector.NewVector(vec), e.Model(), nil
+}
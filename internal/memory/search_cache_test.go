@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+func TestSearchCache_InvalidateIsScoped(t *testing.T) {
+	c := newSearchCache(config.SearchConfig{CacheEnabled: true})
+	projA, projB := "proj-a", "proj-b"
+	searches := map[string]SearchRequest{
+		"a":        {Query: "q", ProjectID: &projA},
+		"b":        {Query: "q", ProjectID: &projB},
+		"unscoped": {Query: "q"},
+		"other-ws": {Query: "other", ProjectID: &projA},
+	}
+	fill := func() {
+		for name, req := range searches {
+			ws := "team"
+			if name == "other-ws" {
+				ws = "elsewhere"
+			}
+			c.Set(ws, req, []SearchResult{{Score: 1}})
+		}
+	}
+	cached := func() map[string]bool {
+		out := map[string]bool{}
+		for name, req := range searches {
+			ws := "team"
+			if name == "other-ws" {
+				ws = "elsewhere"
+			}
+			_, out[name] = c.Get(ws, req)
+		}
+		return out
+	}
+
+	fill()
+	c.Invalidate(invalidationFor("team", []*Memory{{Scope: ScopeProject, ProjectID: &projA}}))
+	if got := cached(); got["a"] || !got["b"] || got["unscoped"] || !got["other-ws"] {
+		t.Fatalf("project write: unexpected cache state %v", got)
+	}
+
+	fill()
+	c.Invalidate(invalidationFor("team", []*Memory{{Scope: ScopeGlobal}}))
+	if got := cached(); got["a"] || got["b"] || got["unscoped"] || !got["other-ws"] {
+		t.Fatalf("global write: unexpected cache state %v", got)
+	}
+
+	fill()
+	c.Invalidate(invalidationFor("team", []*Memory{{Scope: ScopeProject}}))
+	if got := cached(); !got["a"] || !got["b"] || got["unscoped"] || !got["other-ws"] {
+		t.Fatalf("write without project: unexpected cache state %v", got)
+	}
+
+	fill()
+	c.Invalidate(cacheInvalidation{})
+	if got := cached(); got["a"] || got["b"] || got["unscoped"] || got["other-ws"] {
+		t.Fatalf("full invalidation: unexpected cache state %v", got)
+	}
+}
+
+func TestHandleCacheInvalidation_IgnoresOwnInstance(t *testing.T) {
+	s := &Service{cache: newSearchCache(config.SearchConfig{CacheEnabled: true}), instanceID: "me"}
+	req := SearchRequest{Query: "q"}
+	s.cache.Set("team", req, nil)
+
+	own, _ := json.Marshal(cacheInvalidation{Instance: "me", Workspace: "team"})
+	s.handleCacheInvalidation(string(own))
+	if _, ok := s.cache.Get("team", req); !ok {
+		t.Fatalf("an instance's own invalidation was already applied locally")
+	}
+
+	other, _ := json.Marshal(cacheInvalidation{Instance: "them", Workspace: "team"})
+	s.handleCacheInvalidation(string(other))
+	if _, ok := s.cache.Get("team", req); ok {
+		t.Fatalf("another instance's invalidation should drop the entry")
+	}
+
+	s.handleCacheInvalidation("not json")
+}
+
+func TestQueryEmbeddingCache(t *testing.T) {
+	c := newQueryEmbeddingCache(config.SearchConfig{EmbeddingCacheSize: 2, EmbeddingCacheTTL: time.Hour})
+	c.Set("m", "Redis  timeout", []float32{1})
+	if _, ok := c.Get("m", " redis timeout "); !ok {
+		t.Fatalf("expected a hit for the same normalized text")
+	}
+	if _, ok := c.Get("other", "redis timeout"); ok {
+		t.Fatalf("embeddings of another model must not be shared")
+	}
+
+	// "redis timeout" was used last, so "b" is evicted.
+	c.Set("m", "b", []float32{2})
+	c.Get("m", "redis timeout")
+	c.Set("m", "c", []float32{3})
+	if _, ok := c.Get("m", "b"); ok {
+		t.Fatalf("expected the least recently used entry to be evicted")
+	}
+	if _, ok := c.Get("m", "redis timeout"); !ok {
+		t.Fatalf("recently used entry was evicted")
+	}
+
+	if newQueryEmbeddingCache(config.SearchConfig{}) != nil {
+		t.Fatalf("size 0 should disable the cache")
+	}
+	var disabled *queryEmbeddingCache
+	disabled.Set("m", "q", []float32{1})
+	if _, ok := disabled.Get("m", "q"); ok {
+		t.Fatalf("disabled cache should never hit")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,22 +17,50 @@ import (
 
 type Service struct {
 	repo       *Repository
-	embedder   *embedding.Client
 	normalizer *ProjectNormalizer
 	cfg        config.MemoryConfig
 	searchCfg  config.SearchConfig
 	cache      *searchCache
+	reranker   Reranker
+	decay      *decayPolicies
+	// configRetention are the retention rules from config.yaml; retention
+	// caches the ones workspaces set through the admin API.
+	configRetention []RetentionRule
+	retention       retentionCache
+	// queryEmbeddings caches search query embeddings.
+	queryEmbeddings *queryEmbeddingCache
+	// instanceID tells this instance's search cache invalidations apart
+	// from other instances'.
+	instanceID string
+	chunking   config.ChunkingConfig
+
+	// embedder serves queries and writes; it differs from target (the
+	// configured model) only while a re-embed migration is in progress.
+	embMu               sync.RWMutex
+	embedder            embedding.Embedder
+	target              embedding.Embedder
+	targetProvider      string
+	reembedCfg          config.ReembedConfig
+	nextEmbeddingRepair time.Time
+	nextChunkSweep      time.Time
 }
 
-func NewService(repo *Repository, embedder *embedding.Client, cfg config.MemoryConfig, searchCfg config.SearchConfig) *Service {
-	return &Service{
+func NewService(repo *Repository, embedder embedding.Embedder, cfg config.MemoryConfig, searchCfg config.SearchConfig) *Service {
+	s := &Service{
 		repo:       repo,
 		embedder:   embedder,
+		target:     embedder,
 		normalizer: NewProjectNormalizer(),
 		cfg:        cfg,
 		searchCfg:  searchCfg,
 		cache:      newSearchCache(searchCfg),
+
+		queryEmbeddings: newQueryEmbeddingCache(searchCfg),
+		instanceID:      uuid.NewString(),
 	}
+	s.decay = newDecayPolicies(cfg, s.normalizeProject)
+	s.configRetention = retentionRulesFromConfig(cfg.Retention)
+	return s
 }
 
 // normalizeProject resolves a raw project_id into a canonical identifier.
@@ -57,7 +87,8 @@ func contextString(ctx context.Context, key string) *string {
 	return &v
 }
 
-func (s *Service) emitTelemetryEvent(event TelemetryEvent) {
+func (s *Service) emitTelemetryEvent(ctx context.Context, event TelemetryEvent) {
+	event.WorkspaceID = WorkspaceFromContext(ctx)
 	go func(ev TelemetryEvent) {
 		bgCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
@@ -67,37 +98,39 @@ func (s *Service) emitTelemetryEvent(event TelemetryEvent) {
 	}(event)
 }
 
-func (s *Service) invalidateSearchCache() {
-	if s.cache != nil {
-		s.cache.InvalidateAll()
-	}
-}
-
 // Store creates a new memory with smart dedup.
 // If consolidation is enabled and a highly similar memory exists (>= AutoMergeThreshold),
 // the existing memory is updated instead. Moderate similarity returns suggestions.
 func (s *Service) Store(ctx context.Context, req StoreRequest) (*StoreResult, error) {
+	if req.Language != "" && !ValidLanguage(req.Language) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidLanguage, req.Language)
+	}
 	// Normalize project_id
 	s.normalizeProjectPtr(req.ProjectID)
 	sessionID := contextString(ctx, "session_id")
 	requestID := contextString(ctx, "request_id")
 
-	s.emitTelemetryEvent(TelemetryEvent{
-		EventType:   TelemetryStoreOpportunity,
-		SessionID:   sessionID,
-		RequestID:   requestID,
-		AgentSource: req.AgentSource,
-		ProjectID:   req.ProjectID,
-		Metadata: map[string]any{
-			"type":       req.Type,
-			"scope":      req.Scope,
-			"importance": req.Importance,
-		},
-	})
+	if req.imported == nil {
+		s.emitTelemetryEvent(ctx, TelemetryEvent{
+			EventType:   TelemetryStoreOpportunity,
+			SessionID:   sessionID,
+			RequestID:   requestID,
+			AgentSource: req.AgentSource,
+			ProjectID:   req.ProjectID,
+			Metadata: map[string]any{
+				"type":       req.Type,
+				"scope":      req.Scope,
+				"importance": req.Importance,
+			},
+		})
+	}
 
 	emitStoreAction := func(action string, memoryID *uuid.UUID, metadata map[string]any) {
+		if req.imported != nil {
+			return
+		}
 		a := action
-		s.emitTelemetryEvent(TelemetryEvent{
+		s.emitTelemetryEvent(ctx, TelemetryEvent{
 			EventType:   TelemetryStoreAction,
 			SessionID:   sessionID,
 			RequestID:   requestID,
@@ -110,14 +143,19 @@ func (s *Service) Store(ctx context.Context, req StoreRequest) (*StoreResult, er
 	}
 
 	// Generate embedding
-	vec, err := s.embedder.Embed(ctx, req.Title+" "+req.Content)
+	var emb pgvector.Vector
+	var embModel string
+	var err error
+	if req.imported != nil && req.imported.Embedding != nil && req.imported.EmbeddingModel != nil {
+		emb, embModel = *req.imported.Embedding, *req.imported.EmbeddingModel
+	} else {
+		emb, embModel, err = s.embed(ctx, req.Title+" "+req.Content)
+	}
 	if err != nil {
 		emitStoreAction("error", nil, map[string]any{"stage": "embed"})
 		return nil, fmt.Errorf("generate embedding: %w", err)
 	}
 
-	emb := pgvector.NewVector(vec)
-
 	// Smart dedup: check for similar memories
 	if s.cfg.Consolidation.Enabled {
 		similar, err := s.repo.FindSimilar(ctx, emb, req.ProjectID, s.cfg.Consolidation.SuggestThreshold, 5)
@@ -136,22 +174,22 @@ func (s *Service) Store(ctx context.Context, req StoreRequest) (*StoreResult, er
 			}
 
 			// Re-embed merged content
-			mergedVec, err := s.embedder.Embed(ctx, title+" "+content)
+			mergedEmb, mergedModel, err := s.embed(ctx, title+" "+content)
 			if err != nil {
 				emitStoreAction("error", nil, map[string]any{"stage": "reembed_merge"})
 				return nil, fmt.Errorf("re-embed merged content: %w", err)
 			}
-			mergedEmb := pgvector.NewVector(mergedVec)
 
 			// Build merged_from list
 			mergedFrom := append(existing.MergedFrom, uuid.Nil) // placeholder
 			copy(mergedFrom[1:], existing.MergedFrom)
 			mergedFrom[0] = existing.ID // track the original state
 
-			if err := s.repo.UpdateMergeFields(ctx, existing.ID, title, content, tags, importance, mergedFrom, &mergedEmb); err != nil {
+			if err := s.repo.UpdateMergeFields(ctx, existing.ID, title, content, tags, importance, mergedFrom, &mergedEmb, mergedModel); err != nil {
 				emitStoreAction("error", nil, map[string]any{"stage": "update_merge_fields"})
 				return nil, fmt.Errorf("auto-merge update: %w", err)
 			}
+			s.kickChunker()
 
 			// Log the auto-merge
 			logEntry := &ConsolidationLog{
@@ -169,8 +207,10 @@ func (s *Service) Store(ctx context.Context, req StoreRequest) (*StoreResult, er
 				slog.Warn("failed to log auto-merge", "error", err)
 			}
 
-			// Promote to long-term if importance is high
-			if importance >= float32(s.cfg.PromoteImportance) {
+			// Promote to long-term if the merged memory's decay policy keeps it
+			merged := existing
+			merged.Importance = importance
+			if merged.TTLSeconds != nil && s.keptForever(ctx, &merged) {
 				s.repo.PromoteToLongTerm(ctx, existing.ID)
 			}
 
@@ -193,11 +233,11 @@ func (s *Service) Store(ctx context.Context, req StoreRequest) (*StoreResult, er
 			} else {
 				emitStoreAction(result.Action, nil, map[string]any{"similarity": similar[0].Similarity})
 			}
-			s.invalidateSearchCache()
+			s.invalidateSearchCache(ctx, &existing)
 			return result, nil
 		} else if len(similar) > 0 {
 			// Store normally but attach suggestions
-			mem, err := s.storeNew(ctx, req, &emb)
+			mem, err := s.storeNew(ctx, req, &emb, embModel)
 			if err != nil {
 				emitStoreAction("error", nil, map[string]any{"stage": "store_new_with_suggestions"})
 				return nil, err
@@ -218,13 +258,13 @@ func (s *Service) Store(ctx context.Context, req StoreRequest) (*StoreResult, er
 			}
 			memID := result.Memory.ID
 			emitStoreAction(result.Action, &memID, map[string]any{"suggestions": len(suggestions)})
-			s.invalidateSearchCache()
+			s.invalidateSearchCache(ctx, mem)
 			return result, nil
 		}
 	}
 
 	// Normal store (no dedup or consolidation disabled)
-	mem, err := s.storeNew(ctx, req, &emb)
+	mem, err := s.storeNew(ctx, req, &emb, embModel)
 	if err != nil {
 		emitStoreAction("error", nil, map[string]any{"stage": "store_new"})
 		return nil, err
@@ -236,31 +276,42 @@ func (s *Service) Store(ctx context.Context, req StoreRequest) (*StoreResult, er
 	}
 	memID := result.Memory.ID
 	emitStoreAction(result.Action, &memID, nil)
-	s.invalidateSearchCache()
+	s.invalidateSearchCache(ctx, mem)
 	return result, nil
 }
 
 // storeNew creates a new memory (the original Store logic).
-func (s *Service) storeNew(ctx context.Context, req StoreRequest, emb *pgvector.Vector) (*Memory, error) {
+func (s *Service) storeNew(ctx context.Context, req StoreRequest, emb *pgvector.Vector, embeddingModel string) (*Memory, error) {
+	if req.imported != nil {
+		mem := req.imported
+		mem.Embedding, mem.EmbeddingModel = emb, &embeddingModel
+		if err := s.repo.ImportMemory(ctx, mem); err != nil {
+			return nil, err
+		}
+		return mem, nil
+	}
+
 	now := time.Now()
 
 	mem := &Memory{
-		ID:          uuid.New(),
-		Title:       req.Title,
-		Content:     req.Content,
-		Summary:     req.Summary,
-		Embedding:   emb,
-		Type:        req.Type,
-		Scope:       req.Scope,
-		ProjectID:   req.ProjectID,
-		AgentSource: req.AgentSource,
-		Tags:        req.Tags,
-		Importance:  req.Importance,
-		TTLSeconds:  req.TTLSeconds,
-		AccessCount: 0,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-		Version:     1,
+		ID:             uuid.New(),
+		Title:          req.Title,
+		Content:        req.Content,
+		Summary:        req.Summary,
+		Embedding:      emb,
+		EmbeddingModel: &embeddingModel,
+		Type:           req.Type,
+		Scope:          req.Scope,
+		ProjectID:      req.ProjectID,
+		AgentSource:    req.AgentSource,
+		Tags:           req.Tags,
+		Importance:     req.Importance,
+		TTLSeconds:     req.TTLSeconds,
+		Language:       req.Language,
+		AccessCount:    0,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Version:        1,
 	}
 
 	if mem.Tags == nil {
@@ -270,17 +321,24 @@ func (s *Service) storeNew(ctx context.Context, req StoreRequest, emb *pgvector.
 	if mem.Scope == "" {
 		mem.Scope = ScopeProject
 	}
+	if mem.Language == "" {
+		mem.Language = s.textLanguage(mem.Title, mem.Content)
+	}
 
-	// Auto long-term if importance is high enough
-	if mem.Importance >= float32(s.cfg.PromoteImportance) {
+	// Long-term right away if the decay policy keeps it for good (by
+	// default, when importance is high enough). Otherwise the TTL comes from
+	// the request or the retention rules.
+	s.refreshRetention(ctx, false)
+	policy := s.decay.For(mem.Type, mem.ProjectID)
+	if state := s.decayState(WorkspaceFromContext(ctx), mem, now); policy.Permanent(state) {
 		mem.TTLSeconds = nil
 		mem.ExpiresAt = nil
 	} else {
 		if mem.TTLSeconds == nil {
-			ttl := s.cfg.DefaultTTL
+			ttl := int(state.TTL.Seconds())
 			mem.TTLSeconds = &ttl
 		}
-		expiresAt := now.Add(time.Duration(*mem.TTLSeconds) * time.Second)
+		expiresAt := policy.ExpiresAt(state)
 		mem.ExpiresAt = &expiresAt
 	}
 
@@ -296,10 +354,17 @@ func (s *Service) storeNew(ctx context.Context, req StoreRequest, emb *pgvector.
 		"importance", mem.Importance,
 		"long_term", mem.TTLSeconds == nil,
 	)
+	s.kickChunker()
 
 	return mem, nil
 }
 
+// Peek returns a memory without counting it as an access, so background jobs
+// can inspect memories without extending their TTL or promoting them.
+func (s *Service) Peek(ctx context.Context, id uuid.UUID) (*Memory, error) {
+	return s.repo.Get(ctx, id)
+}
+
 func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Memory, error) {
 	mem, err := s.repo.Get(ctx, id)
 	if err != nil {
@@ -309,27 +374,18 @@ func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Memory, error) {
 		return nil, nil
 	}
 
-	// Increment access and check for auto-promotion
-	if err := s.repo.IncrementAccess(ctx, id, s.cfg.TTLExtendFactor); err != nil {
-		slog.Warn("failed to increment access", "id", id, "error", err)
-	}
-
-	// Auto-promote if access count threshold reached
-	if mem.TTLSeconds != nil && mem.AccessCount+1 >= s.cfg.PromoteAccessCount {
-		if err := s.repo.PromoteToLongTerm(ctx, id); err != nil {
-			slog.Warn("failed to auto-promote", "id", id, "error", err)
-		} else {
-			slog.Info("auto-promoted memory to long-term", "id", id, "access_count", mem.AccessCount+1)
-			mem.TTLSeconds = nil
-			mem.ExpiresAt = nil
-		}
-	}
+	// Count the access; the decay policy extends or promotes the memory
+	s.recordAccess(ctx, mem)
 
 	return mem, nil
 }
 
 func (s *Service) Update(ctx context.Context, id uuid.UUID, req UpdateRequest) (*Memory, error) {
+	if req.Language != nil && !ValidLanguage(*req.Language) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidLanguage, *req.Language)
+	}
 	var newEmbedding *pgvector.Vector
+	var embeddingModel string
 
 	// Re-embed if content changed
 	if req.Content != nil {
@@ -346,38 +402,67 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, req UpdateRequest) (
 			}
 			title = existing.Title
 		}
-		vec, err := s.embedder.Embed(ctx, title+" "+*req.Content)
+		emb, model, err := s.embed(ctx, title+" "+*req.Content)
 		if err != nil {
 			return nil, fmt.Errorf("re-embed: %w", err)
 		}
-		emb := pgvector.NewVector(vec)
 		newEmbedding = &emb
+		embeddingModel = model
 	}
 
-	if err := s.repo.Update(ctx, id, req, newEmbedding); err != nil {
+	if err := s.repo.Update(ctx, id, req, newEmbedding, embeddingModel); err != nil {
 		return nil, err
 	}
-	s.invalidateSearchCache()
-	return s.repo.Get(ctx, id)
+	if req.Title != nil || req.Content != nil {
+		s.kickChunker()
+	}
+	mem, err := s.repo.Get(ctx, id)
+	s.invalidateSearchCache(ctx, mem)
+	return mem, err
 }
 
+// Delete moves a memory to the trash, from which it can be restored until
+// memory.trash_retention has passed.
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	// Look the memory up first to invalidate only searches that could
+	// return it; if that fails, the whole workspace is invalidated.
+	mem, _ := s.repo.Get(ctx, id)
 	if err := s.repo.Delete(ctx, id); err != nil {
 		return err
 	}
-	s.invalidateSearchCache()
+	s.invalidateSearchCache(ctx, mem)
 	return nil
 }
 
 func (s *Service) Search(ctx context.Context, req SearchRequest) ([]SearchResult, error) {
+	req.Paginate, req.Cursor = false, ""
+	page, err := s.search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
+
+// SearchPage runs a cursor-paginated search. Pages are keyed on the last
+// result's position rather than an offset, so concurrent writes do not
+// repeat or skip results. Each page still re-ranks the candidates from the
+// top, so paging stops at maxSearchDepth results. Paginated searches are
+// never reranked or cached.
+func (s *Service) SearchPage(ctx context.Context, req SearchRequest) (*SearchPage, error) {
+	req.Paginate = true
+	return s.search(ctx, req)
+}
+
+func (s *Service) search(ctx context.Context, req SearchRequest) (*SearchPage, error) {
 	start := time.Now()
+	now := start
 
 	// Normalize project_id
 	s.normalizeProjectPtr(req.ProjectID)
 	sessionID := contextString(ctx, "session_id")
 	requestID := contextString(ctx, "request_id")
 
-	s.emitTelemetryEvent(TelemetryEvent{
+	s.emitTelemetryEvent(ctx, TelemetryEvent{
 		EventType:   TelemetryRecallAttempt,
 		SessionID:   sessionID,
 		RequestID:   requestID,
@@ -392,12 +477,60 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) ([]SearchResult
 	if req.Limit > s.searchCfg.MaxLimit {
 		req.Limit = s.searchCfg.MaxLimit
 	}
+	ranker, err := s.ranker(req.Ranking)
+	if err != nil {
+		return nil, err
+	}
+	req.Ranking = ranker.Strategy()
+	mods, err := resolveScoreModifiers(s.searchCfg.Modifiers, req.Modifiers)
+	if err != nil {
+		return nil, err
+	}
+	var cursor *searchCursor
+	if req.Paginate {
+		if req.Offset != 0 {
+			return nil, fmt.Errorf("%w: offset cannot be combined with cursor pagination", ErrInvalidSearch)
+		}
+		if req.Rerank != nil && *req.Rerank {
+			return nil, fmt.Errorf("%w: rerank cannot be combined with cursor pagination", ErrInvalidSearch)
+		}
+		if req.Cursor != "" {
+			cursor = &searchCursor{}
+			if err := DecodeCursor(req.Cursor, cursor); err != nil {
+				return nil, err
+			}
+			now = cursor.Now
+		}
+	}
+	req.filter, err = ParseFilter(req.Filter, now)
+	if err != nil {
+		return nil, err
+	}
+	req.filter.mapProjects(s.normalizeProject)
+	req.symbols, err = parseSymbolQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	rerank := s.searchReranks(req)
+	req.Rerank = &rerank
+
+	var fingerprint string
+	if req.Paginate {
+		fingerprint = searchFingerprint(WorkspaceFromContext(ctx), req)
+		if cursor != nil {
+			if cursor.Query != fingerprint {
+				return nil, fmt.Errorf("%w: cursor belongs to a different search", ErrInvalidCursor)
+			}
+			// Size the candidate set to reach past the results already seen.
+			req.Offset = cursor.Seen
+		}
+	}
 
-	if s.cache != nil && s.cache.Enabled() {
-		if cached, ok := s.cache.Get(req); ok {
+	if s.cache != nil && s.cache.Enabled() && !req.Paginate {
+		if cached, ok := s.cache.Get(WorkspaceFromContext(ctx), req); ok {
 			hitCount := len(cached)
 			latencyMs := int(time.Since(start).Milliseconds())
-			s.emitTelemetryEvent(TelemetryEvent{
+			s.emitTelemetryEvent(ctx, TelemetryEvent{
 				EventType:   TelemetryRecallHit,
 				SessionID:   sessionID,
 				RequestID:   requestID,
@@ -410,33 +543,48 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) ([]SearchResult
 			})
 
 			for _, r := range cached {
-				go func(id uuid.UUID) {
-					bgCtx := context.Background()
-					s.repo.IncrementAccess(bgCtx, id, s.cfg.TTLExtendFactor)
-				}(r.Memory.ID)
+				go func(mem Memory) {
+					s.recordAccess(context.WithoutCancel(ctx), &mem)
+				}(r.Memory)
 			}
 
-			return cached, nil
+			return &SearchPage{Results: cached}, nil
 		}
 	}
 
-	vec, err := s.embedder.Embed(ctx, req.Query)
+	queryText := req.Query
+	if req.symbols != nil {
+		queryText = req.symbols.text()
+	}
+	queryEmbedding, queryModel, err := s.embedQuery(ctx, queryText)
 	if err != nil {
 		return nil, fmt.Errorf("embed query: %w", err)
 	}
 
-	queryEmbedding := pgvector.NewVector(vec)
-	results, err := s.repo.HybridSearch(ctx, queryEmbedding, req, s.searchCfg.VectorWeight, s.searchCfg.KeywordWeight)
+	chunkModel := ""
+	if s.chunking.Enabled {
+		chunkModel = queryModel
+	}
+	candidates, err := s.repo.HybridSearchCandidates(ctx, queryEmbedding, req, chunkModel)
 	if err != nil {
 		return nil, err
 	}
-	if s.cache != nil && s.cache.Enabled() {
-		s.cache.Set(req, results)
+	page, rerankFailed := s.rankPage(ctx, req, ranker, mods, candidates, cursor, fingerprint, now)
+	results := page.Results
+	if req.Explain {
+		if err := s.addHighlights(ctx, req.Query, results); err != nil {
+			return nil, err
+		}
+	}
+	// A fallback order is not cached, so the next identical query retries
+	// the reranker.
+	if s.cache != nil && s.cache.Enabled() && !rerankFailed && !req.Paginate {
+		s.cache.Set(WorkspaceFromContext(ctx), req, results)
 	}
 
 	hitCount := len(results)
 	latencyMs := int(time.Since(start).Milliseconds())
-	s.emitTelemetryEvent(TelemetryEvent{
+	s.emitTelemetryEvent(ctx, TelemetryEvent{
 		EventType:   TelemetryRecallHit,
 		SessionID:   sessionID,
 		RequestID:   requestID,
@@ -448,48 +596,111 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) ([]SearchResult
 		Metadata:    map[string]any{"cache_hit": false},
 	})
 
-	// Increment access for returned results
-	for _, r := range results {
-		go func(id uuid.UUID) {
-			bgCtx := context.Background()
-			s.repo.IncrementAccess(bgCtx, id, s.cfg.TTLExtendFactor)
-		}(r.Memory.ID)
+	// Increment access for returned results. Continuation pages are left
+	// alone: paging through a search is not a recall of every result, and
+	// the bump would move the scores the cursor is keyed on.
+	if cursor == nil {
+		for _, r := range results {
+			go func(mem Memory) {
+				s.recordAccess(context.WithoutCancel(ctx), &mem)
+			}(r.Memory)
+		}
 	}
 
-	return results, nil
+	return page, nil
 }
 
-func (s *Service) Promote(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.PromoteToLongTerm(ctx, id); err != nil {
-		return err
+// searchReranks reports whether req's results go through the reranker. A
+// paginated search never does: its cursor is keyed on the first-stage
+// score, which a reranked order does not follow. A symbol search asks for
+// exact names, which the reranking model would only reorder by topic.
+func (s *Service) searchReranks(req SearchRequest) bool {
+	if req.Paginate {
+		return false
 	}
-	s.invalidateSearchCache()
-	return nil
+	query := strings.TrimSpace(req.Query)
+	return s.rerankEnabled(req) && query != "" && query != "*" && req.symbols == nil
 }
 
-func (s *Service) CreateRelationship(ctx context.Context, req RelationshipRequest) (*Relationship, error) {
-	rel := &Relationship{
-		ID:           uuid.New(),
-		FromMemoryID: req.FromMemoryID,
-		ToMemoryID:   req.ToMemoryID,
-		Relationship: req.Relationship,
-		Strength:     req.Strength,
-		Context:      req.Context,
-		CreatedAt:    time.Now(),
+// rankPage scores the candidates, reranks them when req.Rerank is set and
+// cuts the requested page. rerankFailed reports a fallback to the
+// first-stage order.
+func (s *Service) rankPage(ctx context.Context, req SearchRequest, ranker Ranker, mods scoreModifiers, candidates []SearchCandidate, cursor *searchCursor, fingerprint string, now time.Time) (page *SearchPage, rerankFailed bool) {
+	results := scoreCandidates(ranker, mods, candidates, now, req.Explain)
+	if req.Rerank != nil && *req.Rerank {
+		if err := s.rerank(ctx, req.Query, results); err != nil {
+			slog.Warn("search rerank failed, keeping first-stage order", "error", err)
+			rerankFailed = true
+		}
+	}
+	if req.Paginate {
+		return cursorPage(results, cursor, req.Limit, fingerprint, now, req.IncludeTotal), rerankFailed
 	}
+	return &SearchPage{Results: pageResults(results, req.Limit, req.Offset)}, rerankFailed
+}
 
-	if rel.Strength <= 0 {
-		rel.Strength = 0.5
+// addHighlights fills in the matched lexemes and content snippets of
+// explained results that match the query text.
+func (s *Service) addHighlights(ctx context.Context, query string, results []SearchResult) error {
+	query = strings.TrimSpace(query)
+	if query == "" || query == "*" {
+		return nil
 	}
+	var ids []uuid.UUID
+	for _, r := range results {
+		if r.Explanation != nil && r.Explanation.KeywordRank > 0 {
+			ids = append(ids, r.Memory.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	highlights, err := s.repo.SearchHighlights(ctx, ids, query)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if h, ok := highlights[r.Memory.ID]; ok {
+			if h.lexemes != nil {
+				r.Explanation.MatchedLexemes = h.lexemes
+			}
+			if h.highlights != nil {
+				r.Explanation.Highlights = h.highlights
+			}
+		}
+	}
+	return nil
+}
 
-	if err := s.repo.StoreRelationship(ctx, rel); err != nil {
+// ranker resolves a request's ranking strategy, falling back to the
+// configured default.
+func (s *Service) ranker(name RankingStrategy) (Ranker, error) {
+	strategy, err := ParseRankingStrategy(string(name))
+	if err != nil {
 		return nil, err
 	}
-	return rel, nil
+	if strategy == "" {
+		strategy = RankingStrategy(s.searchCfg.Ranking)
+	}
+	return NewRanker(strategy, RankingWeights{
+		Vector:  s.searchCfg.VectorWeight,
+		Keyword: s.searchCfg.KeywordWeight,
+		Symbol:  s.searchCfg.SymbolWeight,
+		RRFK:    s.searchCfg.RRFK,
+	})
+}
+
+func (s *Service) Promote(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.PromoteToLongTerm(ctx, id); err != nil {
+		return err
+	}
+	mem, _ := s.repo.Get(ctx, id)
+	s.invalidateSearchCache(ctx, mem)
+	return nil
 }
 
 func (s *Service) GetRelated(ctx context.Context, memoryID uuid.UUID, relationshipTypes []string) ([]Memory, []Relationship, error) {
-	return s.repo.GetRelated(ctx, memoryID, relationshipTypes)
+	return s.repo.GetRelated(ctx, memoryID, canonicalRelationshipFilter(relationshipTypes))
 }
 
 func (s *Service) GetContext(ctx context.Context, projectID string) ([]Memory, error) {
@@ -528,15 +739,46 @@ func (s *Service) GetFunnelAnalytics(ctx context.Context, req FunnelAnalyticsReq
 	return s.repo.GetFunnelAnalytics(ctx, req)
 }
 
+// CleanupExpired evaluates every short-term memory against its decay
+// policy: memories the policy now keeps for good are promoted, those past
+// their expiry move to the trash, and the rest get their expires_at updated,
+// so policy changes in config apply to existing memories. It returns how
+// many memories were trashed.
 func (s *Service) CleanupExpired(ctx context.Context) (int64, error) {
-	count, err := s.repo.DeleteExpired(ctx)
-	if err != nil {
-		return 0, err
+	s.refreshRetention(ctx, true)
+	now := time.Now()
+	var trashed, promoted int64
+	var after uuid.UUID
+	for {
+		batch, err := s.repo.ListDecaying(ctx, after, decayBatchSize)
+		if err != nil {
+			return trashed, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		var d decayDecisions
+		for _, m := range batch {
+			s.evaluateDecay(m, now, &d)
+		}
+		t, p, err := s.repo.ApplyDecay(ctx, &d, now)
+		trashed += t
+		promoted += p
+		if err != nil {
+			return trashed, err
+		}
+		after = batch[len(batch)-1].ID
+		if len(batch) < decayBatchSize {
+			break
+		}
 	}
-	if count > 0 {
-		s.invalidateSearchCache()
+	if promoted > 0 {
+		slog.Info("promoted memories by decay policy", "count", promoted)
 	}
-	return count, nil
+	if trashed > 0 || promoted > 0 {
+		s.invalidateAllSearchCaches(ctx)
+	}
+	return trashed, nil
 }
 
 // --- Consolidation service methods ---
@@ -594,11 +836,10 @@ func (s *Service) ConsolidateMemories(ctx context.Context, targetID uuid.UUID, s
 	importance := maxImportance(target.Importance, sources)
 
 	// Re-embed merged content
-	vec, err := s.embedder.Embed(ctx, title+" "+content)
+	newEmb, newModel, err := s.embed(ctx, title+" "+content)
 	if err != nil {
 		return nil, fmt.Errorf("re-embed merged: %w", err)
 	}
-	newEmb := pgvector.NewVector(vec)
 
 	// Build merged_from list
 	mergedFrom := append([]uuid.UUID{}, target.MergedFrom...)
@@ -607,7 +848,7 @@ func (s *Service) ConsolidateMemories(ctx context.Context, targetID uuid.UUID, s
 	}
 
 	// Update target with merged content
-	if err := s.repo.UpdateMergeFields(ctx, targetID, title, content, tags, importance, mergedFrom, &newEmb); err != nil {
+	if err := s.repo.UpdateMergeFields(ctx, targetID, title, content, tags, importance, mergedFrom, &newEmb, newModel); err != nil {
 		return nil, fmt.Errorf("update target: %w", err)
 	}
 
@@ -622,14 +863,16 @@ func (s *Service) ConsolidateMemories(ctx context.Context, targetID uuid.UUID, s
 			ID:           uuid.New(),
 			FromMemoryID: targetID,
 			ToMemoryID:   src.ID,
-			Relationship: "SUPERSEDES",
+			Relationship: RelSupersedes,
 			Strength:     1.0,
 			CreatedAt:    time.Now(),
 		})
 	}
 
-	// Promote to long-term if high importance
-	if importance >= float32(s.cfg.PromoteImportance) {
+	// Promote to long-term if the merged memory's decay policy keeps it
+	merged := *target
+	merged.Importance = importance
+	if merged.TTLSeconds != nil && s.keptForever(ctx, &merged) {
 		s.repo.PromoteToLongTerm(ctx, targetID)
 	}
 
@@ -657,7 +900,7 @@ func (s *Service) ConsolidateMemories(ctx context.Context, targetID uuid.UUID, s
 		"sources", len(sources),
 		"strategy", strategy,
 	)
-	s.invalidateSearchCache()
+	s.invalidateSearchCache(ctx, append([]*Memory{target}, sources...)...)
 
 	return s.repo.Get(ctx, targetID)
 }
@@ -704,7 +947,7 @@ func (s *Service) ScanForDuplicates(ctx context.Context) (int, error) {
 
 	count := 0
 	for _, p := range pairs {
-		if err := s.repo.StoreSuggestion(ctx, p.MemAID, p.MemBID, p.Similarity, p.ProjectID); err != nil {
+		if err := s.repo.StoreSuggestion(ctx, p.MemAID, p.MemBID, p.Similarity, p.ProjectID, p.WorkspaceID); err != nil {
 			slog.Warn("failed to store suggestion", "a", p.MemAID, "b", p.MemBID, "error", err)
 			continue
 		}
@@ -720,7 +963,37 @@ func (s *Service) GetSuggestions(ctx context.Context, projectID *string, status
 		normalized := s.normalizeProject(*projectID)
 		projectID = &normalized
 	}
-	return s.repo.GetSuggestions(ctx, projectID, status, limit, offset)
+	return s.repo.GetSuggestions(ctx, projectID, status, limit, offset, nil)
+}
+
+// GetSuggestionsPage lists suggestions a page at a time, continuing from
+// cursor (empty for the first page).
+func (s *Service) GetSuggestionsPage(ctx context.Context, projectID *string, status string, limit int, cursor string) (*SuggestionPage, error) {
+	var after *SuggestionCursor
+	if cursor != "" {
+		after = &SuggestionCursor{}
+		if err := DecodeCursor(cursor, after); err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if projectID != nil {
+		normalized := s.normalizeProject(*projectID)
+		projectID = &normalized
+	}
+	suggestions, total, err := s.repo.GetSuggestions(ctx, projectID, status, limit+1, 0, after)
+	if err != nil {
+		return nil, err
+	}
+	page := &SuggestionPage{Suggestions: suggestions, Total: total}
+	if len(suggestions) > limit {
+		last := suggestions[limit-1]
+		page.Suggestions = suggestions[:limit]
+		page.NextCursor = EncodeCursor(SuggestionCursor{Similarity: last.Similarity, ID: last.ID})
+	}
+	return page, nil
 }
 
 // UpdateSuggestionStatus updates a suggestion's status.
@@ -768,7 +1041,7 @@ func (s *Service) NormalizeAllProjectIDs(ctx context.Context) (int, error) {
 		}
 	}
 	if updated > 0 {
-		s.invalidateSearchCache()
+		s.invalidateAllSearchCaches(ctx)
 	}
 	return updated, nil
 }
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// symbolQueryPrefix starts a symbol search: "symbol:Repository.HybridSearch
+// parse_*" matches memories containing those identifiers or file paths
+// instead of searching their text. A term ending in * matches by prefix.
+const symbolQueryPrefix = "symbol:"
+
+// maxSymbolTerms caps the terms of a symbol search.
+const maxSymbolTerms = 20
+
+// symbolQuery is a parsed symbol search. Terms are lowercased, as stored in
+// memories.symbols by the memory_symbols() SQL function.
+type symbolQuery struct {
+	exact    []string
+	prefixes []string
+}
+
+// parseSymbolQuery parses query if it is a symbol search, and returns nil
+// otherwise.
+func parseSymbolQuery(query string) (*symbolQuery, error) {
+	query = strings.TrimSpace(query)
+	if len(query) < len(symbolQueryPrefix) || !strings.EqualFold(query[:len(symbolQueryPrefix)], symbolQueryPrefix) {
+		return nil, nil
+	}
+	terms := strings.FieldsFunc(query[len(symbolQueryPrefix):], func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	q := &symbolQuery{exact: []string{}, prefixes: []string{}}
+	seen := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		t = strings.ToLower(t)
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if prefix, ok := strings.CutSuffix(t, "*"); ok {
+			if len(prefix) < 2 {
+				return nil, fmt.Errorf("%w: symbol prefix %q is too short", ErrInvalidSearch, t)
+			}
+			q.prefixes = append(q.prefixes, prefix)
+			continue
+		}
+		q.exact = append(q.exact, t)
+	}
+	switch n := len(q.exact) + len(q.prefixes); {
+	case n == 0:
+		return nil, fmt.Errorf("%w: symbol search needs at least one symbol", ErrInvalidSearch)
+	case n > maxSymbolTerms:
+		return nil, fmt.Errorf("%w: symbol search takes at most %d symbols", ErrInvalidSearch, maxSymbolTerms)
+	}
+	return q, nil
+}
+
+// text is the query text embedded for a symbol search.
+func (q *symbolQuery) text() string {
+	return strings.Join(append(append([]string{}, q.exact...), q.prefixes...), " ")
+}
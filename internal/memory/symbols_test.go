@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSymbolQuery(t *testing.T) {
+	q, err := parseSymbolQuery("  Symbol:Repository.HybridSearch, parse_* internal/memory/filter.go parse_*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"repository.hybridsearch", "internal/memory/filter.go"}; !reflect.DeepEqual(q.exact, want) {
+		t.Errorf("exact: got %q, want %q", q.exact, want)
+	}
+	if want := []string{"parse_"}; !reflect.DeepEqual(q.prefixes, want) {
+		t.Errorf("prefixes: got %q, want %q", q.prefixes, want)
+	}
+	if got := q.text(); got != "repository.hybridsearch internal/memory/filter.go parse_" {
+		t.Errorf("text: got %q", got)
+	}
+
+	if q, err := parseSymbolQuery("how to fix symbol: errors"); q != nil || err != nil {
+		t.Errorf("plain query: got %v, %v", q, err)
+	}
+	for _, bad := range []string{"symbol:", "symbol: , ", "symbol:a*"} {
+		if _, err := parseSymbolQuery(bad); !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("%q: expected ErrInvalidSearch, got %v", bad, err)
+		}
+	}
+}
+
+func TestScoreCandidates_SymbolMatches(t *testing.T) {
+	now := time.Now()
+	cands := []SearchCandidate{
+		{Memory: Memory{ID: uuid.New(), Title: "prose", UpdatedAt: now}, VectorScore: 0.75, VectorRank: 1},
+		{Memory: Memory{ID: uuid.New(), Title: "symbol", UpdatedAt: now}, VectorScore: 0.60, VectorRank: 2, SymbolScore: 1, SymbolRank: 1, MatchedSymbols: []string{"repository.hybridsearch"}},
+	}
+	w := RankingWeights{Vector: 0.7, Keyword: 0.3, Symbol: 0.3, RRFK: DefaultRRFK}
+	for _, strategy := range []RankingStrategy{RankingLinear, RankingRRF, RankingMax} {
+		ranker, _ := NewRanker(strategy, w)
+		results := scoreCandidates(ranker, scoreModifiers{}, cands, now, true)
+		if results[0].Memory.Title != "symbol" {
+			t.Errorf("%s: top result %q, want the exact symbol match", strategy, results[0].Memory.Title)
+		}
+		if e := results[0].Explanation; e.SymbolRank != 1 || len(e.MatchedSymbols) != 1 {
+			t.Errorf("%s: explanation misses the symbol match: %+v", strategy, e)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a memory is in the trash.
+const (
+	TrashReasonDeleted = "deleted"
+	TrashReasonExpired = "expired"
+)
+
+// TrashedMemory is a memory in the trash. It can be restored until PurgeAt,
+// when cleanup deletes it for good.
+type TrashedMemory struct {
+	Memory
+	DeletedAt     time.Time `json:"deleted_at"`
+	DeletedReason string    `json:"deleted_reason"`
+	PurgeAt       time.Time `json:"purge_at"`
+}
+
+// TrashFilter narrows a trash listing.
+type TrashFilter struct {
+	ProjectID *string
+	Reason    string // TrashReasonDeleted, TrashReasonExpired or empty for both
+}
+
+// TrashCursor is the position after a trashed memory, ordered by deletion
+// time descending, then id.
+type TrashCursor struct {
+	DeletedAt time.Time `json:"d"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// TrashPage is one page of the trash.
+type TrashPage struct {
+	Memories   []TrashedMemory `json:"memories"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// ListTrash lists the workspace's trash a page at a time, most recently
+// deleted first, continuing from cursor (empty for the first page).
+func (s *Service) ListTrash(ctx context.Context, f TrashFilter, limit int, cursor string) (*TrashPage, error) {
+	switch f.Reason {
+	case "", TrashReasonDeleted, TrashReasonExpired:
+	default:
+		return nil, fmt.Errorf("%w: %q (use %s or %s)", ErrInvalidTrashReason, f.Reason, TrashReasonDeleted, TrashReasonExpired)
+	}
+	var after *TrashCursor
+	if cursor != "" {
+		after = &TrashCursor{}
+		if err := DecodeCursor(cursor, after); err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if f.ProjectID != nil {
+		normalized := s.normalizeProject(*f.ProjectID)
+		f.ProjectID = &normalized
+	}
+
+	items, total, err := s.repo.ListTrash(ctx, f, limit+1, after, s.cfg.TrashRetention)
+	if err != nil {
+		return nil, err
+	}
+	page := &TrashPage{Memories: items, Total: total}
+	if len(items) > limit {
+		last := items[limit-1]
+		page.Memories = items[:limit]
+		page.NextCursor = EncodeCursor(TrashCursor{DeletedAt: last.DeletedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// RestoreFromTrash puts a trashed memory back, with its relationships and
+// history. An expired memory starts a new TTL.
+func (s *Service) RestoreFromTrash(ctx context.Context, id uuid.UUID) (*Memory, error) {
+	if err := s.repo.RestoreFromTrash(ctx, id, s.cfg.DefaultTTL); err != nil {
+		return nil, err
+	}
+	mem, err := s.repo.Get(ctx, id)
+	s.invalidateSearchCache(ctx, mem)
+	return mem, err
+}
+
+// PurgeTrash permanently deletes one trashed memory.
+func (s *Service) PurgeTrash(ctx context.Context, id uuid.UUID) error {
+	_, err := s.repo.PurgeTrash(ctx, &id)
+	return err
+}
+
+// EmptyTrash permanently deletes every trashed memory in the workspace and
+// returns how many there were.
+func (s *Service) EmptyTrash(ctx context.Context) (int64, error) {
+	return s.repo.PurgeTrash(ctx, nil)
+}
+
+// PurgeExpiredTrash permanently deletes memories that have been in the trash
+// longer than memory.trash_retention.
+func (s *Service) PurgeExpiredTrash(ctx context.Context) (int64, error) {
+	count, err := s.repo.PurgeExpiredTrash(ctx, s.cfg.TrashRetention)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		slog.Info("purged memories from trash", "count", count, "retention", s.cfg.TrashRetention)
+	}
+	return count, nil
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListTrash returns trashed memories matching f, most recently deleted
+// first, and how many match in total.
+func (r *Repository) ListTrash(ctx context.Context, f TrashFilter, limit int, after *TrashCursor, retention time.Duration) ([]TrashedMemory, int, error) {
+	conditions := []string{"m.workspace_id = $1", "m.deleted_at IS NOT NULL"}
+	args := []any{WorkspaceFromContext(ctx)}
+	argIdx := 2
+
+	if f.ProjectID != nil {
+		conditions = append(conditions, fmt.Sprintf("m.project_id = $%d", argIdx))
+		args = append(args, *f.ProjectID)
+		argIdx++
+	}
+	if f.Reason != "" {
+		conditions = append(conditions, fmt.Sprintf("m.deleted_reason = $%d", argIdx))
+		args = append(args, f.Reason)
+		argIdx++
+	}
+
+	var total int
+	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memories m WHERE "+strings.Join(conditions, " AND "), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count trash: %w", err)
+	}
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(m.deleted_at < $%d OR (m.deleted_at = $%d AND m.id > $%d))", argIdx, argIdx, argIdx+1))
+		args = append(args, after.DeletedAt, after.ID)
+		argIdx += 2
+	}
+	args = append(args, limit)
+
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+		SELECT m.id, m.title, m.content, m.summary, m.type, m.scope, m.project_id,
+		       m.agent_source, m.language::text, m.tags, m.importance, m.ttl_seconds, m.access_count,
+		       m.created_at, m.updated_at, m.expires_at, m.version, m.replaced_by,
+		       m.deleted_at, m.deleted_reason
+		FROM memories m
+		WHERE %s
+		ORDER BY m.deleted_at DESC, m.id
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), argIdx), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list trash: %w", err)
+	}
+	defer rows.Close()
+
+	items := []TrashedMemory{}
+	for rows.Next() {
+		var t TrashedMemory
+		m := &t.Memory
+		if err := rows.Scan(
+			&m.ID, &m.Title, &m.Content, &m.Summary, &m.Type, &m.Scope, &m.ProjectID,
+			&m.AgentSource, &m.Language, &m.Tags, &m.Importance, &m.TTLSeconds, &m.AccessCount,
+			&m.CreatedAt, &m.UpdatedAt, &m.ExpiresAt, &m.Version, &m.ReplacedBy,
+			&t.DeletedAt, &t.DeletedReason,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan trashed memory: %w", err)
+		}
+		t.PurgeAt = t.DeletedAt.Add(retention)
+		items = append(items, t)
+	}
+	return items, total, rows.Err()
+}
+
+// RestoreFromTrash takes a memory out of the trash. A memory that expired
+// gets a fresh TTL (its own, or defaultTTL seconds) so the next cleanup does
+// not trash it again; restoring counts as its last access for decay.
+func (r *Repository) RestoreFromTrash(ctx context.Context, id uuid.UUID, defaultTTL int) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE memories
+		SET deleted_at = NULL, deleted_reason = NULL, last_accessed_at = NOW(),
+		    expires_at = CASE
+		        WHEN expires_at IS NOT NULL AND expires_at <= NOW()
+		        THEN NOW() + make_interval(secs => COALESCE(ttl_seconds, $3))
+		        ELSE expires_at
+		    END
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL
+	`, id, WorkspaceFromContext(ctx), defaultTTL)
+	if err != nil {
+		return fmt.Errorf("restore from trash: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrNotInTrash, id)
+	}
+	return nil
+}
+
+// PurgeTrash permanently deletes trashed memories of the workspace: the one
+// with the given ID, or all of them when id is nil. Relationships, versions
+// and chunks go with them.
+func (r *Repository) PurgeTrash(ctx context.Context, id *uuid.UUID) (int64, error) {
+	result, err := r.pool.Exec(ctx, `
+		DELETE FROM memories
+		WHERE workspace_id = $1 AND deleted_at IS NOT NULL
+		  AND ($2::uuid IS NULL OR id = $2)
+	`, WorkspaceFromContext(ctx), id)
+	if err != nil {
+		return 0, fmt.Errorf("purge trash: %w", err)
+	}
+	if id != nil && result.RowsAffected() == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNotInTrash, *id)
+	}
+	return result.RowsAffected(), nil
+}
+
+// PurgeExpiredTrash permanently deletes memories trashed longer than
+// retention ago, in every workspace.
+func (r *Repository) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	result, err := r.pool.Exec(ctx, `
+		DELETE FROM memories
+		WHERE deleted_at IS NOT NULL AND deleted_at < NOW() - $1::INTERVAL
+	`, fmt.Sprintf("%d seconds", int(retention.Seconds())))
+	if err != nil {
+		return 0, fmt.Errorf("purge expired trash: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,17 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestListTrash_RejectsUnknownReason(t *testing.T) {
+	s := &Service{}
+	if _, err := s.ListTrash(context.Background(), TrashFilter{Reason: "archived"}, 10, ""); !errors.Is(err, ErrInvalidTrashReason) {
+		t.Fatalf("expected ErrInvalidTrashReason, got %v", err)
+	}
+	if _, err := s.ListTrash(context.Background(), TrashFilter{}, 10, "not-a-cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
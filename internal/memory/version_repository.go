@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// Snapshots are written by the memories_record_version trigger, so every
+// write path records history without going through this file.
+
+const memoryVersionColumns = `
+	id, memory_id, version_no, title, content, summary, type, scope, project_id,
+	tags, importance, change_reason, created_at`
+
+func scanMemoryVersion(row pgx.Row) (*MemoryVersion, error) {
+	v := &MemoryVersion{}
+	err := row.Scan(
+		&v.ID, &v.MemoryID, &v.VersionNo, &v.Title, &v.Content, &v.Summary, &v.Type, &v.Scope, &v.ProjectID,
+		&v.Tags, &v.Importance, &v.ChangeReason, &v.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ListVersions returns a memory's snapshots, newest first, and the total count.
+func (r *Repository) ListVersions(ctx context.Context, memoryID uuid.UUID, limit, offset int) ([]MemoryVersion, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM memory_versions WHERE memory_id = $1", memoryID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count memory versions: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+memoryVersionColumns+`
+		FROM memory_versions
+		WHERE memory_id = $1
+		ORDER BY version_no DESC
+		LIMIT $2 OFFSET $3
+	`, memoryID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list memory versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []MemoryVersion
+	for rows.Next() {
+		v, err := scanMemoryVersion(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan memory version: %w", err)
+		}
+		versions = append(versions, *v)
+	}
+	return versions, total, rows.Err()
+}
+
+// GetVersion returns one snapshot, or nil if it does not exist.
+func (r *Repository) GetVersion(ctx context.Context, memoryID uuid.UUID, versionNo int) (*MemoryVersion, error) {
+	v, err := scanMemoryVersion(r.pool.QueryRow(ctx, `
+		SELECT `+memoryVersionColumns+`
+		FROM memory_versions
+		WHERE memory_id = $1 AND version_no = $2
+	`, memoryID, versionNo))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get memory version: %w", err)
+	}
+	return v, nil
+}
+
+// LatestVersionNo returns the newest version number of a memory, or 0 if it
+// has no history.
+func (r *Repository) LatestVersionNo(ctx context.Context, memoryID uuid.UUID) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx,
+		"SELECT COALESCE(MAX(version_no), 0) FROM memory_versions WHERE memory_id = $1", memoryID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("get latest memory version: %w", err)
+	}
+	return n, nil
+}
+
+// RestoreVersion writes a snapshot's content fields back to the memory. The
+// trigger records the result as a new version labelled restore:vN.
+func (r *Repository) RestoreVersion(ctx context.Context, v *MemoryVersion, newEmbedding pgvector.Vector, embeddingModel string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin restore version: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	reason := fmt.Sprintf("restore:v%d", v.VersionNo)
+	if _, err := tx.Exec(ctx, "SELECT set_config('contextify.change_reason', $1, true)", reason); err != nil {
+		return fmt.Errorf("set change_reason: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE memories
+		SET title = $1, content = $2, summary = $3, type = $4, tags = $5, importance = $6,
+		    embedding = $7, embedding_model = $8, embedding_next = NULL, embedding_next_model = NULL
+		WHERE id = $9 AND workspace_id = $10
+	`, v.Title, v.Content, v.Summary, v.Type, v.Tags, v.Importance, newEmbedding, embeddingModel, v.MemoryID, WorkspaceFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("restore memory version: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrMemoryNotFound, v.MemoryID)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ListVersions returns a memory's history, newest first.
+func (s *Service) ListVersions(ctx context.Context, memoryID uuid.UUID, limit, offset int) ([]MemoryVersion, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if err := s.requireMemory(ctx, memoryID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListVersions(ctx, memoryID, limit, offset)
+}
+
+// DiffVersions returns a unified diff between two versions. A zero to
+// selects the latest version; a zero from selects the one before to.
+func (s *Service) DiffVersions(ctx context.Context, memoryID uuid.UUID, from, to int) (*VersionDiff, error) {
+	if err := s.requireMemory(ctx, memoryID); err != nil {
+		return nil, err
+	}
+	if to <= 0 {
+		latest, err := s.repo.LatestVersionNo(ctx, memoryID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrMemoryNotFound, memoryID)
+		}
+		to = latest
+	}
+	if from <= 0 {
+		from = max(to-1, 1)
+	}
+
+	fromV, err := s.getVersion(ctx, memoryID, from)
+	if err != nil {
+		return nil, err
+	}
+	toV, err := s.getVersion(ctx, memoryID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersionDiff{
+		MemoryID:    memoryID,
+		FromVersion: from,
+		ToVersion:   to,
+		Diff:        unifiedDiff(fmt.Sprintf("v%d", from), fmt.Sprintf("v%d", to), renderVersion(fromV), renderVersion(toV)),
+	}, nil
+}
+
+// RestoreVersion makes a past version current again. History is kept: the
+// restore is recorded as a new version.
+func (s *Service) RestoreVersion(ctx context.Context, memoryID uuid.UUID, versionNo int) (*Memory, error) {
+	if err := s.requireMemory(ctx, memoryID); err != nil {
+		return nil, err
+	}
+	v, err := s.getVersion(ctx, memoryID, versionNo)
+	if err != nil {
+		return nil, err
+	}
+
+	emb, model, err := s.embed(ctx, v.Title+" "+v.Content)
+	if err != nil {
+		return nil, fmt.Errorf("re-embed: %w", err)
+	}
+	if err := s.repo.RestoreVersion(ctx, v, emb, model); err != nil {
+		return nil, err
+	}
+	mem, err := s.repo.Get(ctx, memoryID)
+	s.invalidateSearchCache(ctx, mem)
+	return mem, err
+}
+
+func (s *Service) getVersion(ctx context.Context, memoryID uuid.UUID, versionNo int) (*MemoryVersion, error) {
+	v, err := s.repo.GetVersion(ctx, memoryID, versionNo)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("%w: %s v%d", ErrVersionNotFound, memoryID, versionNo)
+	}
+	return v, nil
+}
+
+// requireMemory returns ErrMemoryNotFound unless the memory exists in the
+// caller's workspace. Version rows carry no workspace of their own.
+func (s *Service) requireMemory(ctx context.Context, memoryID uuid.UUID) error {
+	mem, err := s.repo.Get(ctx, memoryID)
+	if err != nil {
+		return err
+	}
+	if mem == nil {
+		return fmt.Errorf("%w: %s", ErrMemoryNotFound, memoryID)
+	}
+	return nil
+}
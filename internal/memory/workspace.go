@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// DefaultWorkspace holds pre-existing data and every request made while
+// authentication is disabled.
+const DefaultWorkspace = "default"
+
+var workspacePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// ValidateWorkspace checks that id is a usable workspace identifier:
+// lowercase letters, digits, '-' and '_', at most 63 characters.
+func ValidateWorkspace(id string) error {
+	if !workspacePattern.MatchString(id) {
+		return fmt.Errorf("invalid workspace %q: use lowercase letters, digits, '-' or '_' (max 63)", id)
+	}
+	return nil
+}
+
+type workspaceKey struct{}
+
+// WithWorkspace scopes every memory read and write made with ctx to the
+// given workspace.
+func WithWorkspace(ctx context.Context, workspace string) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspace)
+}
+
+// WorkspaceFromContext returns the workspace set by WithWorkspace, or
+// DefaultWorkspace.
+func WorkspaceFromContext(ctx context.Context) string {
+	if ws, ok := ctx.Value(workspaceKey{}).(string); ok && ws != "" {
+		return ws
+	}
+	return DefaultWorkspace
+}
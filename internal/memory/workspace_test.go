@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWorkspaceFromContext(t *testing.T) {
+	if got := WorkspaceFromContext(context.Background()); got != DefaultWorkspace {
+		t.Fatalf("expected %q without a workspace, got %q", DefaultWorkspace, got)
+	}
+	ctx := WithWorkspace(context.Background(), "team-a")
+	if got := WorkspaceFromContext(ctx); got != "team-a" {
+		t.Fatalf("expected team-a, got %q", got)
+	}
+}
+
+func TestValidateWorkspace(t *testing.T) {
+	for _, id := range []string{"default", "team-a", "ops_2"} {
+		if err := ValidateWorkspace(id); err != nil {
+			t.Errorf("ValidateWorkspace(%q) = %v, want nil", id, err)
+		}
+	}
+	for _, id := range []string{"", "Team", "-lead", "a b", "x/y"} {
+		if err := ValidateWorkspace(id); err == nil {
+			t.Errorf("ValidateWorkspace(%q) = nil, want error", id)
+		}
+	}
+}
+
+func TestCacheKey_SeparatesWorkspaces(t *testing.T) {
+	req := SearchRequest{Query: "postgres timeout", Limit: 10}
+	a, err := cacheKey("team-a", req)
+	if err != nil {
+		t.Fatalf("cacheKey: %v", err)
+	}
+	b, err := cacheKey("team-b", req)
+	if err != nil {
+		t.Fatalf("cacheKey: %v", err)
+	}
+	if a == b {
+		t.Fatalf("expected different cache keys per workspace")
+	}
+}
@@ -2,53 +2,36 @@ package scheduler
 
 import (
 	"context"
-	"log/slog"
+	"errors"
 	"time"
 
 	"github.com/atakanatali/contextify/internal/memory"
 )
 
-type Cleanup struct {
-	svc      *memory.Service
-	interval time.Duration
-	stop     chan struct{}
-}
-
-func NewCleanup(svc *memory.Service, interval time.Duration) *Cleanup {
-	return &Cleanup{
-		svc:      svc,
-		interval: interval,
-		stop:     make(chan struct{}),
-	}
-}
-
-func (c *Cleanup) Start() {
-	slog.Info("starting TTL cleanup scheduler", "interval", c.interval)
-	go c.run()
-}
-
-func (c *Cleanup) Stop() {
-	close(c.stop)
-}
-
-func (c *Cleanup) run() {
-	ticker := time.NewTicker(c.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			count, err := c.svc.CleanupExpired(ctx)
-			cancel()
+// CleanupJob moves expired memories to the trash, applies retention rules
+// and purges trash past its retention. A failing step does not stop the
+// others.
+func CleanupJob(svc *memory.Service, interval time.Duration) Job {
+	return Job{
+		Name:        "cleanup",
+		Description: "Trash expired memories, apply retention rules and purge old trash",
+		Schedule:    Every(interval),
+		Timeout:     30 * time.Second,
+		Run: func(ctx context.Context) (map[string]any, error) {
+			var errs []error
+			expired, err := svc.CleanupExpired(ctx)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			retained, err := svc.EnforceRetention(ctx)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			purged, err := svc.PurgeExpiredTrash(ctx)
 			if err != nil {
-				slog.Error("cleanup failed", "error", err)
-			} else if count > 0 {
-				slog.Info("cleaned up expired memories", "count", count)
+				errs = append(errs, err)
 			}
-		case <-c.stop:
-			slog.Info("cleanup scheduler stopped")
-			return
-		}
+			return map[string]any{"expired": expired, "retention_trashed": retained, "purged": purged}, errors.Join(errs...)
+		},
 	}
 }
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/memory"
+)
+
+// EmbedderSync switches this instance's query embedder to the configured
+// model once the reembed job, which runs on the leader only, has completed
+// the cutover. It runs on every instance.
+type EmbedderSync struct {
+	svc      *memory.Service
+	interval time.Duration
+	stop     chan struct{}
+}
+
+func NewEmbedderSync(svc *memory.Service, interval time.Duration) *EmbedderSync {
+	return &EmbedderSync{
+		svc:      svc,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (e *EmbedderSync) Start() {
+	slog.Info("starting embedder sync", "interval", e.interval)
+	go e.run()
+}
+
+func (e *EmbedderSync) Stop() {
+	close(e.stop)
+}
+
+func (e *EmbedderSync) run() {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := e.svc.SyncActiveEmbedder(ctx); err != nil {
+				slog.Error("embedder sync failed", "error", err)
+			}
+			cancel()
+		case <-e.stop:
+			slog.Info("embedder sync stopped")
+			return
+		}
+	}
+}
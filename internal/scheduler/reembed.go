@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/memory"
+)
+
+// ReembedJobName is the name of the re-embed job, which starting a
+// migration triggers.
+const ReembedJobName = "reembed"
+
+const (
+	// reembedSweep is how often the re-embed job looks for work. It matches
+	// the back-off of chunk embedding when nothing is left.
+	reembedSweep = 30 * time.Second
+	// reembedRunFor bounds how long one run keeps taking batches.
+	reembedRunFor = 5 * time.Minute
+)
+
+// ReembedJob drives the background re-embedding migration, then embeds the
+// chunks of long memories that lack them. A run takes one batch of each per
+// interval until both are done or reembedRunFor has passed. Progress lives
+// in the database, so a new leader resumes where the last one stopped.
+func ReembedJob(svc *memory.Service, interval time.Duration) Job {
+	return Job{
+		Name:        ReembedJobName,
+		Description: "Re-embed memories for the active embedding model and embed missing chunks",
+		Schedule:    Every(reembedSweep),
+		Timeout:     reembedRunFor + 2*time.Minute,
+		RunOnStart:  true,
+		Run: func(ctx context.Context) (map[string]any, error) {
+			deadline := time.Now().Add(reembedRunFor)
+			var reembedded, chunked, batches int
+			result := func() map[string]any {
+				return map[string]any{"reembedded": reembedded, "chunked": chunked, "batches": batches}
+			}
+			for {
+				var errs []error
+				n, err := svc.ReembedStep(ctx)
+				if err != nil {
+					errs = append(errs, err)
+				}
+				c, err := svc.ChunkStep(ctx)
+				if err != nil {
+					errs = append(errs, err)
+				}
+				reembedded, chunked, batches = reembedded+n, chunked+c, batches+1
+				if err := errors.Join(errs...); err != nil {
+					return result(), err
+				}
+				if (n == 0 && c == 0) || time.Now().After(deadline) {
+					return result(), nil
+				}
+				select {
+				case <-time.After(interval):
+				case <-ctx.Done():
+					return result(), ctx.Err()
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runsKept is how many runs of each job the history keeps.
+const runsKept = 50
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// jobState is the persisted state of a job.
+type jobState struct {
+	Name               string
+	Paused             bool
+	NextRunAt          *time.Time
+	TriggerRequestedAt *time.Time
+	RunCount           int64
+	FailureCount       int64
+}
+
+// EnsureJobs creates the state rows of jobs seen for the first time.
+func (r *Repository) EnsureJobs(ctx context.Context, names []string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO scheduled_jobs (name) SELECT unnest($1::text[])
+		ON CONFLICT (name) DO NOTHING
+	`, names)
+	if err != nil {
+		return fmt.Errorf("ensure scheduled jobs: %w", err)
+	}
+	return nil
+}
+
+// ListJobStates returns the state of every job, by name.
+func (r *Repository) ListJobStates(ctx context.Context) (map[string]jobState, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT name, paused, next_run_at, trigger_requested_at, run_count, failure_count
+		FROM scheduled_jobs
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled jobs: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string]jobState{}
+	for rows.Next() {
+		var s jobState
+		if err := rows.Scan(&s.Name, &s.Paused, &s.NextRunAt, &s.TriggerRequestedAt, &s.RunCount, &s.FailureCount); err != nil {
+			return nil, fmt.Errorf("scan scheduled job: %w", err)
+		}
+		out[s.Name] = s
+	}
+	return out, rows.Err()
+}
+
+// SetPaused pauses or resumes a job.
+func (r *Repository) SetPaused(ctx context.Context, name string, paused bool) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO scheduled_jobs (name, paused) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET paused = EXCLUDED.paused, updated_at = NOW()
+	`, name, paused)
+	if err != nil {
+		return fmt.Errorf("set scheduled job paused: %w", err)
+	}
+	return nil
+}
+
+// RequestTrigger asks the leader to run a job as soon as it can. A
+// trigger already pending is kept.
+func (r *Repository) RequestTrigger(ctx context.Context, name string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO scheduled_jobs (name, trigger_requested_at) VALUES ($1, NOW())
+		ON CONFLICT (name) DO UPDATE
+		SET trigger_requested_at = COALESCE(scheduled_jobs.trigger_requested_at, NOW()), updated_at = NOW()
+	`, name)
+	if err != nil {
+		return fmt.Errorf("trigger scheduled job: %w", err)
+	}
+	return nil
+}
+
+// SetNextRun records when a job runs next.
+func (r *Repository) SetNextRun(ctx context.Context, name string, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE scheduled_jobs SET next_run_at = $2, updated_at = NOW() WHERE name = $1
+	`, name, at)
+	if err != nil {
+		return fmt.Errorf("set next run: %w", err)
+	}
+	return nil
+}
+
+// StartRun records the start of a run and consumes a pending manual
+// trigger, which the run answers.
+func (r *Repository) StartRun(ctx context.Context, name, triggeredBy string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		WITH job AS (
+			UPDATE scheduled_jobs SET trigger_requested_at = NULL, updated_at = NOW() WHERE name = $1
+		)
+		INSERT INTO scheduled_job_runs (job_name, triggered_by, status) VALUES ($1, $2, 'running')
+		RETURNING id
+	`, name, triggeredBy).Scan(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("start scheduled run: %w", err)
+	}
+	return id, nil
+}
+
+// FinishRun records the outcome of a run, schedules the next one and
+// trims the job's history to runsKept runs.
+func (r *Repository) FinishRun(ctx context.Context, name string, runID uuid.UUID, duration time.Duration, runErr error, result map[string]any, next time.Time) error {
+	status, errMsg := "succeeded", (*string)(nil)
+	if runErr != nil {
+		status = "failed"
+		msg := runErr.Error()
+		errMsg = &msg
+	}
+	var resultJSON []byte
+	if result != nil {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("encode run result: %w", err)
+		}
+		resultJSON = b
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin finish run: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE scheduled_job_runs
+		SET status = $2, finished_at = NOW(), duration_ms = $3, error = $4, result = $5
+		WHERE id = $1
+	`, runID, status, duration.Milliseconds(), errMsg, resultJSON); err != nil {
+		return fmt.Errorf("finish scheduled run: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE scheduled_jobs
+		SET run_count = run_count + 1,
+		    failure_count = failure_count + CASE WHEN $2 THEN 1 ELSE 0 END,
+		    next_run_at = $3,
+		    updated_at = NOW()
+		WHERE name = $1
+	`, name, runErr != nil, next); err != nil {
+		return fmt.Errorf("update scheduled job: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM scheduled_job_runs
+		WHERE job_name = $1 AND id NOT IN (
+			SELECT id FROM scheduled_job_runs WHERE job_name = $1 ORDER BY started_at DESC LIMIT $2
+		)
+	`, name, runsKept); err != nil {
+		return fmt.Errorf("trim scheduled runs: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// FailRunning marks runs left running by an instance that stopped as
+// failed.
+func (r *Repository) FailRunning(ctx context.Context) (int64, error) {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE scheduled_job_runs
+		SET status = 'failed', finished_at = NOW(), error = 'interrupted'
+		WHERE status = 'running'
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("fail interrupted runs: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// RecentRuns returns up to perJob most recent runs of each job, newest
+// first.
+func (r *Repository) RecentRuns(ctx context.Context, perJob int) (map[string][]Run, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT job_name, id, triggered_by, status, started_at, finished_at, duration_ms, error, result
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY job_name ORDER BY started_at DESC) AS rn
+			FROM scheduled_job_runs
+		) runs
+		WHERE rn <= $1
+		ORDER BY job_name, started_at DESC
+	`, perJob)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled runs: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string][]Run{}
+	for rows.Next() {
+		var name string
+		var run Run
+		var result []byte
+		if err := rows.Scan(&name, &run.ID, &run.TriggeredBy, &run.Status, &run.StartedAt, &run.FinishedAt, &run.DurationMs, &run.Error, &result); err != nil {
+			return nil, fmt.Errorf("scan scheduled run: %w", err)
+		}
+		if result != nil {
+			if err := json.Unmarshal(result, &run.Result); err != nil {
+				return nil, fmt.Errorf("decode run result: %w", err)
+			}
+		}
+		out[name] = append(out[name], run)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Wednesday 2025-01-15 10:07:30 UTC.
+	base := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"@every 5m", base.Add(5 * time.Minute)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2025, 1, 16, 3, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"5,45 10 * * *", time.Date(2025, 1, 15, 10, 45, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches.
+		{"0 0 20 * 5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		s, err := ParseSchedule(c.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q): %v", c.spec, err)
+		}
+		if got := s.Next(base); !got.Equal(c.want) {
+			t.Errorf("%q: Next = %v, want %v", c.spec, got, c.want)
+		}
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"", "@every", "@every soon", "@every 10ms", "@yearly",
+		"* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"*/0 * * * *", "5-1 * * * *", "a * * * *",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestParseSchedule_NeverMatches(t *testing.T) {
+	s, err := ParseSchedule("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("expected no run for February 31st, got %v", next)
+	}
+}
@@ -0,0 +1,507 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/steward"
+)
+
+// leaderLockKey is the advisory lock held by the instance that runs
+// scheduled jobs. It differs from the steward's so the two can lead from
+// different instances.
+const leaderLockKey int64 = 84201002
+
+// pollInterval is how often the scheduler looks for due jobs and manual
+// triggers.
+const pollInterval = 5 * time.Second
+
+// recentRunsShown is how many runs of each job List returns.
+const recentRunsShown = 5
+
+// Run statuses and triggers, as stored in scheduled_job_runs.
+const (
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+var ErrUnknownJob = errors.New("unknown scheduled job")
+
+// Job is a named background task run on a schedule.
+type Job struct {
+	Name        string
+	Description string
+	// Schedule is parsed by ParseSchedule; scheduler.jobs in the config
+	// overrides it by name.
+	Schedule string
+	// Timeout bounds a single run.
+	Timeout time.Duration
+	// RunOnStart runs the job as soon as an instance becomes leader, on top
+	// of its schedule.
+	RunOnStart bool
+	// Run does the work and returns a summary recorded with the run.
+	Run func(ctx context.Context) (map[string]any, error)
+}
+
+// Every returns the schedule of a job run every d.
+func Every(d time.Duration) string {
+	return "@every " + d.String()
+}
+
+type registeredJob struct {
+	Job
+	schedule Schedule
+}
+
+// Scheduler runs registered jobs on the instance holding its advisory lock,
+// at most one run of a job at a time. Pauses, manual triggers, the next run
+// time and the run history are stored in Postgres, so they survive restarts
+// and are shared by every instance.
+type Scheduler struct {
+	pool      *pgxpool.Pool
+	repo      *Repository
+	locks     *steward.Repository
+	jitter    time.Duration
+	overrides map[string]string
+
+	jobs   []*registeredJob
+	byName map[string]*registeredJob
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	wake   chan struct{}
+
+	mu        sync.Mutex
+	lockConn  *pgxpool.Conn
+	leader    bool
+	workerID  string
+	ensured   bool
+	running   map[string]bool
+	startRuns map[string]bool
+}
+
+func New(pool *pgxpool.Pool, cfg config.SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		pool:      pool,
+		repo:      NewRepository(pool),
+		locks:     steward.NewRepository(pool),
+		jitter:    cfg.Jitter,
+		overrides: cfg.Jobs,
+		byName:    map[string]*registeredJob{},
+		wake:      make(chan struct{}, 1),
+		workerID:  fmt.Sprintf("scheduler-%d", time.Now().UnixNano()),
+		running:   map[string]bool{},
+		startRuns: map[string]bool{},
+	}
+}
+
+// Register adds a job. It must be called before Start.
+func (s *Scheduler) Register(j Job) error {
+	if j.Name == "" || j.Run == nil {
+		return fmt.Errorf("register job: name and run are required")
+	}
+	if _, ok := s.byName[j.Name]; ok {
+		return fmt.Errorf("register job %s: already registered", j.Name)
+	}
+	if override, ok := s.overrides[j.Name]; ok {
+		j.Schedule = override
+	}
+	schedule, err := ParseSchedule(j.Schedule)
+	if err != nil {
+		return fmt.Errorf("register job %s: %w", j.Name, err)
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("register job %s: schedule %q never runs", j.Name, j.Schedule)
+	}
+	if j.Timeout <= 0 {
+		j.Timeout = time.Minute
+	}
+	rj := &registeredJob{Job: j, schedule: schedule}
+	s.jobs = append(s.jobs, rj)
+	s.byName[j.Name] = rj
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	for name := range s.overrides {
+		if _, ok := s.byName[name]; !ok {
+			slog.Warn("scheduler.jobs names an unknown job", "job", name)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(ctx)
+	}()
+	slog.Info("scheduler started", "jobs", len(s.jobs), "worker_id", s.workerID, "jitter", s.jitter)
+}
+
+// Stop cancels running jobs, waits for them to record their outcome and
+// gives up leadership.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	s.releaseLeaderLock(context.Background())
+	slog.Info("scheduler stopped")
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.poll(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			slog.Warn("scheduler poll failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// poll starts the jobs that are due or were triggered, if this instance
+// leads.
+func (s *Scheduler) poll(ctx context.Context) error {
+	if !s.isEnsured() {
+		if err := s.repo.EnsureJobs(ctx, s.jobNames()); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.ensured = true
+		s.mu.Unlock()
+	}
+	if err := s.checkLeader(ctx); err != nil {
+		return err
+	}
+	if !s.isLeader() {
+		return nil
+	}
+
+	states, err := s.repo.ListJobStates(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, j := range s.jobs {
+		st := states[j.Name]
+		if st.NextRunAt == nil {
+			next := s.nextRun(j, now)
+			if err := s.repo.SetNextRun(ctx, j.Name, next); err != nil {
+				return err
+			}
+			st.NextRunAt = &next
+		}
+
+		s.mu.Lock()
+		busy := s.running[j.Name]
+		onStart := s.startRuns[j.Name]
+		s.mu.Unlock()
+		if busy {
+			continue
+		}
+		switch {
+		case st.TriggerRequestedAt != nil:
+			s.startRun(ctx, j, TriggerManual)
+		case st.Paused:
+		case onStart || !st.NextRunAt.After(now):
+			s.startRun(ctx, j, TriggerSchedule)
+		}
+	}
+	return nil
+}
+
+// startRun runs j in its own goroutine and records the run.
+func (s *Scheduler) startRun(ctx context.Context, j *registeredJob, triggeredBy string) {
+	s.mu.Lock()
+	s.running[j.Name] = true
+	delete(s.startRuns, j.Name)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, j.Name)
+			s.mu.Unlock()
+		}()
+
+		runID, err := s.repo.StartRun(ctx, j.Name, triggeredBy)
+		if err != nil {
+			slog.Warn("failed to record scheduled run", "job", j.Name, "error", err)
+			return
+		}
+		started := time.Now()
+		result, runErr := s.execute(ctx, j)
+		duration := time.Since(started)
+		if runErr != nil {
+			slog.Error("scheduled job failed", "job", j.Name, "trigger", triggeredBy, "duration", duration, "error", runErr)
+		} else {
+			slog.Info("scheduled job finished", "job", j.Name, "trigger", triggeredBy, "duration", duration, "result", result)
+		}
+
+		// Record the outcome even when Stop cancelled the run.
+		finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		if err := s.repo.FinishRun(finishCtx, j.Name, runID, duration, runErr, result, s.nextRun(j, time.Now().UTC())); err != nil {
+			slog.Warn("failed to record scheduled run outcome", "job", j.Name, "error", err)
+		}
+	}()
+}
+
+// execute runs j under its timeout, turning a panic into an error.
+func (s *Scheduler) execute(ctx context.Context, j *registeredJob) (result map[string]any, err error) {
+	runCtx, cancel := context.WithTimeout(ctx, j.Timeout)
+	defer cancel()
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return j.Run(runCtx)
+}
+
+// nextRun is j's first scheduled time after t, delayed by a random jitter.
+func (s *Scheduler) nextRun(j *registeredJob, t time.Time) time.Time {
+	next := j.schedule.Next(t)
+	limit := jitterLimit(s.jitter, j.schedule.Next(next).Sub(next))
+	if limit > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(limit))))
+	}
+	return next
+}
+
+// jitterLimit caps jitter at a tenth of the gap between two runs, so short
+// intervals are not swamped by it.
+func jitterLimit(jitter, gap time.Duration) time.Duration {
+	if gap > 0 && gap/10 < jitter {
+		return gap / 10
+	}
+	return jitter
+}
+
+// checkLeader takes the leader lock if it is free, and drops leadership if
+// the connection holding it was lost.
+func (s *Scheduler) checkLeader(ctx context.Context) error {
+	s.mu.Lock()
+	conn := s.lockConn
+	s.mu.Unlock()
+	if conn != nil {
+		if err := conn.Ping(ctx); err == nil || ctx.Err() != nil {
+			return nil
+		}
+		slog.Warn("scheduler lost its leader lock connection", "worker_id", s.workerID)
+		s.mu.Lock()
+		s.lockConn = nil
+		s.leader = false
+		s.mu.Unlock()
+		conn.Release()
+	}
+
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire leader conn: %w", err)
+	}
+	ok, err := s.locks.TryAcquireLeaderLock(ctx, conn, leaderLockKey)
+	if err != nil {
+		conn.Release()
+		return err
+	}
+	if !ok {
+		conn.Release()
+		return nil
+	}
+	return s.becomeLeader(ctx, conn)
+}
+
+// becomeLeader takes over from the previous leader: runs it left unfinished
+// are marked failed, next run times a tighter schedule override would have
+// made earlier are moved up, and RunOnStart jobs are queued.
+func (s *Scheduler) becomeLeader(ctx context.Context, conn *pgxpool.Conn) error {
+	s.mu.Lock()
+	s.lockConn = conn
+	s.leader = true
+	for _, j := range s.jobs {
+		if j.RunOnStart {
+			s.startRuns[j.Name] = true
+		}
+	}
+	s.mu.Unlock()
+	slog.Info("scheduler leader lock acquired", "worker_id", s.workerID)
+
+	if n, err := s.repo.FailRunning(ctx); err != nil {
+		return err
+	} else if n > 0 {
+		slog.Info("scheduler marked interrupted runs failed", "count", n)
+	}
+	states, err := s.repo.ListJobStates(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, j := range s.jobs {
+		st := states[j.Name]
+		latest := j.schedule.Next(now).Add(s.jitter)
+		if st.NextRunAt != nil && st.NextRunAt.After(latest) {
+			if err := s.repo.SetNextRun(ctx, j.Name, s.nextRun(j, now)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) releaseLeaderLock(ctx context.Context) {
+	s.mu.Lock()
+	conn := s.lockConn
+	leader := s.leader
+	s.lockConn = nil
+	s.leader = false
+	s.mu.Unlock()
+	if !leader || conn == nil {
+		return
+	}
+	if err := s.locks.ReleaseLeaderLock(ctx, conn, leaderLockKey); err != nil {
+		slog.Warn("failed to release scheduler leader lock", "error", err)
+	}
+	conn.Release()
+}
+
+func (s *Scheduler) isLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leader
+}
+
+func (s *Scheduler) isEnsured() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ensured
+}
+
+func (s *Scheduler) jobNames() []string {
+	names := make([]string, len(s.jobs))
+	for i, j := range s.jobs {
+		names[i] = j.Name
+	}
+	return names
+}
+
+// Run is one recorded run of a job.
+type Run struct {
+	ID          uuid.UUID      `json:"id"`
+	TriggeredBy string         `json:"triggered_by"`
+	Status      string         `json:"status"`
+	StartedAt   time.Time      `json:"started_at"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty"`
+	DurationMs  *int64         `json:"duration_ms,omitempty"`
+	Error       *string        `json:"error,omitempty"`
+	Result      map[string]any `json:"result,omitempty"`
+}
+
+// JobStatus is a job's schedule and state as of List.
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Description    string     `json:"description,omitempty"`
+	Schedule       string     `json:"schedule"`
+	Timeout        string     `json:"timeout"`
+	Paused         bool       `json:"paused"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	TriggerPending bool       `json:"trigger_pending"`
+	RunCount       int64      `json:"run_count"`
+	FailureCount   int64      `json:"failure_count"`
+	LastRun        *Run       `json:"last_run,omitempty"`
+	RecentRuns     []Run      `json:"recent_runs"`
+}
+
+// Status is what GET /api/v1/admin/schedules returns. Leader tells whether
+// the instance that answered runs the jobs.
+type Status struct {
+	Leader   bool        `json:"leader"`
+	WorkerID string      `json:"worker_id"`
+	Jitter   string      `json:"jitter"`
+	Jobs     []JobStatus `json:"jobs"`
+}
+
+// List returns every registered job, by name, with its latest runs.
+func (s *Scheduler) List(ctx context.Context) (*Status, error) {
+	states, err := s.repo.ListJobStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := s.repo.RecentRuns(ctx, recentRunsShown)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Status{Leader: s.isLeader(), WorkerID: s.workerID, Jitter: s.jitter.String(), Jobs: []JobStatus{}}
+	for _, j := range s.jobs {
+		st := states[j.Name]
+		js := JobStatus{
+			Name:           j.Name,
+			Description:    j.Description,
+			Schedule:       j.Schedule,
+			Timeout:        j.Timeout.String(),
+			Paused:         st.Paused,
+			NextRunAt:      st.NextRunAt,
+			TriggerPending: st.TriggerRequestedAt != nil,
+			RunCount:       st.RunCount,
+			FailureCount:   st.FailureCount,
+			RecentRuns:     runs[j.Name],
+		}
+		if js.RecentRuns == nil {
+			js.RecentRuns = []Run{}
+		}
+		if len(js.RecentRuns) > 0 {
+			js.LastRun = &js.RecentRuns[0]
+		}
+		out.Jobs = append(out.Jobs, js)
+	}
+	sort.Slice(out.Jobs, func(a, b int) bool { return out.Jobs[a].Name < out.Jobs[b].Name })
+	return out, nil
+}
+
+// Pause stops or resumes the scheduled runs of a job. A paused job still
+// runs when triggered.
+func (s *Scheduler) Pause(ctx context.Context, name string, paused bool) error {
+	if _, ok := s.byName[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	return s.repo.SetPaused(ctx, name, paused)
+}
+
+// Trigger asks the leader to run a job now. The request is stored, so it
+// is picked up by whichever instance leads, within a poll interval.
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	if _, ok := s.byName[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	if err := s.repo.RequestTrigger(ctx, name); err != nil {
+		return err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atakanatali/contextify/internal/config"
+)
+
+func noop(context.Context) (map[string]any, error) { return nil, nil }
+
+func TestRegister(t *testing.T) {
+	s := New(nil, config.SchedulerConfig{Jobs: map[string]string{"override": "0 3 * * *"}})
+
+	if err := s.Register(Job{Name: "cleanup", Schedule: Every(5 * time.Minute), Run: noop}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := s.Register(Job{Name: "cleanup", Schedule: "@hourly", Run: noop}); err == nil {
+		t.Errorf("expected an error registering a job twice")
+	}
+	if err := s.Register(Job{Name: "bad", Schedule: "every 5m", Run: noop}); err == nil {
+		t.Errorf("expected an error for an invalid schedule")
+	}
+	if err := s.Register(Job{Name: "never", Schedule: "0 0 30 2 *", Run: noop}); err == nil {
+		t.Errorf("expected an error for a schedule that never runs")
+	}
+	if err := s.Register(Job{Name: "norun", Schedule: "@hourly"}); err == nil {
+		t.Errorf("expected an error for a job without a run func")
+	}
+
+	if err := s.Register(Job{Name: "override", Schedule: "@hourly", Run: noop}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	j := s.byName["override"]
+	if j.Schedule != "0 3 * * *" {
+		t.Errorf("schedule = %q, want the config override", j.Schedule)
+	}
+	if j.Timeout != time.Minute {
+		t.Errorf("timeout = %v, want the 1m default", j.Timeout)
+	}
+}
+
+func TestJitterLimit(t *testing.T) {
+	cases := []struct {
+		jitter, gap, want time.Duration
+	}{
+		{30 * time.Second, time.Hour, 30 * time.Second},
+		{30 * time.Second, time.Minute, 6 * time.Second},
+		{0, time.Hour, 0},
+	}
+	for _, c := range cases {
+		if got := jitterLimit(c.jitter, c.gap); got != c.want {
+			t.Errorf("jitterLimit(%v, %v) = %v, want %v", c.jitter, c.gap, got, c.want)
+		}
+	}
+}
+
+func TestNextRun_Jitter(t *testing.T) {
+	s := New(nil, config.SchedulerConfig{Jitter: 30 * time.Second})
+	if err := s.Register(Job{Name: "minutely", Schedule: "* * * * *", Run: noop}); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC)
+	slot := time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		next := s.nextRun(s.byName["minutely"], base)
+		if next.Before(slot) || !next.Before(slot.Add(6*time.Second)) {
+			t.Fatalf("next run %v outside [%v, +6s)", next, slot)
+		}
+	}
+}
@@ -119,7 +119,7 @@ func (e *DerivationExecutor) Execute(ctx context.Context, job Job) (*ExecutionRe
 			_, _ = e.svc.CreateRelationship(ctx, memory.RelationshipRequest{
 				FromMemoryID: derivedID,
 				ToMemoryID:   src,
-				Relationship: "DERIVED_FROM",
+				Relationship: memory.RelDerivedFrom,
 				Strength:     0.9,
 			})
 		}
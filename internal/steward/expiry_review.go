@@ -0,0 +1,102 @@
+package steward
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/memory"
+)
+
+const JobReviewExpiring = "review_expiring"
+
+// expiryLetExpire is the review decision that leaves a memory to expire.
+const expiryLetExpire = "let_expire"
+
+// ExpiryReviewExecutor decides on a memory in the expiry review queue from
+// how often it was read. A job is queued once per memory and access count,
+// so a kept memory is only kept again if it is read before it next expires.
+type ExpiryReviewExecutor struct {
+	svc    *memory.Service
+	cfg    *config.StewardExpiryReview
+	dryRun bool
+}
+
+func NewExpiryReviewExecutor(svc *memory.Service, cfg *config.StewardExpiryReview, dryRun bool) *ExpiryReviewExecutor {
+	if cfg == nil {
+		empty := config.StewardExpiryReview{}
+		cfg = &empty
+	}
+	return &ExpiryReviewExecutor{svc: svc, cfg: cfg, dryRun: dryRun}
+}
+
+func (e *ExpiryReviewExecutor) Execute(ctx context.Context, job Job) (*ExecutionResult, error) {
+	if !e.cfg.Enabled {
+		return &ExecutionResult{Status: JobSucceeded, Decision: "expiry_review_disabled", Output: map[string]any{"enabled": false}}, nil
+	}
+	memoryID, err := parseInferRelationshipsPayload(job.Payload, job.SourceMemoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	mem, err := e.svc.Peek(ctx, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	if mem == nil || mem.ReplacedBy != nil || mem.TTLSeconds == nil {
+		return &ExecutionResult{
+			Status:      JobSucceeded,
+			Decision:    "skip_not_expiring",
+			Output:      map[string]any{"memory_id": memoryID},
+			SideEffects: []map[string]any{{"type": "expiry_review_skip", "memory_id": memoryID, "reason": "missing_replaced_or_long_term"}},
+		}, nil
+	}
+
+	action := decideExpiry(mem.AccessCount, e.cfg)
+	effect := map[string]any{"memory_id": memoryID, "access_count": mem.AccessCount, "action": action}
+	result := &ExecutionResult{
+		Status: JobSucceeded,
+		Output: map[string]any{"memory_id": memoryID, "access_count": mem.AccessCount, "expires_at": mem.ExpiresAt, "action": action},
+	}
+	switch {
+	case action == expiryLetExpire:
+		effect["type"] = "expiry_left"
+		result.Decision = expiryLetExpire
+	case e.dryRun:
+		effect["type"] = "expiry_review_proposed"
+		effect["reason"] = "dry_run"
+		result.Decision = "dry_run_expiry_review"
+	default:
+		review, err := e.svc.ReviewExpiring(ctx, memoryID, action)
+		if errors.Is(err, memory.ErrMemoryNotFound) || errors.Is(err, memory.ErrNotExpiring) {
+			// Expired, deleted or promoted since the job was claimed.
+			effect["type"] = "expiry_review_skip"
+			effect["reason"] = err.Error()
+			result.Decision = "skip_not_expiring"
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		effect["type"] = "expiry_reviewed"
+		effect["action"] = review.Action
+		if review.ExpiresAt != nil {
+			effect["expires_at"] = review.ExpiresAt
+		}
+		result.Decision = "expiry_" + review.Action
+	}
+	result.SideEffects = []map[string]any{effect}
+	return result, nil
+}
+
+// decideExpiry promotes a memory read at least PromoteAccessCount times,
+// keeps one read at least KeepAccessCount times and lets the rest expire.
+func decideExpiry(accessCount int, cfg *config.StewardExpiryReview) string {
+	switch {
+	case accessCount >= cfg.PromoteAccessCount:
+		return memory.ReviewPromote
+	case accessCount >= cfg.KeepAccessCount:
+		return memory.ReviewKeep
+	}
+	return expiryLetExpire
+}
@@ -0,0 +1,27 @@
+package steward
+
+import (
+	"testing"
+
+	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/memory"
+)
+
+func TestDecideExpiry(t *testing.T) {
+	cfg := &config.StewardExpiryReview{Enabled: true, KeepAccessCount: 1, PromoteAccessCount: 3}
+	cases := []struct {
+		accesses int
+		want     string
+	}{
+		{0, expiryLetExpire},
+		{1, memory.ReviewKeep},
+		{2, memory.ReviewKeep},
+		{3, memory.ReviewPromote},
+		{10, memory.ReviewPromote},
+	}
+	for _, c := range cases {
+		if got := decideExpiry(c.accesses, cfg); got != c.want {
+			t.Errorf("decideExpiry(%d) = %q, want %q", c.accesses, got, c.want)
+		}
+	}
+}
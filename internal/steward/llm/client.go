@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -95,8 +97,21 @@ func (c *Client) DecideMerge(ctx context.Context, in MergeDecisionInput) (*Merge
 }
 
 func (c *Client) decideMergeOnce(ctx context.Context, model string, in MergeDecisionInput) (*MergeDecision, *DecisionMetrics, error) {
+	content, metrics, err := c.chatJSON(ctx, model, buildPrompt(in))
+	if err != nil {
+		return nil, nil, err
+	}
+	decision, err := ParseAndValidateDecision(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decision, metrics, nil
+}
+
+// chatJSON sends a single-turn prompt in JSON mode and returns the model's
+// reply with usage metrics.
+func (c *Client) chatJSON(ctx context.Context, model, prompt string) ([]byte, *DecisionMetrics, error) {
 	start := time.Now()
-	prompt := buildPrompt(in)
 	reqBody := ollamaChatRequest{
 		Model:  model,
 		Stream: false,
@@ -129,10 +144,6 @@ func (c *Client) decideMergeOnce(ctx context.Context, model string, in MergeDeci
 	if err := json.Unmarshal(body, &out); err != nil {
 		return nil, nil, fmt.Errorf("decode llm response: %w", err)
 	}
-	decision, err := ParseAndValidateDecision([]byte(out.Message.Content))
-	if err != nil {
-		return nil, nil, err
-	}
 	lat := int(time.Since(start).Milliseconds())
 	metrics := &DecisionMetrics{
 		Provider:         "ollama",
@@ -142,7 +153,7 @@ func (c *Client) decideMergeOnce(ctx context.Context, model string, in MergeDeci
 		TotalTokens:      sumPtrs(out.PromptEvalCount, out.EvalCount),
 		LatencyMs:        &lat,
 	}
-	return decision, metrics, nil
+	return []byte(out.Message.Content), metrics, nil
 }
 
 func ParseAndValidateDecision(raw []byte) (*MergeDecision, error) {
@@ -170,6 +181,130 @@ func buildPrompt(in MergeDecisionInput) string {
 	return "Analyze duplicate merge risk and return JSON with keys: is_duplicate, has_conflict, decision, confidence, recommended_strategy, merged_title, merged_content, reason_codes. Input: " + string(b)
 }
 
+type RelationshipDecisionInput struct {
+	SourceTitle   string   `json:"source_title"`
+	SourceType    string   `json:"source_type"`
+	SourceContent string   `json:"source_content"`
+	TargetTitle   string   `json:"target_title"`
+	TargetType    string   `json:"target_type"`
+	TargetContent string   `json:"target_content"`
+	Similarity    float64  `json:"similarity"`
+	AllowedTypes  []string `json:"allowed_types"`
+	Suggested     string   `json:"suggested,omitempty"`
+}
+
+// RelationshipDecision is the model's verdict on an edge from source to
+// target. Relationship is one of the allowed types or "NONE".
+type RelationshipDecision struct {
+	Relationship string   `json:"relationship"`
+	Confidence   float64  `json:"confidence"`
+	ReasonCodes  []string `json:"reason_codes"`
+}
+
+func (c *Client) DecideRelationship(ctx context.Context, in RelationshipDecisionInput) (*RelationshipDecision, *DecisionMetrics, error) {
+	decision, metrics, err := c.decideRelationshipOnce(ctx, c.model, in)
+	if err == nil {
+		return decision, metrics, nil
+	}
+	return c.decideRelationshipOnce(ctx, c.fallback, in)
+}
+
+func (c *Client) decideRelationshipOnce(ctx context.Context, model string, in RelationshipDecisionInput) (*RelationshipDecision, *DecisionMetrics, error) {
+	b, _ := json.Marshal(in)
+	prompt := "Decide how the source memory relates to the target memory. Return JSON with keys: relationship (one of allowed_types, or NONE if they are not meaningfully related), confidence, reason_codes. Input: " + string(b)
+	content, metrics, err := c.chatJSON(ctx, model, prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+	decision, err := ParseAndValidateRelationshipDecision(content, in.AllowedTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decision, metrics, nil
+}
+
+func ParseAndValidateRelationshipDecision(raw []byte, allowed []string) (*RelationshipDecision, error) {
+	var d RelationshipDecision
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return nil, fmt.Errorf("parse relationship decision json: %w", err)
+	}
+	d.Relationship = strings.ToUpper(strings.TrimSpace(d.Relationship))
+	if d.Relationship != "NONE" && !slices.Contains(allowed, d.Relationship) {
+		return nil, fmt.Errorf("invalid relationship %q", d.Relationship)
+	}
+	if d.Confidence < 0 || d.Confidence > 1 {
+		return nil, fmt.Errorf("invalid confidence")
+	}
+	if d.ReasonCodes == nil {
+		d.ReasonCodes = []string{}
+	}
+	return &d, nil
+}
+
+// RerankDocument is one search candidate shown to the reranking model.
+type RerankDocument struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+type RerankInput struct {
+	Query     string           `json:"query"`
+	Documents []RerankDocument `json:"documents"`
+}
+
+type rerankResponse struct {
+	Scores []struct {
+		ID        string  `json:"id"`
+		Relevance float64 `json:"relevance"`
+	} `json:"scores"`
+}
+
+// Rerank asks the model how relevant each document is to the query and
+// returns the relevance (0-1) by document ID. Unlike the steward decisions
+// there is no fallback model: search callers bound the call with a deadline
+// and keep their own order on error.
+func (c *Client) Rerank(ctx context.Context, in RerankInput) (map[string]float64, *DecisionMetrics, error) {
+	b, _ := json.Marshal(in)
+	prompt := "Rate how relevant each document is to the search query, from 0 (unrelated) to 1 (directly answers it). Return JSON with key scores: a list of {id, relevance} covering every document. Input: " + string(b)
+	content, metrics, err := c.chatJSON(ctx, c.model, prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, len(in.Documents))
+	for i, d := range in.Documents {
+		ids[i] = d.ID
+	}
+	scores, err := ParseAndValidateRerank(content, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return scores, metrics, nil
+}
+
+// ParseAndValidateRerank reads a rerank reply. Scores for unknown IDs are
+// dropped; documents the model skipped are simply absent from the result.
+func ParseAndValidateRerank(raw []byte, ids []string) (map[string]float64, error) {
+	var r rerankResponse
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("parse rerank json: %w", err)
+	}
+	scores := make(map[string]float64, len(r.Scores))
+	for _, s := range r.Scores {
+		if !slices.Contains(ids, s.ID) {
+			continue
+		}
+		if s.Relevance < 0 || s.Relevance > 1 {
+			return nil, fmt.Errorf("invalid relevance for %q", s.ID)
+		}
+		scores[s.ID] = s.Relevance
+	}
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("rerank returned no scores")
+	}
+	return scores, nil
+}
+
 func sumPtrs(a, b *int) *int {
 	if a == nil && b == nil {
 		return nil
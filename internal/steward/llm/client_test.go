@@ -26,3 +26,40 @@ func TestParseAndValidateDecision_InvalidDecision(t *testing.T) {
 		t.Fatalf("expected validation error")
 	}
 }
+
+func TestParseAndValidateRelationshipDecision(t *testing.T) {
+	allowed := []string{"SOLVES", "RELATED_TO"}
+	d, err := ParseAndValidateRelationshipDecision([]byte(`{"relationship":"solves","confidence":0.8}`), allowed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Relationship != "SOLVES" || d.ReasonCodes == nil {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+	if _, err := ParseAndValidateRelationshipDecision([]byte(`{"relationship":"NONE","confidence":0.9}`), allowed); err != nil {
+		t.Fatalf("NONE should be accepted: %v", err)
+	}
+	if _, err := ParseAndValidateRelationshipDecision([]byte(`{"relationship":"CAUSES","confidence":0.9}`), allowed); err == nil {
+		t.Fatalf("expected error for a type outside allowed_types")
+	}
+	if _, err := ParseAndValidateRelationshipDecision([]byte(`{"relationship":"SOLVES","confidence":1.5}`), allowed); err == nil {
+		t.Fatalf("expected error for out-of-range confidence")
+	}
+}
+
+func TestParseAndValidateRerank(t *testing.T) {
+	ids := []string{"1", "2", "3"}
+	scores, err := ParseAndValidateRerank([]byte(`{"scores":[{"id":"1","relevance":0.2},{"id":"3","relevance":0.9},{"id":"9","relevance":1}]}`), ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scores) != 2 || scores["3"] != 0.9 || scores["1"] != 0.2 {
+		t.Fatalf("unexpected scores: %v", scores)
+	}
+	if _, err := ParseAndValidateRerank([]byte(`{"scores":[{"id":"1","relevance":1.5}]}`), ids); err == nil {
+		t.Fatalf("expected error for out-of-range relevance")
+	}
+	if _, err := ParseAndValidateRerank([]byte(`{"scores":[]}`), ids); err == nil {
+		t.Fatalf("expected error for an empty reply")
+	}
+}
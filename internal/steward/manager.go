@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/embedding"
 	"github.com/atakanatali/contextify/internal/memory"
 	stewardllm "github.com/atakanatali/contextify/internal/steward/llm"
 )
@@ -21,6 +22,7 @@ const leaderLockKey int64 = 84201001
 type Manager struct {
 	pool      *pgxpool.Pool
 	svc       *memory.Service
+	embedder  embedding.Embedder
 	cfg       config.StewardConfig
 	ollamaURL string
 
@@ -52,7 +54,7 @@ type circuitBreakerState struct {
 	LastProbeSuccessAt  *time.Time `json:"last_probe_success_at,omitempty"`
 }
 
-func NewManager(pool *pgxpool.Pool, svc *memory.Service, cfg config.StewardConfig, embeddingOllamaURL string) *Manager {
+func NewManager(pool *pgxpool.Pool, svc *memory.Service, embedder embedding.Embedder, cfg config.StewardConfig, embeddingOllamaURL string) *Manager {
 	ollamaURL := cfg.OllamaURL
 	if ollamaURL == "" {
 		ollamaURL = embeddingOllamaURL
@@ -60,6 +62,7 @@ func NewManager(pool *pgxpool.Pool, svc *memory.Service, cfg config.StewardConfi
 	return &Manager{
 		pool:      pool,
 		svc:       svc,
+		embedder:  embedder,
 		cfg:       cfg,
 		ollamaURL: ollamaURL,
 		repo:      NewRepository(pool),
@@ -79,6 +82,12 @@ func (m *Manager) Start() {
 	}
 	m.registry.Register("auto_merge_from_suggestion", NewAutoMergeSuggestionExecutorWithGuard(m.repo, m.svc, m.cfg.DryRun, llmClient, m.llmAllowed))
 	m.registry.Register("derive_memories", NewDerivationExecutorFromPtr(m.repo, m.svc, &m.cfg.Derivation))
+	var relLLM *stewardllm.Client
+	if m.cfg.Relationships.UseLLM {
+		relLLM = stewardllm.NewClient(m.ollamaURL, m.cfg.Model)
+	}
+	m.registry.Register(JobInferRelationships, NewRelationshipInferenceExecutor(m.svc, &m.cfg.Relationships, m.cfg.DryRun, relLLM, m.llmAllowed))
+	m.registry.Register(JobReviewExpiring, NewExpiryReviewExecutor(m.svc, &m.cfg.ExpiryReview, m.cfg.DryRun))
 	m.registry.Register("policy_tune", NewPolicyTuneExecutor(m))
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancel = cancel
@@ -149,6 +158,20 @@ func (m *Manager) tick(ctx context.Context, leaseDuration time.Duration) error {
 			slog.Debug("enqueued auto-merge suggestion jobs", "count", n)
 		}
 	}
+	if m.cfg.Relationships.Enabled {
+		if n, err := m.repo.EnqueueRelationshipInferenceJobs(ctx, m.cfg.Relationships.Lookback, m.cfg.MaxAttempts, m.cfg.ClaimBatchSize*4, m.maxQueuedTotal()); err != nil {
+			slog.Warn("failed to enqueue relationship inference jobs", "error", err)
+		} else if n > 0 {
+			slog.Debug("enqueued relationship inference jobs", "count", n)
+		}
+	}
+	if m.cfg.ExpiryReview.Enabled {
+		if n, err := m.repo.EnqueueExpiryReviewJobs(ctx, m.svc.ReviewWindow(), m.cfg.MaxAttempts, m.cfg.ClaimBatchSize*4, m.maxQueuedTotal()); err != nil {
+			slog.Warn("failed to enqueue expiry review jobs", "error", err)
+		} else if n > 0 {
+			slog.Debug("enqueued expiry review jobs", "count", n)
+		}
+	}
 	if m.cfg.SelfLearn.Enabled && m.cfg.SelfLearn.EvalInterval > 0 {
 		m.mu.Lock()
 		due := m.lastPolicyEval.IsZero() || time.Since(m.lastPolicyEval) >= m.cfg.SelfLearn.EvalInterval
@@ -189,6 +212,7 @@ func (m *Manager) tick(ctx context.Context, leaseDuration time.Duration) error {
 func (m *Manager) executeJob(parent context.Context, job Job) error {
 	jobCtx, cancel := context.WithTimeout(parent, m.cfg.RequestTimeout)
 	defer cancel()
+	jobCtx = memory.WithWorkspace(jobCtx, job.WorkspaceID)
 
 	run, err := m.repo.CreateRun(jobCtx, job, "steward", m.cfg.Model)
 	if err != nil {
@@ -322,6 +346,7 @@ type Status struct {
 	WorkerID                  string              `json:"worker_id"`
 	TickInterval              time.Duration       `json:"tick_interval"`
 	Model                     string              `json:"model"`
+	EmbeddingModel            string              `json:"embedding_model"`
 	Health                    QueueHealthSummary  `json:"health"`
 	StartupRecoveredStaleJobs int64               `json:"startup_recovered_stale_jobs"`
 	CircuitBreaker            circuitBreakerState `json:"circuit_breaker"`
@@ -339,6 +364,7 @@ func (m *Manager) GetStatus() Status {
 		WorkerID:                  m.workerID,
 		TickInterval:              m.cfg.TickInterval,
 		Model:                     m.cfg.Model,
+		EmbeddingModel:            m.embeddingModel(),
 		Health:                    m.health,
 		StartupRecoveredStaleJobs: m.startupRecoveredStaleJobs,
 		CircuitBreaker:            m.breaker,
@@ -349,6 +375,13 @@ func (m *Manager) GetStatus() Status {
 	}
 }
 
+func (m *Manager) embeddingModel() string {
+	if m.embedder == nil {
+		return ""
+	}
+	return m.embedder.Model()
+}
+
 func (m *Manager) SetMode(paused, dryRun bool) Status {
 	m.mu.Lock()
 	m.paused = paused
@@ -365,6 +398,40 @@ func (m *Manager) ListRuns(ctx context.Context, f RunFilters) ([]Run, error) {
 	return m.repo.ListRuns(ctx, f)
 }
 
+// ListRunsPage lists f.Limit runs after cursor (empty for the first page),
+// with the total count when includeTotal is set.
+func (m *Manager) ListRunsPage(ctx context.Context, f RunFilters, cursor string, includeTotal bool) (*RunPage, error) {
+	if cursor != "" {
+		f.After = &RunCursor{}
+		if err := memory.DecodeCursor(cursor, f.After); err != nil {
+			return nil, err
+		}
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	f.Limit, f.Offset = limit+1, 0
+	runs, err := m.repo.ListRuns(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	page := &RunPage{Runs: runs}
+	if len(runs) > limit {
+		last := runs[limit-1]
+		page.Runs = runs[:limit]
+		page.NextCursor = memory.EncodeCursor(RunCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	if includeTotal {
+		total, err := m.repo.CountRuns(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+	return page, nil
+}
+
 func (m *Manager) ListEventsByJob(ctx context.Context, jobID uuid.UUID, limit, offset int) ([]Event, error) {
 	return m.repo.ListEventsByJob(ctx, jobID, limit, offset)
 }
@@ -401,8 +468,8 @@ func (m *Manager) RetryJob(ctx context.Context, id uuid.UUID) error {
 	_, err := m.pool.Exec(ctx, `
 		UPDATE steward_jobs
 		SET status = 'queued', run_after = NOW(), locked_by = NULL, locked_at = NULL, lease_expires_at = NULL, updated_at = NOW()
-		WHERE id = $1 AND status IN ('failed','dead_letter','cancelled')
-	`, id)
+		WHERE id = $1 AND status IN ('failed','dead_letter','cancelled') AND workspace_id = $2
+	`, id, memory.WorkspaceFromContext(ctx))
 	return err
 }
 
@@ -410,8 +477,8 @@ func (m *Manager) CancelJob(ctx context.Context, id uuid.UUID) error {
 	_, err := m.pool.Exec(ctx, `
 		UPDATE steward_jobs
 		SET status = 'cancelled', cancelled_at = NOW(), locked_by = NULL, locked_at = NULL, lease_expires_at = NULL, updated_at = NOW()
-		WHERE id = $1 AND status IN ('queued','running')
-	`, id)
+		WHERE id = $1 AND status IN ('queued','running') AND workspace_id = $2
+	`, id, memory.WorkspaceFromContext(ctx))
 	return err
 }
 
@@ -0,0 +1,267 @@
+package steward
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/memory"
+	"github.com/atakanatali/contextify/internal/steward/llm"
+)
+
+const JobInferRelationships = "infer_relationships"
+
+// Typed edges are worth proposing at a lower similarity than RELATED_TO, since
+// the type pair itself is evidence.
+const typedRelationshipBonus = 0.1
+
+// inferableRelationships are the types the inference job may propose.
+var inferableRelationships = []string{memory.RelSolves, memory.RelAddresses, memory.RelRelatedTo}
+
+// RelationshipInferenceExecutor links a new memory to similar existing ones.
+// Candidates come from embedding similarity; the edge type comes from the
+// memory types (a fix SOLVES an error, a decision ADDRESSES a problem) and,
+// when enabled, is confirmed or rejected by the steward model.
+type RelationshipInferenceExecutor struct {
+	svc        *memory.Service
+	cfg        *config.StewardRelationships
+	dryRun     bool
+	llm        *llm.Client
+	llmAllowed func() bool
+}
+
+func NewRelationshipInferenceExecutor(svc *memory.Service, cfg *config.StewardRelationships, dryRun bool, llmClient *llm.Client, llmAllowed func() bool) *RelationshipInferenceExecutor {
+	if cfg == nil {
+		empty := config.StewardRelationships{}
+		cfg = &empty
+	}
+	return &RelationshipInferenceExecutor{svc: svc, cfg: cfg, dryRun: dryRun, llm: llmClient, llmAllowed: llmAllowed}
+}
+
+// proposedEdge is one inferred relationship, oriented from From to To.
+type proposedEdge struct {
+	From         uuid.UUID `json:"from_memory_id"`
+	To           uuid.UUID `json:"to_memory_id"`
+	Relationship string    `json:"relationship"`
+	Similarity   float64   `json:"similarity"`
+	Confidence   float64   `json:"confidence"`
+	Source       string    `json:"source"` // "heuristic" or "llm"
+}
+
+func (e *RelationshipInferenceExecutor) Execute(ctx context.Context, job Job) (*ExecutionResult, error) {
+	if !e.cfg.Enabled {
+		return &ExecutionResult{Status: JobSucceeded, Decision: "infer_disabled", Output: map[string]any{"enabled": false}}, nil
+	}
+	memoryID, err := parseInferRelationshipsPayload(job.Payload, job.SourceMemoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	mem, err := e.svc.Peek(ctx, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	if mem == nil || mem.ReplacedBy != nil || mem.Embedding == nil {
+		return &ExecutionResult{
+			Status:      JobSucceeded,
+			Decision:    "skip_missing_memory",
+			Output:      map[string]any{"memory_id": memoryID, "proposed": 0},
+			SideEffects: []map[string]any{{"type": "inference_skip", "memory_id": memoryID, "reason": "missing_or_replaced"}},
+		}, nil
+	}
+
+	existing, err := e.svc.ListRelationships(ctx, memory.RelationshipFilter{MemoryID: &memoryID, Limit: 500})
+	if err != nil {
+		return nil, fmt.Errorf("list existing relationships: %w", err)
+	}
+	linked := map[uuid.UUID]bool{}
+	for _, rel := range existing.Relationships {
+		linked[rel.FromMemoryID] = true
+		linked[rel.ToMemoryID] = true
+	}
+
+	maxCandidates := e.cfg.MaxCandidates
+	if maxCandidates <= 0 {
+		maxCandidates = 1
+	}
+	similar, err := e.svc.FindSimilarTo(ctx, memoryID, e.cfg.MinSimilarity, maxCandidates*4)
+	if err != nil {
+		return nil, fmt.Errorf("find similar memories: %w", err)
+	}
+
+	result := &ExecutionResult{Status: JobSucceeded, Retryable: false}
+	proposed := []proposedEdge{}
+	sideEffects := []map[string]any{}
+	for _, cand := range similar {
+		if len(proposed) >= maxCandidates {
+			break
+		}
+		if linked[cand.Memory.ID] || cand.Similarity > e.cfg.MaxSimilarity {
+			continue
+		}
+		edge := inferEdge(mem, &cand.Memory, cand.Similarity)
+		if e.llm != nil && e.cfg.UseLLM && (e.llmAllowed == nil || e.llmAllowed()) {
+			decided, metrics, derr := e.decideWithLLM(ctx, mem, &cand.Memory, edge)
+			if metrics != nil {
+				result.Provider = metrics.Provider
+				result.Model = metrics.Model
+				result.PromptTokens = addTokens(result.PromptTokens, metrics.PromptTokens)
+				result.CompletionTokens = addTokens(result.CompletionTokens, metrics.CompletionTokens)
+				result.TotalTokens = addTokens(result.TotalTokens, metrics.TotalTokens)
+			}
+			// On a model error the heuristic edge stands.
+			if derr == nil {
+				if decided == nil {
+					sideEffects = append(sideEffects, map[string]any{"type": "relationship_rejected", "candidate_id": cand.Memory.ID, "reason": "llm_none"})
+					continue
+				}
+				edge = *decided
+			}
+		}
+		if edge.Confidence < e.cfg.MinConfidence {
+			continue
+		}
+		proposed = append(proposed, edge)
+	}
+
+	for _, edge := range proposed {
+		effect := map[string]any{
+			"from_memory_id": edge.From,
+			"to_memory_id":   edge.To,
+			"relationship":   edge.Relationship,
+			"confidence":     edge.Confidence,
+			"source":         edge.Source,
+		}
+		if e.dryRun {
+			effect["type"] = "relationship_proposed"
+			effect["reason"] = "dry_run"
+			sideEffects = append(sideEffects, effect)
+			continue
+		}
+		ctxNote := fmt.Sprintf("inferred by steward (%s, similarity %.2f)", edge.Source, edge.Similarity)
+		rel, err := e.svc.CreateRelationship(ctx, memory.RelationshipRequest{
+			FromMemoryID: edge.From,
+			ToMemoryID:   edge.To,
+			Relationship: edge.Relationship,
+			Strength:     float32(edge.Confidence),
+			Context:      &ctxNote,
+		})
+		if err != nil {
+			effect["type"] = "relationship_failed"
+			effect["error"] = err.Error()
+			sideEffects = append(sideEffects, effect)
+			continue
+		}
+		effect["type"] = "relationship_created"
+		effect["relationship_id"] = rel.ID
+		sideEffects = append(sideEffects, effect)
+	}
+
+	result.Decision = "relationships_inferred"
+	if e.dryRun {
+		result.Decision = "dry_run_relationships"
+	}
+	if len(proposed) == 0 {
+		result.Decision = "no_relationships"
+	}
+	result.Output = map[string]any{
+		"memory_id":  memoryID,
+		"candidates": len(similar),
+		"proposed":   proposed,
+	}
+	result.SideEffects = sideEffects
+	return result, nil
+}
+
+// decideWithLLM asks the model to confirm, retype or reject the heuristic
+// edge. The model judges the edge in the heuristic's orientation; a nil edge
+// means it found no meaningful relationship.
+func (e *RelationshipInferenceExecutor) decideWithLLM(ctx context.Context, mem, cand *memory.Memory, heuristic proposedEdge) (*proposedEdge, *llm.DecisionMetrics, error) {
+	src, dst := mem, cand
+	if heuristic.From != mem.ID {
+		src, dst = cand, mem
+	}
+	decision, metrics, err := e.llm.DecideRelationship(ctx, llm.RelationshipDecisionInput{
+		SourceTitle:   src.Title,
+		SourceType:    string(src.Type),
+		SourceContent: src.Content,
+		TargetTitle:   dst.Title,
+		TargetType:    string(dst.Type),
+		TargetContent: dst.Content,
+		Similarity:    heuristic.Similarity,
+		AllowedTypes:  inferableRelationships,
+		Suggested:     heuristic.Relationship,
+	})
+	if err != nil {
+		return nil, metrics, err
+	}
+	if decision.Relationship == "NONE" {
+		return nil, metrics, nil
+	}
+	edge := heuristic
+	edge.Relationship = decision.Relationship
+	edge.Confidence = decision.Confidence
+	edge.Source = "llm"
+	return &edge, metrics, nil
+}
+
+// inferEdge proposes an edge between a new memory and a similar one from
+// their types. A fix or solution SOLVES an error or problem, and a decision,
+// workflow, task or code pattern ADDRESSES one, whichever of the two is new;
+// anything else is RELATED_TO. Typed edges get a confidence bonus over the
+// raw similarity.
+func inferEdge(mem, cand *memory.Memory, similarity float64) proposedEdge {
+	edge := proposedEdge{From: mem.ID, To: cand.ID, Relationship: memory.RelRelatedTo, Similarity: similarity, Confidence: similarity, Source: "heuristic"}
+	if rel := typePairRelationship(mem.Type, cand.Type); rel != "" {
+		edge.Relationship = rel
+	} else if rel := typePairRelationship(cand.Type, mem.Type); rel != "" {
+		edge.From, edge.To = cand.ID, mem.ID
+		edge.Relationship = rel
+	}
+	if edge.Relationship != memory.RelRelatedTo {
+		edge.Confidence = math.Min(1, similarity+typedRelationshipBonus)
+	}
+	return edge
+}
+
+func typePairRelationship(from, to memory.MemoryType) string {
+	isProblem := to == memory.TypeError || to == memory.TypeProblem
+	if !isProblem {
+		return ""
+	}
+	switch from {
+	case memory.TypeFix, memory.TypeSolution:
+		return memory.RelSolves
+	case memory.TypeDecision, memory.TypeWorkflow, memory.TypeTask, memory.TypeCodePattern:
+		return memory.RelAddresses
+	}
+	return ""
+}
+
+func addTokens(total, n *int) *int {
+	if n == nil {
+		return total
+	}
+	sum := *n
+	if total != nil {
+		sum += *total
+	}
+	return &sum
+}
+
+func parseInferRelationshipsPayload(payload map[string]any, fallback []uuid.UUID) (uuid.UUID, error) {
+	if s, ok := payload["memory_id"].(string); ok {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("invalid payload.memory_id: %w", err)
+		}
+		return id, nil
+	}
+	if len(fallback) > 0 {
+		return fallback[0], nil
+	}
+	return uuid.Nil, fmt.Errorf("missing payload.memory_id")
+}
@@ -0,0 +1,93 @@
+package steward
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/atakanatali/contextify/internal/config"
+	"github.com/atakanatali/contextify/internal/memory"
+)
+
+func TestInferEdge_TypePairs(t *testing.T) {
+	newMem := func(typ memory.MemoryType) *memory.Memory {
+		return &memory.Memory{ID: uuid.New(), Type: typ}
+	}
+
+	fix, errMem := newMem(memory.TypeFix), newMem(memory.TypeError)
+	edge := inferEdge(fix, errMem, 0.7)
+	if edge.Relationship != memory.RelSolves || edge.From != fix.ID || edge.To != errMem.ID {
+		t.Fatalf("new fix should SOLVE the error: %+v", edge)
+	}
+	if edge.Confidence < 0.79 || edge.Confidence > 0.81 {
+		t.Fatalf("typed edge should get the bonus, got %v", edge.Confidence)
+	}
+
+	// A new error links back from an existing solution.
+	problem, solution := newMem(memory.TypeProblem), newMem(memory.TypeSolution)
+	edge = inferEdge(problem, solution, 0.7)
+	if edge.Relationship != memory.RelSolves || edge.From != solution.ID || edge.To != problem.ID {
+		t.Fatalf("existing solution should SOLVE the new problem: %+v", edge)
+	}
+
+	decision := newMem(memory.TypeDecision)
+	if edge := inferEdge(decision, problem, 0.7); edge.Relationship != memory.RelAddresses {
+		t.Fatalf("decision should ADDRESS a problem: %+v", edge)
+	}
+
+	a, b := newMem(memory.TypeGeneral), newMem(memory.TypeWorkflow)
+	edge = inferEdge(a, b, 0.8)
+	if edge.Relationship != memory.RelRelatedTo || edge.Confidence != 0.8 {
+		t.Fatalf("untyped pair should be RELATED_TO at raw similarity: %+v", edge)
+	}
+
+	if edge := inferEdge(fix, errMem, 0.95); edge.Confidence != 1 {
+		t.Fatalf("confidence should be capped at 1, got %v", edge.Confidence)
+	}
+}
+
+func TestInferEdge_ProposesOnlyInferableTypes(t *testing.T) {
+	types := []memory.MemoryType{memory.TypeFix, memory.TypeSolution, memory.TypeError, memory.TypeProblem, memory.TypeDecision, memory.TypeGeneral}
+	allowed := map[string]bool{}
+	for _, r := range inferableRelationships {
+		allowed[r] = true
+	}
+	for _, a := range types {
+		for _, b := range types {
+			edge := inferEdge(&memory.Memory{ID: uuid.New(), Type: a}, &memory.Memory{ID: uuid.New(), Type: b}, 0.7)
+			if !allowed[edge.Relationship] {
+				t.Fatalf("%s/%s produced non-inferable type %s", a, b, edge.Relationship)
+			}
+		}
+	}
+}
+
+func TestParseInferRelationshipsPayload(t *testing.T) {
+	id := uuid.New()
+	got, err := parseInferRelationshipsPayload(map[string]any{"memory_id": id.String()}, nil)
+	if err != nil || got != id {
+		t.Fatalf("payload id: got %v, %v", got, err)
+	}
+	got, err = parseInferRelationshipsPayload(nil, []uuid.UUID{id})
+	if err != nil || got != id {
+		t.Fatalf("fallback id: got %v, %v", got, err)
+	}
+	if _, err := parseInferRelationshipsPayload(map[string]any{"memory_id": "nope"}, nil); err == nil {
+		t.Fatal("expected error for invalid memory_id")
+	}
+	if _, err := parseInferRelationshipsPayload(nil, nil); err == nil {
+		t.Fatal("expected error for missing memory_id")
+	}
+}
+
+func TestRelationshipInference_DisabledIsNoop(t *testing.T) {
+	ex := NewRelationshipInferenceExecutor(nil, &config.StewardRelationships{Enabled: false}, true, nil, nil)
+	res, err := ex.Execute(context.Background(), Job{JobType: JobInferRelationships})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if res.Decision != "infer_disabled" {
+		t.Fatalf("unexpected decision: %s", res.Decision)
+	}
+}
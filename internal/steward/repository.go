@@ -11,6 +11,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/atakanatali/contextify/internal/memory"
 )
 
 type Repository struct {
@@ -89,7 +91,7 @@ func (r *Repository) ClaimJobs(ctx context.Context, workerID string, batchSize i
 		RETURNING j.id, j.job_type, j.project_id, j.source_memory_ids, j.trigger_reason, j.payload,
 		          j.status, j.priority, j.attempt_count, j.max_attempts, j.run_after, j.locked_by,
 		          j.locked_at, j.lease_expires_at, j.last_error, j.idempotency_key, j.cancelled_at,
-		          j.created_at, j.updated_at
+		          j.created_at, j.updated_at, j.workspace_id
 	`
 	rows, err := tx.Query(ctx, query, batchSize, workerID, fmt.Sprintf("%d seconds", int(leaseDuration.Seconds())))
 	if err != nil {
@@ -124,7 +126,7 @@ func (r *Repository) EnqueueAutoMergeSuggestionJobsWithBackpressure(ctx context.
 
 func (r *Repository) enqueueAutoMergeSuggestionJobsSlow(ctx context.Context, threshold float64, maxAttempts, limit, maxQueuedTotal, maxQueuedPerProject int) (int64, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, memory_a_id, memory_b_id, similarity, project_id
+		SELECT id, memory_a_id, memory_b_id, similarity, project_id, workspace_id
 		FROM consolidation_suggestions
 		WHERE status = 'pending' AND similarity >= $1
 		ORDER BY similarity DESC, created_at ASC
@@ -140,7 +142,8 @@ func (r *Repository) enqueueAutoMergeSuggestionJobsSlow(ctx context.Context, thr
 		var sid, aID, bID uuid.UUID
 		var similarity float64
 		var projectID *string
-		if err := rows.Scan(&sid, &aID, &bID, &similarity, &projectID); err != nil {
+		var workspaceID string
+		if err := rows.Scan(&sid, &aID, &bID, &similarity, &projectID, &workspaceID); err != nil {
 			return inserted, fmt.Errorf("scan auto-merge suggestion: %w", err)
 		}
 		if maxQueuedTotal > 0 {
@@ -164,15 +167,15 @@ func (r *Repository) enqueueAutoMergeSuggestionJobsSlow(ctx context.Context, thr
 		_, err := r.pool.Exec(ctx, `
 			INSERT INTO steward_jobs (
 				id, job_type, project_id, source_memory_ids, trigger_reason, payload, status, priority,
-				attempt_count, max_attempts, run_after, idempotency_key
+				attempt_count, max_attempts, run_after, idempotency_key, workspace_id
 			)
 			VALUES (
 				uuid_generate_v4(), 'auto_merge_from_suggestion', $1, ARRAY[$2,$3]::uuid[], 'pending_suggestion_high_similarity',
 				jsonb_build_object('suggestion_id',$4,'similarity',$5,'memory_a_id',$2,'memory_b_id',$3,'merge_strategy','smart_merge'),
-				'queued', 100, 0, $6, NOW(), $7
+				'queued', 100, 0, $6, NOW(), $7, $8
 			)
 			ON CONFLICT (idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
-		`, projectID, aID, bID, sid, similarity, maxAttempts, "steward:auto_merge_suggestion:"+sid.String())
+		`, projectID, aID, bID, sid, similarity, maxAttempts, "steward:auto_merge_suggestion:"+sid.String(), workspaceID)
 		if err != nil {
 			return inserted, fmt.Errorf("insert auto-merge steward job: %w", err)
 		}
@@ -246,20 +249,106 @@ func (r *Repository) EnqueueDeriveJob(ctx context.Context, projectID *string, so
 	_, err = r.pool.Exec(ctx, `
 		INSERT INTO steward_jobs (
 			id, job_type, project_id, source_memory_ids, trigger_reason, payload, status, priority,
-			attempt_count, max_attempts, run_after, idempotency_key
+			attempt_count, max_attempts, run_after, idempotency_key, workspace_id
 		)
 		VALUES (
 			uuid_generate_v4(), 'derive_memories', $1, $2, 'post_merge_derivation', $3::jsonb, 'queued',
-			50, 0, $4, NOW(), $5
+			50, 0, $4, NOW(), $5, $6
 		)
 		ON CONFLICT (idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
-	`, projectID, sourceIDs, string(b), maxAttempts, idempotencyKey)
+	`, projectID, sourceIDs, string(b), maxAttempts, idempotencyKey, memory.WorkspaceFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("enqueue derive job: %w", err)
 	}
 	return nil
 }
 
+// EnqueueRelationshipInferenceJobs queues one infer_relationships job for
+// each memory created within lookback that has not had one yet, oldest first.
+func (r *Repository) EnqueueRelationshipInferenceJobs(ctx context.Context, lookback time.Duration, maxAttempts, limit, maxQueuedTotal int) (int64, error) {
+	if maxQueuedTotal > 0 {
+		q, err := r.CountQueuedJobs(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if q >= int64(maxQueuedTotal) {
+			return 0, nil
+		}
+		limit = min(limit, maxQueuedTotal-int(q))
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	res, err := r.pool.Exec(ctx, `
+		INSERT INTO steward_jobs (
+			id, job_type, project_id, source_memory_ids, trigger_reason, payload, status, priority,
+			attempt_count, max_attempts, run_after, idempotency_key, workspace_id
+		)
+		SELECT uuid_generate_v4(), 'infer_relationships', m.project_id, ARRAY[m.id], 'new_memory',
+		       jsonb_build_object('memory_id', m.id), 'queued', 30, 0, $3, NOW(),
+		       'steward:infer_relationships:' || m.id::text, m.workspace_id
+		FROM memories m
+		WHERE m.created_at >= NOW() - $1::interval
+		  AND m.replaced_by IS NULL
+		  AND m.deleted_at IS NULL
+		  AND m.embedding IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM steward_jobs j WHERE j.idempotency_key = 'steward:infer_relationships:' || m.id::text
+		  )
+		ORDER BY m.created_at ASC
+		LIMIT $2
+		ON CONFLICT (idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
+	`, fmt.Sprintf("%d seconds", int(lookback.Seconds())), limit, maxAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue relationship inference jobs: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// EnqueueExpiryReviewJobs queues one review_expiring job for each
+// short-term memory expiring within window, soonest first. Jobs are keyed on
+// the memory and its access count, so a memory is reviewed again only after
+// it has been read since its last review.
+func (r *Repository) EnqueueExpiryReviewJobs(ctx context.Context, window time.Duration, maxAttempts, limit, maxQueuedTotal int) (int64, error) {
+	if maxQueuedTotal > 0 {
+		q, err := r.CountQueuedJobs(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if q >= int64(maxQueuedTotal) {
+			return 0, nil
+		}
+		limit = min(limit, maxQueuedTotal-int(q))
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	res, err := r.pool.Exec(ctx, `
+		INSERT INTO steward_jobs (
+			id, job_type, project_id, source_memory_ids, trigger_reason, payload, status, priority,
+			attempt_count, max_attempts, run_after, idempotency_key, workspace_id
+		)
+		SELECT uuid_generate_v4(), 'review_expiring', m.project_id, ARRAY[m.id], 'expiring_memory',
+		       jsonb_build_object('memory_id', m.id), 'queued', 40, 0, $3, NOW(),
+		       'steward:review_expiring:' || m.id::text || ':' || m.access_count, m.workspace_id
+		FROM memories m
+		WHERE m.ttl_seconds IS NOT NULL
+		  AND m.expires_at < NOW() + $1::interval
+		  AND m.replaced_by IS NULL
+		  AND m.deleted_at IS NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM steward_jobs j WHERE j.idempotency_key = 'steward:review_expiring:' || m.id::text || ':' || m.access_count
+		  )
+		ORDER BY m.expires_at ASC
+		LIMIT $2
+		ON CONFLICT (idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
+	`, fmt.Sprintf("%d seconds", int(window.Seconds())), limit, maxAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue expiry review jobs: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
 func (r *Repository) StoreDerivationRecord(ctx context.Context, d Derivation) error {
 	payload := d.Payload
 	if payload == nil {
@@ -291,7 +380,7 @@ func scanJob(rows pgx.Row) (*Job, error) {
 		&job.ID, &job.JobType, &job.ProjectID, &job.SourceMemoryIDs, &job.TriggerReason, &payloadBytes,
 		&job.Status, &job.Priority, &job.AttemptCount, &job.MaxAttempts, &job.RunAfter, &job.LockedBy,
 		&job.LockedAt, &job.LeaseExpiresAt, &job.LastError, &job.IdempotencyKey, &job.CancelledAt,
-		&job.CreatedAt, &job.UpdatedAt,
+		&job.CreatedAt, &job.UpdatedAt, &job.WorkspaceID,
 	); err != nil {
 		return nil, fmt.Errorf("scan steward job: %w", err)
 	}
@@ -460,33 +549,13 @@ func nullableString(v string) *string {
 	return &v
 }
 
+// ListRuns lists runs of jobs in the caller's workspace, newest first.
 func (r *Repository) ListRuns(ctx context.Context, f RunFilters) ([]Run, error) {
-	conditions := []string{}
-	args := []any{}
-	arg := 1
-	if f.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("r.status = $%d", arg))
-		args = append(args, *f.Status)
-		arg++
-	}
-	if f.JobType != nil {
-		conditions = append(conditions, fmt.Sprintf("j.job_type = $%d", arg))
-		args = append(args, *f.JobType)
-		arg++
-	}
-	if f.ProjectID != nil {
-		conditions = append(conditions, fmt.Sprintf("j.project_id = $%d", arg))
-		args = append(args, *f.ProjectID)
-		arg++
-	}
-	if f.Model != nil {
-		conditions = append(conditions, fmt.Sprintf("r.model = $%d", arg))
-		args = append(args, *f.Model)
-		arg++
-	}
-	where := ""
-	if len(conditions) > 0 {
-		where = "WHERE " + strings.Join(conditions, " AND ")
+	where, args, arg := runConditions(ctx, f)
+	if f.After != nil {
+		where += fmt.Sprintf(" AND (r.created_at, r.id) < ($%d, $%d)", arg, arg+1)
+		args = append(args, f.After.CreatedAt, f.After.ID)
+		arg += 2
 	}
 	if f.Limit <= 0 {
 		f.Limit = 50
@@ -500,9 +569,9 @@ func (r *Repository) ListRuns(ctx context.Context, f RunFilters) ([]Run, error)
 		       r.prompt_tokens, r.completion_tokens, r.total_tokens, r.latency_ms, r.status,
 		       r.error_class, r.error_message, r.created_at, r.completed_at
 		FROM steward_runs r
-		LEFT JOIN steward_jobs j ON j.id = r.job_id
+		JOIN steward_jobs j ON j.id = r.job_id
 		%s
-		ORDER BY r.created_at DESC
+		ORDER BY r.created_at DESC, r.id DESC
 		LIMIT $%d OFFSET $%d
 	`, where, arg, arg+1)
 	rows, err := r.pool.Query(ctx, query, args...)
@@ -526,6 +595,51 @@ func (r *Repository) ListRuns(ctx context.Context, f RunFilters) ([]Run, error)
 	return out, rows.Err()
 }
 
+// CountRuns counts the runs matching f's filters, ignoring its paging.
+func (r *Repository) CountRuns(ctx context.Context, f RunFilters) (int, error) {
+	where, args, _ := runConditions(ctx, f)
+	var n int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM steward_runs r
+		JOIN steward_jobs j ON j.id = r.job_id
+		`+where, args...).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count steward runs: %w", err)
+	}
+	return n, nil
+}
+
+// runConditions builds the WHERE clause for f's filters and returns it with
+// its arguments and the next placeholder number.
+func runConditions(ctx context.Context, f RunFilters) (string, []any, int) {
+	conditions := []string{"j.workspace_id = $1"}
+	args := []any{memory.WorkspaceFromContext(ctx)}
+	arg := 2
+	if f.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("r.status = $%d", arg))
+		args = append(args, *f.Status)
+		arg++
+	}
+	if f.JobType != nil {
+		conditions = append(conditions, fmt.Sprintf("j.job_type = $%d", arg))
+		args = append(args, *f.JobType)
+		arg++
+	}
+	if f.ProjectID != nil {
+		conditions = append(conditions, fmt.Sprintf("j.project_id = $%d", arg))
+		args = append(args, *f.ProjectID)
+		arg++
+	}
+	if f.Model != nil {
+		conditions = append(conditions, fmt.Sprintf("r.model = $%d", arg))
+		args = append(args, *f.Model)
+		arg++
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, arg
+}
+
+// ListEventsByJob returns a job's timeline; jobs of other workspaces have none.
 func (r *Repository) ListEventsByJob(ctx context.Context, jobID uuid.UUID, limit, offset int) ([]Event, error) {
 	if limit <= 0 {
 		limit = 200
@@ -534,12 +648,13 @@ func (r *Repository) ListEventsByJob(ctx context.Context, jobID uuid.UUID, limit
 		offset = 0
 	}
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, job_id, run_id, event_type, data, schema_version, created_at
-		FROM steward_events
-		WHERE job_id = $1
-		ORDER BY created_at ASC
+		SELECT e.id, e.job_id, e.run_id, e.event_type, e.data, e.schema_version, e.created_at
+		FROM steward_events e
+		JOIN steward_jobs j ON j.id = e.job_id
+		WHERE e.job_id = $1 AND j.workspace_id = $4
+		ORDER BY e.created_at ASC
 		LIMIT $2 OFFSET $3
-	`, jobID, limit, offset)
+	`, jobID, limit, offset, memory.WorkspaceFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("list steward events: %w", err)
 	}
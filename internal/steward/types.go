@@ -37,6 +37,7 @@ type Job struct {
 	CancelledAt     *time.Time
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	WorkspaceID     string
 }
 
 type Run struct {
@@ -78,6 +79,23 @@ type RunFilters struct {
 	Model     *string
 	Limit     int
 	Offset    int
+	// After starts the listing after a run, for cursor pagination.
+	After *RunCursor
+}
+
+// RunCursor is the position after a run, ordered newest first by created_at
+// then id.
+type RunCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// RunPage is one page of runs.
+type RunPage struct {
+	Runs       []Run
+	NextCursor string
+	// Total is set when requested: the runs matching the filters.
+	Total *int
 }
 
 type MetricsSummary struct {
@@ -0,0 +1,157 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func exportArchive(t *testing.T, query url.Values) []map[string]any {
+	t.Helper()
+	resp, err := http.Get(baseURL + "/export?" + query.Encode())
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("export: expected 200, got %d: %s", resp.StatusCode, body)
+	}
+
+	var records []map[string]any
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 1<<20), 16<<20)
+	for sc.Scan() {
+		var rec map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("export line is not JSON: %v", err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func importArchive(t *testing.T, records []map[string]any, query string) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range records {
+		enc.Encode(rec)
+	}
+	resp, err := http.Post(baseURL+"/import?"+query, "application/x-ndjson", &buf)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	defer resp.Body.Close()
+	var result map[string]any
+	json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != 200 {
+		t.Fatalf("import: expected 200, got %d: %v", resp.StatusCode, result)
+	}
+	return result
+}
+
+func countRecords(records []map[string]any, kind string) int {
+	n := 0
+	for _, r := range records {
+		if r["kind"] == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	project := uniqueProject()
+	a := storeMemory(t, "Archive source A", "Connection pool exhaustion under load was fixed by raising max_conns.", project, 0.9)
+	b := storeMemory(t, "Archive source B", "Release tags follow semantic versioning with a v prefix.", project, 0.9)
+	idA := a["memory"].(map[string]any)["id"].(string)
+	idB := b["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, idA)
+	defer deleteMemory(t, idB)
+
+	status, _ := doRequest(t, "POST", "/relationships", map[string]any{
+		"from_memory_id": idA, "to_memory_id": idB, "relationship": "related_to", "strength": 0.7,
+	})
+	if status != 201 {
+		t.Fatalf("create relationship: expected 201, got %d", status)
+	}
+
+	records := exportArchive(t, url.Values{"project_id": {project}, "include_embeddings": {"true"}})
+	if len(records) == 0 || records[0]["kind"] != "header" {
+		t.Fatalf("archive must start with a header, got %v", records)
+	}
+	if n := countRecords(records, "memory"); n != 2 {
+		t.Fatalf("expected 2 memory records, got %d", n)
+	}
+	if n := countRecords(records, "relationship"); n != 1 {
+		t.Fatalf("expected 1 relationship record, got %d", n)
+	}
+
+	// Same install, default mode: everything already exists.
+	result := importArchive(t, records, "")
+	if skipped := result["memories"].(map[string]any)["skipped"].(float64); skipped != 2 {
+		t.Fatalf("expected 2 skipped memories, got %v", result)
+	}
+
+	// Restore after deletion keeps the original IDs and relationships.
+	deleteMemory(t, idA)
+	deleteMemory(t, idB)
+	result = importArchive(t, records, "on_conflict=skip")
+	if created := result["memories"].(map[string]any)["created"].(float64); created != 2 {
+		t.Fatalf("expected 2 created memories, got %v", result)
+	}
+	if got := getMemory(t, idA); got["title"] != "Archive source A" {
+		t.Fatalf("restored memory has wrong title: %v", got)
+	}
+	status, related := doRequest(t, "GET", "/memories/"+idA+"/related", nil)
+	if status != 200 || len(related["relationships"].([]any)) != 1 {
+		t.Fatalf("expected restored relationship, got %d %v", status, related)
+	}
+
+	// Remap imports copies under fresh IDs.
+	result = importArchive(t, records, "on_conflict=remap")
+	mems := result["memories"].(map[string]any)
+	if mems["created"].(float64) != 2 || mems["remapped"].(float64) != 2 {
+		t.Fatalf("expected 2 remapped memories, got %v", result)
+	}
+	copies := exportArchive(t, url.Values{"project_id": {project}})
+	for _, rec := range copies {
+		if rec["kind"] != "memory" {
+			continue
+		}
+		if id := rec["memory"].(map[string]any)["id"].(string); id != idA && id != idB {
+			deleteMemory(t, id)
+		}
+	}
+	if n := countRecords(copies, "memory"); n != 4 {
+		t.Fatalf("expected originals and copies (4), got %d", n)
+	}
+}
+
+func TestImport_RejectsInvalidArchive(t *testing.T) {
+	resp, err := http.Post(baseURL+"/import", "application/x-ndjson", bytes.NewBufferString(`{"kind":"memory"}`+"\n"))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for archive without header, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(baseURL+"/import?on_conflict=merge", "application/x-ndjson", bytes.NewBufferString(""))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for unknown on_conflict, got %d", resp.StatusCode)
+	}
+}
@@ -484,6 +484,86 @@ func TestConsolidationLog(t *testing.T) {
 		entry["target_id"], entry["merge_strategy"], entry["performed_by"])
 }
 
+func TestConsolidationRevert(t *testing.T) {
+	project := uniqueProject()
+
+	r1 := storeMemory(t, "Revert test target", "Original target content for revert.", project, 0.5)
+	id1 := r1["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id1)
+
+	r2 := storeMemory(t, "Revert test source", "Source content absorbed by the merge.", project, 0.5)
+	id2 := r2["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id2)
+
+	status, _ := doRequest(t, "POST", "/memories/"+id1+"/merge", map[string]any{
+		"source_ids": []string{id2},
+		"strategy":   "append",
+	})
+	if status != 200 {
+		t.Fatalf("merge failed: status=%d", status)
+	}
+
+	_, logs := doRequestArray(t, "GET", "/consolidation/log?target_id="+id1, nil)
+	if len(logs) == 0 {
+		t.Fatal("expected a log entry for the merge")
+	}
+	logID := logs[0].(map[string]any)["id"].(string)
+
+	status, entry := doRequest(t, "POST", "/consolidation/log/"+logID+"/revert", nil)
+	if status != 200 {
+		t.Fatalf("revert failed: status=%d body=%v", status, entry)
+	}
+	if entry["merge_strategy"] != "revert" || entry["reverts_id"] != logID {
+		t.Fatalf("unexpected revert log entry: %v", entry)
+	}
+
+	target := getMemory(t, id1)
+	if target["content"] != "Original target content for revert." {
+		t.Errorf("expected target content restored, got %v", target["content"])
+	}
+	source := getMemory(t, id2)
+	if source["replaced_by"] != nil {
+		t.Errorf("expected source replaced_by cleared, got %v", source["replaced_by"])
+	}
+
+	status, _ = doRequest(t, "POST", "/consolidation/log/"+logID+"/revert", nil)
+	if status != 409 {
+		t.Errorf("expected 409 when reverting twice, got %d", status)
+	}
+}
+
+func TestConsolidationRevert_PurgedSource(t *testing.T) {
+	project := uniqueProject()
+
+	r1 := storeMemory(t, "Purged revert target", "Target content for purged revert.", project, 0.5)
+	id1 := r1["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id1)
+
+	r2 := storeMemory(t, "Purged revert source", "Source content that will be purged.", project, 0.5)
+	id2 := r2["memory"].(map[string]any)["id"].(string)
+
+	doRequest(t, "POST", "/memories/"+id1+"/merge", map[string]any{"source_ids": []string{id2}})
+	_, logs := doRequestArray(t, "GET", "/consolidation/log?target_id="+id1, nil)
+	if len(logs) == 0 {
+		t.Fatal("expected a log entry for the merge")
+	}
+	logID := logs[0].(map[string]any)["id"].(string)
+
+	deleteMemory(t, id2)
+
+	status, body := doRequest(t, "POST", "/consolidation/log/"+logID+"/revert", nil)
+	if status != 409 {
+		t.Fatalf("expected 409 when the source is purged, got %d body=%v", status, body)
+	}
+}
+
+func TestConsolidationRevert_NotFound(t *testing.T) {
+	status, _ := doRequest(t, "POST", "/consolidation/log/00000000-0000-0000-0000-000000000000/revert", nil)
+	if status != 404 {
+		t.Fatalf("expected 404 for unknown log entry, got %d", status)
+	}
+}
+
 func TestConsolidationLog_FilterByTarget(t *testing.T) {
 	status, _ := doRequest(t, "GET", "/consolidation/log?target_id=00000000-0000-0000-0000-000000000000&limit=5", nil)
 	if status != 200 {
@@ -0,0 +1,89 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"net/url"
+	"testing"
+)
+
+func storeShortTerm(t *testing.T, title, project string) string {
+	t.Helper()
+	status, body := doRequest(t, "POST", "/memories", map[string]any{
+		"title":       title,
+		"content":     "A short-term note that expires in ten minutes: " + title,
+		"type":        "general",
+		"project_id":  project,
+		"importance":  0.3,
+		"ttl_seconds": 600,
+	})
+	if status != 201 {
+		t.Fatalf("store memory failed: status=%d body=%v", status, body)
+	}
+	return body["memory"].(map[string]any)["id"].(string)
+}
+
+func TestExpiring_ListKeepPromote(t *testing.T) {
+	project := uniqueProject()
+	id := storeShortTerm(t, "Expiring memory", project)
+	defer deleteMemory(t, id)
+
+	status, body := doRequest(t, "GET", "/memories/expiring?within=1h&project_id="+url.QueryEscape(project), nil)
+	if status != 200 {
+		t.Fatalf("list expiring failed: status=%d body=%v", status, body)
+	}
+	memories := body["memories"].([]any)
+	if len(memories) != 1 || memories[0].(map[string]any)["id"] != id {
+		t.Fatalf("expected the memory in the review queue, got %v", memories)
+	}
+
+	status, body = doRequest(t, "POST", "/memories/"+id+"/review", map[string]any{"action": "keep"})
+	if status != 200 {
+		t.Fatalf("keep failed: status=%d body=%v", status, body)
+	}
+	if body["action"] != "keep" || body["expires_at"] == nil {
+		t.Fatalf("unexpected keep result: %v", body)
+	}
+
+	status, body = doRequest(t, "POST", "/memories/"+id+"/review", map[string]any{"action": "promote"})
+	if status != 200 || body["action"] != "promote" {
+		t.Fatalf("promote failed: status=%d body=%v", status, body)
+	}
+	if mem := getMemory(t, id); mem["ttl_seconds"] != nil {
+		t.Fatalf("expected a long-term memory after promote, got ttl_seconds=%v", mem["ttl_seconds"])
+	}
+	if status, _ := doRequest(t, "POST", "/memories/"+id+"/review", map[string]any{"action": "keep"}); status != 409 {
+		t.Fatalf("expected 409 reviewing a long-term memory, got %d", status)
+	}
+}
+
+func TestExpiring_ExpireNow(t *testing.T) {
+	project := uniqueProject()
+	id := storeShortTerm(t, "Memory to let expire", project)
+
+	status, body := doRequest(t, "POST", "/memories/"+id+"/review", map[string]any{"action": "expire"})
+	if status != 200 || body["action"] != "expire" {
+		t.Fatalf("expire failed: status=%d body=%v", status, body)
+	}
+	if status, _ := doRequest(t, "GET", "/memories/"+id, nil); status != 404 {
+		t.Fatalf("expected 404 for an expired memory, got %d", status)
+	}
+	trashed := trashContains(t, project, id)
+	if trashed == nil || trashed["deleted_reason"] != "expired" {
+		t.Fatalf("expected the memory in the trash as expired, got %v", trashed)
+	}
+	doRequest(t, "DELETE", "/trash/"+id, nil)
+}
+
+func TestExpiring_InvalidAction(t *testing.T) {
+	id := storeShortTerm(t, "Memory with a bad review", uniqueProject())
+	defer deleteMemory(t, id)
+
+	if status, _ := doRequest(t, "POST", "/memories/"+id+"/review", map[string]any{"action": "snooze"}); status != 400 {
+		t.Fatalf("expected 400 for an unknown action, got %d", status)
+	}
+	if status, _ := doRequest(t, "GET", "/memories/expiring?within=soon", nil); status != 400 {
+		t.Fatalf("expected 400 for an invalid window, got %d", status)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"testing"
+)
+
+func TestMemoryGraph_TraversalAndPath(t *testing.T) {
+	project := uniqueProject()
+	id := func(r map[string]any) string { return r["memory"].(map[string]any)["id"].(string) }
+
+	errMem := id(storeMemory(t, "Graph error", "Requests fail with dial tcp timeout after deploy.", project, 0.5))
+	problem := id(storeMemory(t, "Graph problem", "Connection pool is capped at five connections in production config.", project, 0.5))
+	solution := id(storeMemory(t, "Graph solution", "Set DB_MAX_CONNS from the instance size in the Helm chart.", project, 0.5))
+	defer deleteMemory(t, errMem)
+	defer deleteMemory(t, problem)
+	defer deleteMemory(t, solution)
+
+	for _, rel := range []map[string]any{
+		{"from_memory_id": problem, "to_memory_id": errMem, "relationship": "CAUSES", "strength": 0.8},
+		{"from_memory_id": solution, "to_memory_id": problem, "relationship": "SOLVES", "strength": 0.9},
+	} {
+		if status, body := doRequest(t, "POST", "/relationships", rel); status != 201 {
+			t.Fatalf("create relationship: expected 201, got %d: %v", status, body)
+		}
+	}
+
+	status, graph := doRequest(t, "GET", "/memories/"+errMem+"/graph?direction=incoming&depth=2", nil)
+	if status != 200 {
+		t.Fatalf("graph: expected 200, got %d: %v", status, graph)
+	}
+	depths := map[string]float64{}
+	for _, n := range graph["nodes"].([]any) {
+		node := n.(map[string]any)
+		depths[node["memory"].(map[string]any)["id"].(string)] = node["depth"].(float64)
+	}
+	if depths[errMem] != 0 || depths[problem] != 1 || depths[solution] != 2 {
+		t.Fatalf("unexpected node depths: %v", depths)
+	}
+	if edges := graph["edges"].([]any); len(edges) != 2 {
+		t.Fatalf("expected 2 edges, got %d", len(edges))
+	}
+
+	_, graph = doRequest(t, "GET", "/memories/"+errMem+"/graph?direction=outgoing", nil)
+	if nodes := graph["nodes"].([]any); len(nodes) != 1 {
+		t.Fatalf("outgoing from the error should reach nothing, got %d nodes", len(nodes))
+	}
+
+	_, graph = doRequest(t, "GET", "/memories/"+errMem+"/graph?direction=incoming&types=CAUSES", nil)
+	if nodes := graph["nodes"].([]any); len(nodes) != 2 {
+		t.Fatalf("CAUSES-only traversal should stop at the problem, got %d nodes", len(nodes))
+	}
+
+	status, path := doRequest(t, "GET", "/memories/"+errMem+"/graph?to="+solution, nil)
+	if status != 200 {
+		t.Fatalf("path: expected 200, got %d: %v", status, path)
+	}
+	if path["hops"].(float64) != 2 || len(path["nodes"].([]any)) != 3 {
+		t.Fatalf("expected a 2-hop path, got %v", path)
+	}
+
+	status, _ = doRequest(t, "GET", "/memories/"+errMem+"/graph?to="+solution+"&min_strength=0.85", nil)
+	if status != 404 {
+		t.Fatalf("path over a weak edge: expected 404, got %d", status)
+	}
+}
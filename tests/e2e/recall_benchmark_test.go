@@ -36,8 +36,22 @@ type benchmarkReport struct {
 	FunnelBefore funnelSnapshot `json:"funnel_before"`
 	FunnelAfter  funnelSnapshot `json:"funnel_after"`
 	FunnelDelta  funnelSnapshot `json:"funnel_delta"`
+	Strategies   []strategyRun  `json:"strategies,omitempty"`
 }
 
+// strategyRun measures one ranking strategy on queries with a known answer.
+type strategyRun struct {
+	Strategy string  `json:"strategy"`
+	Queries  int     `json:"queries"`
+	Top1     int     `json:"top1"`
+	MRR      float64 `json:"mrr"`
+	P50Ms    int     `json:"p50_ms"`
+	P95Ms    int     `json:"p95_ms"`
+}
+
+// rankingStrategies are compared by the recall benchmark.
+var rankingStrategies = []string{"linear", "rrf", "max"}
+
 func TestRecallBenchmark(t *testing.T) {
 	project := uniqueProject()
 	var ids []string
@@ -110,6 +124,19 @@ func TestRecallBenchmark(t *testing.T) {
 		StoreActions:       funnelAfter.StoreActions - funnelBefore.StoreActions,
 	}
 
+	// Queries naming one memory have a single right answer, which the
+	// strategies are compared on.
+	expected := map[string]string{}
+	for _, n := range []int{1, 9, 17, 25, 33} {
+		expected[fmt.Sprintf("benchmark memory %02d", n)] = ids[n]
+	}
+	var strategies []strategyRun
+	for _, strategy := range rankingStrategies {
+		run := runRankingStrategy(t, project, strategy, expected)
+		strategies = append(strategies, run)
+		t.Logf("Ranking %-6s queries=%d top1=%d mrr=%.3f p50_ms=%d p95_ms=%d", run.Strategy, run.Queries, run.Top1, run.MRR, run.P50Ms, run.P95Ms)
+	}
+
 	t.Logf("Recall benchmark summary: calls=%d hits=%d hit_rate=%.3f p50_ms=%d p95_ms=%d", totalCalls, hitCount, hitRate, p50, p95)
 	t.Logf("Thresholds: max_p95_ms=%d min_hit_rate=%.3f", maxP95, minHitRate)
 	t.Logf("Funnel delta: recall_attempts=%d recall_hits=%d store_opportunities=%d store_actions=%d",
@@ -130,6 +157,7 @@ func TestRecallBenchmark(t *testing.T) {
 			FunnelBefore: funnelBefore,
 			FunnelAfter:  funnelAfter,
 			FunnelDelta:  funnelDelta,
+			Strategies:   strategies,
 		}
 		writeBenchmarkReport(t, reportPath, report)
 		t.Logf("Benchmark report written: %s", reportPath)
@@ -143,6 +171,44 @@ func TestRecallBenchmark(t *testing.T) {
 	}
 }
 
+func runRankingStrategy(t *testing.T, project, strategy string, expected map[string]string) strategyRun {
+	t.Helper()
+	run := strategyRun{Strategy: strategy}
+	var latencies []int
+	var rrSum float64
+	for q, want := range expected {
+		start := time.Now()
+		status, results := doRequestArray(t, "POST", "/memories/recall", map[string]any{
+			"query":      q,
+			"project_id": project,
+			"limit":      10,
+			"ranking":    strategy,
+		})
+		latencies = append(latencies, int(time.Since(start).Milliseconds()))
+		if status != 200 {
+			t.Fatalf("recall failed: status=%d strategy=%s query=%q", status, strategy, q)
+		}
+		run.Queries++
+		for i, r := range results {
+			mem := r.(map[string]any)["memory"].(map[string]any)
+			if mem["id"] == want {
+				rrSum += 1 / float64(i+1)
+				if i == 0 {
+					run.Top1++
+				}
+				break
+			}
+		}
+	}
+	sort.Ints(latencies)
+	run.P50Ms = percentile(latencies, 50)
+	run.P95Ms = percentile(latencies, 95)
+	if run.Queries > 0 {
+		run.MRR = rrSum / float64(run.Queries)
+	}
+	return run
+}
+
 func percentile(values []int, p int) int {
 	if len(values) == 0 {
 		return 0
@@ -0,0 +1,27 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import "testing"
+
+func TestReembedStatusEndpoint(t *testing.T) {
+	status, body := doRequest(t, "GET", "/admin/reembed", nil)
+	if status != 200 {
+		t.Fatalf("reembed status failed: status=%d body=%v", status, body)
+	}
+	for _, key := range []string{"active", "configured", "in_sync", "total", "covered", "coverage_pct"} {
+		if _, ok := body[key]; !ok {
+			t.Fatalf("expected reembed status payload to include %s", key)
+		}
+	}
+
+	active := body["active"].(map[string]any)
+	configured := body["configured"].(map[string]any)
+	if active["model"] == configured["model"] && body["in_sync"] == true {
+		code, _ := doRequest(t, "POST", "/admin/reembed", nil)
+		if code != 409 {
+			t.Fatalf("expected 409 when embeddings already match, got %d", code)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"testing"
+)
+
+func TestRelationships_CRUD(t *testing.T) {
+	project := uniqueProject()
+	id := func(r map[string]any) string { return r["memory"].(map[string]any)["id"].(string) }
+
+	problem := id(storeMemory(t, "Relationship problem", "Builds fail because the Go module cache is not restored in CI.", project, 0.5))
+	fix := id(storeMemory(t, "Relationship fix", "Cache ~/go/pkg/mod keyed on go.sum in the CI workflow.", project, 0.5))
+	defer deleteMemory(t, problem)
+	defer deleteMemory(t, fix)
+
+	status, rel := doRequest(t, "POST", "/relationships", map[string]any{
+		"from_memory_id": problem, "to_memory_id": fix, "relationship": "solved by", "strength": 0.7,
+	})
+	if status != 201 {
+		t.Fatalf("create: expected 201, got %d: %v", status, rel)
+	}
+	if rel["relationship"] != "SOLVES" || rel["from_memory_id"] != fix || rel["to_memory_id"] != problem {
+		t.Fatalf("inverse name should be stored as fix -SOLVES-> problem, got %v", rel)
+	}
+	relID := rel["id"].(string)
+
+	for _, bad := range []map[string]any{
+		{"from_memory_id": fix, "to_memory_id": problem, "relationship": "LIKES"},
+		{"from_memory_id": fix, "to_memory_id": fix, "relationship": "SOLVES"},
+		{"from_memory_id": fix, "to_memory_id": problem, "relationship": "SOLVES", "strength": 1.5},
+	} {
+		if status, body := doRequest(t, "POST", "/relationships", bad); status != 400 {
+			t.Fatalf("create %v: expected 400, got %d: %v", bad, status, body)
+		}
+	}
+
+	status, list := doRequest(t, "GET", "/relationships?project_id="+project, nil)
+	if status != 200 {
+		t.Fatalf("list: expected 200, got %d: %v", status, list)
+	}
+	if list["total"].(float64) != 1 || len(list["relationships"].([]any)) != 1 {
+		t.Fatalf("expected one relationship in project, got %v", list)
+	}
+
+	status, rel = doRequest(t, "PUT", "/relationships/"+relID, map[string]any{"strength": 0.95, "context": "confirmed in CI"})
+	if status != 200 || rel["strength"].(float64) < 0.94 || rel["context"] != "confirmed in CI" {
+		t.Fatalf("update strength: got %d: %v", status, rel)
+	}
+
+	status, rel = doRequest(t, "PUT", "/relationships/"+relID, map[string]any{"relationship": "CAUSED_BY"})
+	if status != 200 || rel["relationship"] != "CAUSES" || rel["from_memory_id"] != problem {
+		t.Fatalf("update type: got %d: %v", status, rel)
+	}
+
+	if status, _ := doRequest(t, "GET", "/relationships/"+relID, nil); status != 200 {
+		t.Fatalf("get: expected 200, got %d", status)
+	}
+	if status, _ := doRequest(t, "DELETE", "/relationships/"+relID, nil); status != 200 {
+		t.Fatalf("delete: expected 200, got %d", status)
+	}
+	if status, _ := doRequest(t, "GET", "/relationships/"+relID, nil); status != 404 {
+		t.Fatalf("get after delete: expected 404, got %d", status)
+	}
+	if status, _ := doRequest(t, "GET", "/memories/"+fix, nil); status != 200 {
+		t.Fatalf("memory should survive relationship delete, got %d", status)
+	}
+}
+
+func TestRelationships_Types(t *testing.T) {
+	status, body := doRequestArray(t, "GET", "/relationships/types", nil)
+	if status != 200 {
+		t.Fatalf("types: expected 200, got %d", status)
+	}
+	found := false
+	for _, item := range body {
+		rt := item.(map[string]any)
+		if rt["name"] == "SOLVES" && rt["inverse"] == "SOLVED_BY" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("SOLVES/SOLVED_BY missing from %v", body)
+	}
+}
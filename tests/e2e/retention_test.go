@@ -0,0 +1,51 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import "testing"
+
+func TestRetentionRules_SetTTL(t *testing.T) {
+	status, before := doRequest(t, "GET", "/admin/retention", nil)
+	if status != 200 {
+		t.Fatalf("get retention rules failed: status=%d body=%v", status, before)
+	}
+	defer doRequest(t, "PUT", "/admin/retention", map[string]any{"rules": before["rules"]})
+
+	rules := []map[string]any{{"type": "conversation", "agent_source": "e2e-retention", "ttl": "1h"}}
+	status, body := doRequest(t, "PUT", "/admin/retention", map[string]any{"rules": rules})
+	if status != 200 {
+		t.Fatalf("set retention rules failed: status=%d body=%v", status, body)
+	}
+	if got := body["rules"].([]any); len(got) != 1 || got[0].(map[string]any)["ttl"] != "1h0m0s" {
+		t.Fatalf("unexpected rules: %v", body["rules"])
+	}
+
+	status, body = doRequest(t, "POST", "/memories", map[string]any{
+		"title":        "Retention rule memory",
+		"content":      "A conversation note that should get the rule's one hour TTL.",
+		"type":         "conversation",
+		"project_id":   uniqueProject(),
+		"importance":   0.3,
+		"agent_source": "e2e-retention",
+	})
+	if status != 201 {
+		t.Fatalf("store memory failed: status=%d body=%v", status, body)
+	}
+	mem := body["memory"].(map[string]any)
+	defer deleteMemory(t, mem["id"].(string))
+	if mem["ttl_seconds"] != float64(3600) {
+		t.Fatalf("expected ttl_seconds 3600 from the retention rule, got %v", mem["ttl_seconds"])
+	}
+}
+
+func TestRetentionRules_Invalid(t *testing.T) {
+	rules := []map[string]any{{"type": "conversation"}}
+	if status, _ := doRequest(t, "PUT", "/admin/retention", map[string]any{"rules": rules}); status != 400 {
+		t.Fatalf("expected 400 for a rule that sets nothing, got %d", status)
+	}
+	rules = []map[string]any{{"type": "nonsense", "max_count": 10}}
+	if status, _ := doRequest(t, "PUT", "/admin/retention", map[string]any{"rules": rules}); status != 400 {
+		t.Fatalf("expected 400 for an unknown type, got %d", status)
+	}
+}
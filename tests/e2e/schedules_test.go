@@ -0,0 +1,79 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"testing"
+	"time"
+)
+
+func findSchedule(t *testing.T, name string) map[string]any {
+	t.Helper()
+	status, body := doRequest(t, "GET", "/admin/schedules", nil)
+	if status != 200 {
+		t.Fatalf("list schedules failed: status=%d body=%v", status, body)
+	}
+	for _, j := range body["jobs"].([]any) {
+		if job := j.(map[string]any); job["name"] == name {
+			return job
+		}
+	}
+	t.Fatalf("job %s not listed: %v", name, body["jobs"])
+	return nil
+}
+
+func TestSchedules_PauseResume(t *testing.T) {
+	if job := findSchedule(t, "cleanup"); job["schedule"] == "" {
+		t.Fatalf("expected cleanup to have a schedule: %v", job)
+	}
+
+	status, body := doRequest(t, "POST", "/admin/schedules", map[string]any{"name": "cleanup", "action": "pause"})
+	if status != 200 || body["paused"] != true {
+		t.Fatalf("pause failed: status=%d body=%v", status, body)
+	}
+	defer doRequest(t, "POST", "/admin/schedules", map[string]any{"name": "cleanup", "action": "resume"})
+	if job := findSchedule(t, "cleanup"); job["paused"] != true {
+		t.Fatalf("expected cleanup to be listed as paused: %v", job)
+	}
+
+	status, body = doRequest(t, "POST", "/admin/schedules", map[string]any{"name": "cleanup", "action": "resume"})
+	if status != 200 || body["paused"] != false {
+		t.Fatalf("resume failed: status=%d body=%v", status, body)
+	}
+}
+
+func TestSchedules_Trigger(t *testing.T) {
+	before := findSchedule(t, "cleanup")["run_count"].(float64)
+
+	status, body := doRequest(t, "POST", "/admin/schedules", map[string]any{"name": "cleanup", "action": "trigger"})
+	if status != 202 {
+		t.Fatalf("trigger failed: status=%d body=%v", status, body)
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		job := findSchedule(t, "cleanup")
+		if job["run_count"].(float64) > before {
+			last := job["last_run"].(map[string]any)
+			if last["status"] != "succeeded" {
+				t.Fatalf("expected the triggered run to succeed: %v", last)
+			}
+			if _, ok := last["duration_ms"]; !ok {
+				t.Fatalf("expected the run's duration to be recorded: %v", last)
+			}
+			return
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatalf("triggered cleanup did not run within 30s")
+}
+
+func TestSchedules_Invalid(t *testing.T) {
+	if status, _ := doRequest(t, "POST", "/admin/schedules", map[string]any{"name": "nonsense", "action": "trigger"}); status != 404 {
+		t.Fatalf("expected 404 for an unknown job, got %d", status)
+	}
+	if status, _ := doRequest(t, "POST", "/admin/schedules", map[string]any{"name": "cleanup", "action": "explode"}); status != 400 {
+		t.Fatalf("expected 400 for an unknown action, got %d", status)
+	}
+}
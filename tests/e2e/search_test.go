@@ -0,0 +1,232 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch_RejectsUnknownRanking(t *testing.T) {
+	status, body := doRequest(t, "POST", "/memories/recall", map[string]any{"query": "anything", "ranking": "bm25"})
+	if status != 400 {
+		t.Fatalf("expected 400 for unknown ranking, got %d: %v", status, body)
+	}
+}
+
+func TestSearch_Explain(t *testing.T) {
+	project := uniqueProject()
+	result := storeMemory(t, "Explain pgbouncer", "Transaction pooling in pgbouncer breaks prepared statements; switch the driver to simple protocol.", project, 0.6)
+	id := result["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id)
+
+	status, results := doRequestArray(t, "POST", "/memories/recall", map[string]any{
+		"query":      "pgbouncer prepared statements",
+		"project_id": project,
+		"explain":    true,
+	})
+	if status != 200 || len(results) == 0 {
+		t.Fatalf("recall: status=%d results=%d", status, len(results))
+	}
+	top := results[0].(map[string]any)
+	if top["memory"].(map[string]any)["id"] != id {
+		t.Fatalf("expected the stored memory first, got %v", top["memory"])
+	}
+	exp, ok := top["explanation"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected an explanation, got %v", top)
+	}
+	if exp["keyword_rank"].(float64) != 1 || exp["vector_score"].(float64) <= 0 {
+		t.Fatalf("unexpected scores: %v", exp)
+	}
+	if exp["boosts"].(map[string]any)["project"].(float64) <= 0 {
+		t.Fatalf("expected a project boost: %v", exp["boosts"])
+	}
+	lexemes := exp["matched_lexemes"].([]any)
+	if len(lexemes) != 3 {
+		t.Fatalf("expected 3 matched lexemes, got %v", lexemes)
+	}
+	highlights := exp["highlights"].([]any)
+	if len(highlights) == 0 || !strings.Contains(highlights[0].(string), "<mark>") {
+		t.Fatalf("expected highlighted snippets, got %v", highlights)
+	}
+
+	_, results = doRequestArray(t, "POST", "/memories/recall", map[string]any{
+		"query":      "pgbouncer prepared statements",
+		"project_id": project,
+	})
+	if _, ok := results[0].(map[string]any)["explanation"]; ok {
+		t.Fatalf("explanation should only be returned on request")
+	}
+}
+
+func TestSearch_Filter(t *testing.T) {
+	project := uniqueProject()
+	result := storeMemory(t, "Filter sentry sampling", "Sentry traces_sample_rate above 0.2 doubled our ingest bill.", project, 0.6)
+	id := result["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id)
+
+	search := func(filter string) []any {
+		t.Helper()
+		status, results := doRequestArray(t, "POST", "/memories/search", map[string]any{
+			"query":      "sentry sampling",
+			"project_id": project,
+			"filter":     filter,
+		})
+		if status != 200 {
+			t.Fatalf("search with filter %q: status=%d", filter, status)
+		}
+		return results
+	}
+
+	if results := search("tag:consolidation,unrelated updated:1d access:<5"); len(results) != 1 {
+		t.Fatalf("expected the memory to match, got %d results", len(results))
+	}
+	if results := search("-tag:e2e-test"); len(results) != 0 {
+		t.Fatalf("negated tag should exclude the memory, got %d results", len(results))
+	}
+	if results := search("created:<2000-01-01"); len(results) != 0 {
+		t.Fatalf("date range should exclude the memory, got %d results", len(results))
+	}
+	if results := search("has:relationship"); len(results) != 0 {
+		t.Fatalf("memory without relationships should not match has:relationship, got %d results", len(results))
+	}
+
+	status, body := doRequest(t, "POST", "/memories/search", map[string]any{"query": "x", "filter": "color:blue"})
+	if status != 400 {
+		t.Fatalf("expected 400 for an unknown filter key, got %d: %v", status, body)
+	}
+}
+
+func TestSearch_CursorPagination(t *testing.T) {
+	project := uniqueProject()
+	for _, title := range []string{"Cursor kafka lag", "Cursor kafka rebalance", "Cursor kafka retention"} {
+		result := storeMemory(t, title, title+": notes about kafka consumer groups and partitions.", project, 0.5)
+		defer deleteMemory(t, result["memory"].(map[string]any)["id"].(string))
+	}
+
+	// The project: filter leaves out global memories that project_id admits.
+	req := map[string]any{"query": "kafka consumer", "filter": "project:" + project, "limit": 2, "paginate": true, "include_total": true}
+	status, first := doRequest(t, "POST", "/memories/search", req)
+	if status != 200 {
+		t.Fatalf("first page: status=%d body=%v", status, first)
+	}
+	if len(first["results"].([]any)) != 2 || first["next_cursor"] == nil || first["total_estimate"].(float64) != 3 {
+		t.Fatalf("unexpected first page: %v", first)
+	}
+
+	req["cursor"] = first["next_cursor"]
+	status, second := doRequest(t, "POST", "/memories/search", req)
+	if status != 200 {
+		t.Fatalf("second page: status=%d body=%v", status, second)
+	}
+	results := second["results"].([]any)
+	if len(results) != 1 || second["next_cursor"] != nil {
+		t.Fatalf("expected a last page of one, got %v", second)
+	}
+	lastID := results[0].(map[string]any)["memory"].(map[string]any)["id"]
+	for _, r := range first["results"].([]any) {
+		if r.(map[string]any)["memory"].(map[string]any)["id"] == lastID {
+			t.Fatalf("pages overlap on %v", lastID)
+		}
+	}
+
+	req["query"] = "something else"
+	if status, body := doRequest(t, "POST", "/memories/search", req); status != 400 {
+		t.Fatalf("expected 400 for a cursor from another search, got %d: %v", status, body)
+	}
+	req["cursor"] = "garbage"
+	if status, body := doRequest(t, "POST", "/memories/search", req); status != 400 {
+		t.Fatalf("expected 400 for an invalid cursor, got %d: %v", status, body)
+	}
+}
+
+func TestSearch_MemoryLanguage(t *testing.T) {
+	project := uniqueProject()
+	status, body := doRequest(t, "POST", "/memories", map[string]any{
+		"title":      "Önbellek zaman aşımı",
+		"content":    "Bağlantı havuzu dolduğu için önbellek istekleri zaman aşımına uğradı ve bu yüzden havuz boyutu artırıldı.",
+		"project_id": project,
+		"language":   "turkish",
+	})
+	if status != 201 {
+		t.Fatalf("store: status=%d body=%v", status, body)
+	}
+	mem := body["memory"].(map[string]any)
+	defer deleteMemory(t, mem["id"].(string))
+	if mem["language"] != "turkish" {
+		t.Fatalf("expected language turkish, got %v", mem["language"])
+	}
+
+	status, results := doRequestArray(t, "POST", "/memories/search", map[string]any{
+		"query":  "havuz boyutu",
+		"filter": "project:" + project + " lang:turkish",
+	})
+	if status != 200 || len(results) != 1 {
+		t.Fatalf("expected the memory to match, status=%d results=%d", status, len(results))
+	}
+
+	status, body = doRequest(t, "POST", "/memories", map[string]any{"title": "x", "content": "y", "language": "klingon"})
+	if status != 400 {
+		t.Fatalf("expected 400 for an unsupported language, got %d: %v", status, body)
+	}
+}
+
+func TestSearch_SymbolMode(t *testing.T) {
+	project := uniqueProject()
+	status, body := doRequest(t, "POST", "/memories", map[string]any{
+		"title":      "Candidate query for hybrid search",
+		"content":    "Repository.HybridSearchCandidates in internal/memory/repository.go builds the SQL.\n```go\nfunc buildSymbolIndex(ctx context.Context) error\n```",
+		"type":       "code_pattern",
+		"project_id": project,
+	})
+	if status != 201 {
+		t.Fatalf("store: status=%d body=%v", status, body)
+	}
+	defer deleteMemory(t, body["memory"].(map[string]any)["id"].(string))
+
+	search := func(query string) []any {
+		t.Helper()
+		status, results := doRequestArray(t, "POST", "/memories/search", map[string]any{
+			"query":   query,
+			"filter":  "project:" + project,
+			"explain": true,
+		})
+		if status != 200 {
+			t.Fatalf("search %q: status=%d", query, status)
+		}
+		return results
+	}
+
+	for _, query := range []string{
+		"symbol:Repository.HybridSearchCandidates",
+		"symbol:hybridsearchcandidates",
+		"symbol:internal/memory/repository.go",
+		"symbol:repository.go",
+		"symbol:buildSymbol*",
+	} {
+		results := search(query)
+		if len(results) != 1 {
+			t.Fatalf("%s: expected the memory to match, got %d results", query, len(results))
+		}
+		explanation := results[0].(map[string]any)["explanation"].(map[string]any)
+		if explanation["symbol_rank"].(float64) != 1 || len(explanation["matched_symbols"].([]any)) == 0 {
+			t.Fatalf("%s: expected a symbol match in the explanation, got %v", query, explanation)
+		}
+	}
+	if results := search("symbol:HybridSearchRanker"); len(results) != 0 {
+		t.Fatalf("symbol search should not return memories without the symbol, got %d", len(results))
+	}
+
+	// A plain query mentioning the identifier ranks it through the symbol signal.
+	results := search("where is HybridSearchCandidates defined")
+	if len(results) == 0 || results[0].(map[string]any)["explanation"].(map[string]any)["symbol_rank"].(float64) != 1 {
+		t.Fatalf("expected a symbol match for the plain query, got %v", results)
+	}
+
+	status, body = doRequest(t, "POST", "/memories/search", map[string]any{"query": "symbol:"})
+	if status != 400 {
+		t.Fatalf("expected 400 for an empty symbol search, got %d: %v", status, body)
+	}
+}
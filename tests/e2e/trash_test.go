@@ -0,0 +1,82 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"net/url"
+	"testing"
+)
+
+func trashContains(t *testing.T, project, id string) map[string]any {
+	t.Helper()
+	status, body := doRequest(t, "GET", "/trash?project_id="+url.QueryEscape(project), nil)
+	if status != 200 {
+		t.Fatalf("list trash failed: status=%d body=%v", status, body)
+	}
+	for _, m := range body["memories"].([]any) {
+		if m := m.(map[string]any); m["id"] == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestTrash_DeleteRestorePurge(t *testing.T) {
+	project := uniqueProject()
+
+	r := storeMemory(t, "Trashed memory", "Content that goes to the trash and back.", project, 0.5)
+	id := r["memory"].(map[string]any)["id"].(string)
+
+	if status, _ := doRequest(t, "DELETE", "/memories/"+id, nil); status != 200 {
+		t.Fatalf("delete failed: status=%d", status)
+	}
+	if status, _ := doRequest(t, "GET", "/memories/"+id, nil); status != 404 {
+		t.Fatalf("expected 404 for a trashed memory, got %d", status)
+	}
+	trashed := trashContains(t, project, id)
+	if trashed == nil {
+		t.Fatalf("deleted memory not listed in trash")
+	}
+	if trashed["deleted_reason"] != "deleted" || trashed["purge_at"] == nil {
+		t.Fatalf("unexpected trash entry: %v", trashed)
+	}
+
+	status, body := doRequest(t, "POST", "/trash/"+id+"/restore", nil)
+	if status != 200 {
+		t.Fatalf("restore failed: status=%d body=%v", status, body)
+	}
+	if body["id"] != id {
+		t.Fatalf("restore returned the wrong memory: %v", body)
+	}
+	getMemory(t, id)
+	if trashContains(t, project, id) != nil {
+		t.Fatalf("restored memory still listed in trash")
+	}
+
+	deleteMemory(t, id)
+	if status, body := doRequest(t, "DELETE", "/trash/"+id, nil); status != 200 {
+		t.Fatalf("purge failed: status=%d body=%v", status, body)
+	}
+	if status, _ := doRequest(t, "POST", "/trash/"+id+"/restore", nil); status != 404 {
+		t.Fatalf("expected 404 restoring a purged memory, got %d", status)
+	}
+}
+
+func TestTrash_InvalidReason(t *testing.T) {
+	if status, _ := doRequest(t, "GET", "/trash?reason=bogus", nil); status != 400 {
+		t.Fatalf("expected 400 for an unknown reason, got %d", status)
+	}
+}
+
+func TestTrash_PurgeNotInTrash(t *testing.T) {
+	project := uniqueProject()
+
+	r := storeMemory(t, "Live memory", "This memory was never deleted.", project, 0.5)
+	id := r["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id)
+
+	if status, _ := doRequest(t, "DELETE", "/trash/"+id, nil); status != 404 {
+		t.Fatalf("expected 404 purging a live memory, got %d", status)
+	}
+}
@@ -0,0 +1,72 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemoryVersions_UpdateDiffRestore(t *testing.T) {
+	project := uniqueProject()
+
+	r := storeMemory(t, "Versioned memory", "Original content for version history.", project, 0.5)
+	id := r["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id)
+
+	status, _ := doRequest(t, "PUT", "/memories/"+id, map[string]any{"content": "Edited content for version history."})
+	if status != 200 {
+		t.Fatalf("update failed: status=%d", status)
+	}
+
+	status, body := doRequest(t, "GET", "/memories/"+id+"/versions", nil)
+	if status != 200 {
+		t.Fatalf("list versions failed: status=%d body=%v", status, body)
+	}
+	if total := body["total"].(float64); total != 2 {
+		t.Fatalf("expected 2 versions after create+update, got %v", total)
+	}
+	latest := body["versions"].([]any)[0].(map[string]any)
+	if latest["change_reason"] != "update" || latest["version_no"].(float64) != 2 {
+		t.Fatalf("unexpected latest version: %v", latest)
+	}
+
+	status, body = doRequest(t, "GET", "/memories/"+id+"/versions/diff?from=1&to=2", nil)
+	if status != 200 {
+		t.Fatalf("diff failed: status=%d body=%v", status, body)
+	}
+	diff := body["diff"].(string)
+	if !strings.Contains(diff, "-Original content") || !strings.Contains(diff, "+Edited content") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+
+	status, body = doRequest(t, "POST", "/memories/"+id+"/restore/1", nil)
+	if status != 200 {
+		t.Fatalf("restore failed: status=%d body=%v", status, body)
+	}
+	if body["content"] != "Original content for version history." {
+		t.Fatalf("expected original content after restore, got %v", body["content"])
+	}
+
+	_, body = doRequest(t, "GET", "/memories/"+id+"/versions?limit=1", nil)
+	restored := body["versions"].([]any)[0].(map[string]any)
+	if restored["change_reason"] != "restore:v1" || restored["version_no"].(float64) != 3 {
+		t.Fatalf("expected restore recorded as version 3, got %v", restored)
+	}
+}
+
+func TestMemoryVersions_NotFound(t *testing.T) {
+	project := uniqueProject()
+
+	r := storeMemory(t, "Versioned memory 404", "Content.", project, 0.5)
+	id := r["memory"].(map[string]any)["id"].(string)
+	defer deleteMemory(t, id)
+
+	if status, _ := doRequest(t, "POST", "/memories/"+id+"/restore/99", nil); status != 404 {
+		t.Fatalf("expected 404 for unknown version, got %d", status)
+	}
+	if status, _ := doRequest(t, "GET", "/memories/00000000-0000-0000-0000-000000000000/versions", nil); status != 404 {
+		t.Fatalf("expected 404 for unknown memory, got %d", status)
+	}
+}
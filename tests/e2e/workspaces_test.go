@@ -0,0 +1,168 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// Requires a server started with AUTH_ENABLED=true and the same bootstrap
+// token in CONTEXTIFY_E2E_BOOTSTRAP_TOKEN; skipped otherwise.
+
+func doAuthRequest(t *testing.T, token, method, path string, body any) (int, map[string]any) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, baseURL+path, reader)
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	var result map[string]any
+	json.Unmarshal(raw, &result)
+
+	return resp.StatusCode, result
+}
+
+func createWorkspaceToken(t *testing.T, bootstrap, workspace string) string {
+	t.Helper()
+	status, result := doAuthRequest(t, bootstrap, "POST", "/admin/tokens", map[string]any{
+		"name":      "e2e-" + workspace,
+		"scope":     "admin",
+		"workspace": workspace,
+	})
+	if status != 201 {
+		t.Fatalf("create token for %s: expected 201, got %d: %v", workspace, status, result)
+	}
+	tok := result["token"].(map[string]any)
+	t.Cleanup(func() {
+		doAuthRequest(t, bootstrap, "DELETE", "/admin/tokens/"+tok["id"].(string), nil)
+	})
+	return result["raw_token"].(string)
+}
+
+func TestWorkspaceIsolation(t *testing.T) {
+	bootstrap := os.Getenv("CONTEXTIFY_E2E_BOOTSTRAP_TOKEN")
+	if bootstrap == "" {
+		t.Skip("CONTEXTIFY_E2E_BOOTSTRAP_TOKEN not set")
+	}
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	tokenA := createWorkspaceToken(t, bootstrap, "e2e-a-"+suffix)
+	tokenB := createWorkspaceToken(t, bootstrap, "e2e-b-"+suffix)
+
+	title := "Workspace isolation marker " + suffix
+	status, result := doAuthRequest(t, tokenA, "POST", "/memories", map[string]any{
+		"title":      title,
+		"content":    "Only team A may see this global memory " + suffix,
+		"type":       "general",
+		"scope":      "global",
+		"importance": 0.9,
+	})
+	if status != 201 {
+		t.Fatalf("store: expected 201, got %d: %v", status, result)
+	}
+	id := result["memory"].(map[string]any)["id"].(string)
+	defer doAuthRequest(t, tokenA, "DELETE", "/memories/"+id, nil)
+
+	if status, _ := doAuthRequest(t, tokenA, "GET", "/memories/"+id, nil); status != 200 {
+		t.Fatalf("owner get: expected 200, got %d", status)
+	}
+	if status, _ := doAuthRequest(t, tokenB, "GET", "/memories/"+id, nil); status != 404 {
+		t.Fatalf("other workspace get: expected 404, got %d", status)
+	}
+	if status, _ := doAuthRequest(t, tokenB, "DELETE", "/memories/"+id, nil); status != 404 {
+		t.Fatalf("other workspace delete: expected 404, got %d", status)
+	}
+
+	req, _ := json.Marshal(map[string]any{"query": title, "limit": 20})
+	for name, token := range map[string]string{"A": tokenA, "B": tokenB} {
+		httpReq, _ := http.NewRequest("POST", baseURL+"/memories/recall", bytes.NewReader(req))
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			t.Fatalf("recall %s: %v", name, err)
+		}
+		var results []map[string]any
+		json.NewDecoder(resp.Body).Decode(&results)
+		resp.Body.Close()
+
+		found := false
+		for _, r := range results {
+			if r["memory"].(map[string]any)["id"] == id {
+				found = true
+			}
+		}
+		if want := name == "A"; found != want {
+			t.Errorf("workspace %s recall: found=%v, want %v", name, found, want)
+		}
+	}
+
+	status, result = doAuthRequest(t, tokenA, "POST", "/admin/tokens", map[string]any{
+		"name":      "cross-workspace",
+		"scope":     "read",
+		"workspace": "e2e-b-" + suffix,
+	})
+	if status != 403 {
+		t.Fatalf("cross-workspace token: expected 403, got %d: %v", status, result)
+	}
+}
+
+func TestInstanceOperations_ForbiddenToWorkspaceAdmin(t *testing.T) {
+	bootstrap := os.Getenv("CONTEXTIFY_E2E_BOOTSTRAP_TOKEN")
+	if bootstrap == "" {
+		t.Skip("CONTEXTIFY_E2E_BOOTSTRAP_TOKEN not set")
+	}
+	token := createWorkspaceToken(t, bootstrap, fmt.Sprintf("e2e-inst-%d", time.Now().UnixNano()))
+
+	calls := []struct {
+		method, path string
+		body         any
+	}{
+		{"POST", "/admin/normalize-projects", nil},
+		{"POST", "/admin/reembed", nil},
+		{"POST", "/admin/reembed/pause", nil},
+		{"GET", "/admin/schedules", nil},
+		{"POST", "/admin/schedules", map[string]any{"name": "cleanup", "action": "pause"}},
+		{"PUT", "/steward/mode", map[string]any{"paused": true}},
+		{"POST", "/steward/run-once", nil},
+	}
+	for _, c := range calls {
+		if status, result := doAuthRequest(t, token, c.method, c.path, c.body); status != 403 {
+			t.Errorf("%s %s as workspace admin: expected 403, got %d: %v", c.method, c.path, status, result)
+		}
+	}
+
+	status, result := doAuthRequest(t, token, "POST", "/admin/tokens", map[string]any{"name": "escalate", "scope": "instance"})
+	if status != 403 {
+		t.Fatalf("instance token from workspace admin: expected 403, got %d: %v", status, result)
+	}
+
+	if status, result := doAuthRequest(t, bootstrap, "GET", "/admin/schedules", nil); status != 200 {
+		t.Fatalf("schedules as bootstrap: expected 200, got %d: %v", status, result)
+	}
+}